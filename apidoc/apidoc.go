@@ -0,0 +1,619 @@
+// Package apidoc renders self-contained API reference documentation of OpenAPI 3.0 and 3.1 specs in HTML and Markdown.
+//
+// Documents do not depend on external assets, so they can be built and published without network access.
+package apidoc
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/convert"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Document is a model of API reference, schemas of parameters and bodies are resolved into trees.
+type Document struct {
+	Title       string
+	Version     string
+	Description string
+	Servers     []Server
+
+	// Groups contain operations grouped by tags in order of spec tags, an operation with several tags
+	// is listed in each of their groups. Operations without tags are in the last group with empty name.
+	Groups []Group
+
+	// Schemas are component schemas sorted by name.
+	Schemas         []Component
+	SecuritySchemes []SecurityScheme
+}
+
+// Component is a component schema, Schema.Ref is the name of component.
+type Component struct {
+	// Anchor is a unique identifier of component in document, e.g. "schema-Pet".
+	Anchor string
+
+	Name   string
+	Schema *Schema
+}
+
+// Server is an API server.
+type Server struct {
+	URL         string
+	Description string
+}
+
+// Group is a list of operations of a tag.
+type Group struct {
+	// Anchor is a unique identifier of group in document, e.g. "tag-pets".
+	Anchor string
+
+	Name        string
+	Description string
+	Operations  []Operation
+}
+
+// Operation describes an operation.
+type Operation struct {
+	// Anchor is a unique identifier of operation in document, e.g. "operation-listPets".
+	Anchor string
+
+	ID          string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Deprecated  bool
+	Tags        []string
+
+	// Parameters contain path item and operation parameters.
+	Parameters  []Parameter
+	RequestBody *RequestBody
+	Responses   []Response
+
+	// Security lists alternative requirements, operation without requirements does not need authentication.
+	Security []SecurityRequirement
+}
+
+// Parameter describes an operation parameter or a response header.
+type Parameter struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Deprecated  bool
+	Schema      *Schema
+	Examples    []Example
+}
+
+// RequestBody describes a request body.
+type RequestBody struct {
+	Description string
+	Required    bool
+	Content     []Content
+}
+
+// Response describes a response of a status code, Status is "default" for default response.
+type Response struct {
+	Status      string
+	Description string
+	Headers     []Parameter
+	Content     []Content
+}
+
+// Content describes a body of a media type.
+type Content struct {
+	MediaType string
+	Schema    *Schema
+	Examples  []Example
+}
+
+// Example is a named example with value formatted as indented JSON.
+type Example struct {
+	Name          string
+	Summary       string
+	Description   string
+	Value         string
+	ExternalValue string
+}
+
+// methodOrder is an order of operations of a path.
+var methodOrder = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+	http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace,
+}
+
+// NewDocument creates a document model of the spec.
+func NewDocument(s openapi.SpecSchema) (*Document, error) {
+	spec, err := convert.AsOpenAPI31(s)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := openapi31.NewRefResolver(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	b := builder{
+		spec:     spec,
+		resolver: resolver,
+		anchors:  map[string]bool{},
+
+		securityAnchors: map[string]string{},
+	}
+
+	return b.document()
+}
+
+type builder struct {
+	spec     *openapi31.Spec
+	resolver *openapi31.RefResolver
+
+	// anchors contains used anchors of document.
+	anchors map[string]bool
+
+	// securityAnchors maps names of security schemes to anchors.
+	securityAnchors map[string]string
+}
+
+func (b *builder) document() (*Document, error) {
+	info := b.spec.Info
+
+	d := &Document{
+		Title:       info.Title,
+		Version:     info.Version,
+		Description: str(info.Description),
+	}
+
+	for _, srv := range b.spec.Servers {
+		d.Servers = append(d.Servers, Server{URL: srv.URL, Description: str(srv.Description)})
+	}
+
+	var err error
+
+	if d.SecuritySchemes, err = b.securitySchemes(); err != nil {
+		return nil, err
+	}
+
+	operations, err := b.operations()
+	if err != nil {
+		return nil, err
+	}
+
+	d.Groups = b.groups(operations)
+	d.Schemas = b.components()
+
+	return d, nil
+}
+
+func (b *builder) operations() ([]Operation, error) {
+	if b.spec.Paths == nil {
+		return nil, nil
+	}
+
+	var res []Operation
+
+	for _, path := range internal.SortedMapKeys(b.spec.Paths.MapOfPathItemValues) {
+		pi := b.spec.Paths.MapOfPathItemValues[path]
+
+		for _, method := range methodOrder {
+			op, err := pi.Operation(method)
+			if err != nil {
+				return nil, err
+			}
+
+			if op == nil {
+				continue
+			}
+
+			o, err := b.operation(method, path, pi, op)
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, o)
+		}
+	}
+
+	return res, nil
+}
+
+func (b *builder) operation(method, path string, pi openapi31.PathItem, op *openapi31.Operation) (Operation, error) {
+	o := Operation{
+		ID:          str(op.ID),
+		Method:      method,
+		Path:        path,
+		Summary:     str(op.Summary),
+		Description: str(op.Description),
+		Deprecated:  op.Deprecated != nil && *op.Deprecated,
+		Tags:        op.Tags,
+		Security:    b.security(op.Security),
+	}
+
+	if o.ID != "" {
+		o.Anchor = b.anchor("operation", o.ID)
+	} else {
+		o.Anchor = b.anchor("operation", strings.ToLower(method)+path)
+	}
+
+	var err error
+
+	if o.Parameters, err = b.parameters(append(pi.Parameters, op.Parameters...)); err != nil {
+		return o, err
+	}
+
+	if o.RequestBody, err = b.requestBody(op.RequestBody); err != nil {
+		return o, err
+	}
+
+	if o.Responses, err = b.responses(op.Responses); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// components makes trees of component schemas.
+func (b *builder) components() []Component {
+	if b.spec.Components == nil {
+		return nil
+	}
+
+	res := make([]Component, 0, len(b.spec.Components.Schemas))
+
+	for _, name := range internal.SortedMapKeys(b.spec.Components.Schemas) {
+		res = append(res, Component{
+			Anchor: b.anchor("schema", name),
+			Name:   name,
+			Schema: b.schema(map[string]interface{}{"$ref": componentSchemaPrefix + internal.EscapeJSONPointer(name)}),
+		})
+	}
+
+	return res
+}
+
+// groups distributes operations by tags.
+func (b *builder) groups(operations []Operation) []Group {
+	var (
+		res      []Group
+		untagged []Operation
+	)
+
+	byTag := map[string]int{}
+
+	add := func(name, description string) {
+		byTag[name] = len(res)
+		res = append(res, Group{Anchor: b.anchor("tag", name), Name: name, Description: description})
+	}
+
+	for _, t := range b.spec.Tags {
+		add(t.Name, str(t.Description))
+	}
+
+	for _, o := range operations {
+		if len(o.Tags) == 0 {
+			untagged = append(untagged, o)
+		}
+
+		for i, tag := range o.Tags {
+			if _, ok := byTag[tag]; !ok {
+				add(tag, "")
+			}
+
+			// Copies of operation in other groups have their own anchors.
+			if i > 0 {
+				o.Anchor = b.anchor(o.Anchor, "")
+			}
+
+			res[byTag[tag]].Operations = append(res[byTag[tag]].Operations, o)
+		}
+	}
+
+	if len(untagged) > 0 {
+		res = append(res, Group{Anchor: b.anchor("tag", "other"), Operations: untagged})
+	}
+
+	// Declared tags without operations are omitted.
+	groups := res[:0]
+
+	for _, g := range res {
+		if len(g.Operations) > 0 {
+			groups = append(groups, g)
+		}
+	}
+
+	return groups
+}
+
+func (b *builder) parameters(params []openapi31.ParameterOrReference) ([]Parameter, error) {
+	var res []Parameter
+
+	byKey := map[string]int{}
+
+	for _, pr := range params {
+		p := pr.Parameter
+
+		if pr.Reference != nil {
+			p = &openapi31.Parameter{}
+
+			if err := b.resolver.Resolve(pr.Reference.Ref, p); err != nil {
+				return nil, err
+			}
+		}
+
+		if p == nil {
+			continue
+		}
+
+		prm := Parameter{
+			Name:        p.Name,
+			In:          string(p.In),
+			Description: str(p.Description),
+			Required:    p.In == openapi31.ParameterInPath || (p.Required != nil && *p.Required),
+			Deprecated:  p.Deprecated != nil && *p.Deprecated,
+			Schema:      b.schema(contentSchema(p.Schema, p.Content)),
+		}
+
+		examples, err := b.examples(p.Example, p.Examples)
+		if err != nil {
+			return nil, err
+		}
+
+		prm.Examples = examples
+
+		// Operation parameters override path item parameters with the same name and location.
+		key := prm.In + "/" + prm.Name
+		if i, ok := byKey[key]; ok {
+			res[i] = prm
+
+			continue
+		}
+
+		byKey[key] = len(res)
+		res = append(res, prm)
+	}
+
+	return res, nil
+}
+
+func (b *builder) requestBody(rbr *openapi31.RequestBodyOrReference) (*RequestBody, error) {
+	if rbr == nil {
+		return nil, nil
+	}
+
+	rb := rbr.RequestBody
+
+	if rbr.Reference != nil {
+		rb = &openapi31.RequestBody{}
+
+		if err := b.resolver.Resolve(rbr.Reference.Ref, rb); err != nil {
+			return nil, err
+		}
+	}
+
+	if rb == nil {
+		return nil, nil
+	}
+
+	content, err := b.content(rb.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestBody{
+		Description: str(rb.Description),
+		Required:    rb.Required != nil && *rb.Required,
+		Content:     content,
+	}, nil
+}
+
+func (b *builder) responses(rs *openapi31.Responses) ([]Response, error) {
+	if rs == nil {
+		return nil, nil
+	}
+
+	statuses := internal.SortedMapKeys(rs.MapOfResponseOrReferenceValues)
+	all := make(map[string]openapi31.ResponseOrReference, len(statuses)+1)
+
+	for k, v := range rs.MapOfResponseOrReferenceValues {
+		all[k] = v
+	}
+
+	if rs.Default != nil {
+		statuses = append(statuses, "default")
+		all["default"] = *rs.Default
+	}
+
+	res := make([]Response, 0, len(statuses))
+
+	for _, status := range statuses {
+		rr := all[status]
+		r := rr.Response
+
+		if rr.Reference != nil {
+			r = &openapi31.Response{}
+
+			if err := b.resolver.Resolve(rr.Reference.Ref, r); err != nil {
+				return nil, err
+			}
+		}
+
+		if r == nil {
+			continue
+		}
+
+		resp := Response{Status: status, Description: r.Description}
+
+		for _, name := range internal.SortedMapKeys(r.Headers) {
+			h, err := b.header(name, r.Headers[name])
+			if err != nil {
+				return nil, err
+			}
+
+			resp.Headers = append(resp.Headers, h)
+		}
+
+		content, err := b.content(r.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Content = content
+		res = append(res, resp)
+	}
+
+	return res, nil
+}
+
+func (b *builder) header(name string, hr openapi31.HeaderOrReference) (Parameter, error) {
+	h := hr.Header
+
+	if hr.Reference != nil {
+		h = &openapi31.Header{}
+
+		if err := b.resolver.Resolve(hr.Reference.Ref, h); err != nil {
+			return Parameter{}, err
+		}
+	}
+
+	if h == nil {
+		h = &openapi31.Header{}
+	}
+
+	examples, err := b.examples(h.Example, h.Examples)
+	if err != nil {
+		return Parameter{}, err
+	}
+
+	return Parameter{
+		Name:        name,
+		In:          string(openapi31.ParameterInHeader),
+		Description: str(h.Description),
+		Required:    h.Required != nil && *h.Required,
+		Deprecated:  h.Deprecated != nil && *h.Deprecated,
+		Schema:      b.schema(contentSchema(h.Schema, h.Content)),
+		Examples:    examples,
+	}, nil
+}
+
+func (b *builder) content(content map[string]openapi31.MediaType) ([]Content, error) {
+	res := make([]Content, 0, len(content))
+
+	for _, ct := range internal.SortedMapKeys(content) {
+		mt := content[ct]
+
+		examples, err := b.examples(mt.Example, mt.Examples)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, Content{MediaType: ct, Schema: b.schema(mt.Schema), Examples: examples})
+	}
+
+	return res, nil
+}
+
+func (b *builder) examples(example *interface{}, examples map[string]openapi31.ExampleOrReference) ([]Example, error) {
+	var res []Example
+
+	if example != nil {
+		res = append(res, Example{Value: formatValue(*example)})
+	}
+
+	for _, name := range internal.SortedMapKeys(examples) {
+		er := examples[name]
+		e := er.Example
+
+		if er.Reference != nil {
+			e = &openapi31.Example{}
+
+			if err := b.resolver.Resolve(er.Reference.Ref, e); err != nil {
+				return nil, err
+			}
+		}
+
+		if e == nil {
+			continue
+		}
+
+		ex := Example{
+			Name:          name,
+			Summary:       str(e.Summary),
+			Description:   str(e.Description),
+			ExternalValue: str(e.ExternalValue),
+		}
+
+		if e.Value != nil {
+			ex.Value = formatValue(*e.Value)
+		}
+
+		res = append(res, ex)
+	}
+
+	return res, nil
+}
+
+// contentSchema returns schema of parameter or header, that can be defined with content instead of schema.
+func contentSchema(schema map[string]interface{}, content map[string]openapi31.MediaType) map[string]interface{} {
+	if schema == nil && len(content) > 0 {
+		return content[internal.SortedMapKeys(content)[0]].Schema
+	}
+
+	return schema
+}
+
+// anchor makes a unique anchor of name, e.g. "operation-listPets" for "listPets".
+func (b *builder) anchor(prefix, name string) string {
+	var s strings.Builder
+
+	s.WriteString(prefix)
+
+	dash := true
+
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			if dash {
+				s.WriteByte('-')
+			}
+
+			s.WriteRune(r)
+
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	res := s.String()
+	anchor := res
+
+	for i := 2; b.anchors[anchor]; i++ {
+		anchor = res + "-" + strconv.Itoa(i)
+	}
+
+	b.anchors[anchor] = true
+
+	return anchor
+}
+
+// formatValue formats value as indented JSON.
+func formatValue(v interface{}) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
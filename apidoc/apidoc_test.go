@@ -0,0 +1,237 @@
+package apidoc_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go/apidoc"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func loadPetstore(t *testing.T) *openapi31.Spec {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/petstore.yaml")
+	require.NoError(t, err)
+
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML(data))
+
+	return &s
+}
+
+func assertGenerated(t *testing.T, fileName string, generated []byte) {
+	t.Helper()
+
+	expected, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(generated))
+}
+
+func TestHTML(t *testing.T) {
+	html, err := apidoc.HTML(loadPetstore(t), apidoc.HTMLOptions{})
+	require.NoError(t, err)
+
+	assertGenerated(t, "testdata/petstore.html", html)
+	assert.NotContains(t, string(html), "http://")
+	assert.NotContains(t, string(html), "<script")
+}
+
+func TestHTML_options(t *testing.T) {
+	html, err := apidoc.HTML(loadPetstore(t), apidoc.HTMLOptions{
+		Title: "Pets </title>",
+		Style: "body { color: red; }",
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(html), "<title>Pets &lt;/title&gt;</title>")
+	assert.Contains(t, string(html), "<style>\nbody { color: red; }\n</style>")
+}
+
+func TestNewDocument(t *testing.T) {
+	d, err := apidoc.NewDocument(loadPetstore(t))
+	require.NoError(t, err)
+
+	var groups []string
+	for _, g := range d.Groups {
+		groups = append(groups, g.Anchor)
+	}
+
+	// Declared tag without operations is omitted.
+	assert.Equal(t, []string{"tag-pets", "tag-photos", "tag-admin", "tag-other"}, groups)
+
+	pets := d.Groups[0]
+	require.Len(t, pets.Operations, 4)
+	assert.Equal(t, "operation-delete-pets-petId", pets.Operations[3].Anchor)
+	assert.True(t, pets.Operations[3].Deprecated)
+
+	// Operation with several tags is listed in each group with unique anchor.
+	assert.Equal(t, "operation-createPet", pets.Operations[1].Anchor)
+	assert.Equal(t, "operation-createPet-2", d.Groups[2].Operations[0].Anchor)
+
+	list := pets.Operations[0]
+	require.Len(t, list.Parameters, 3)
+	assert.Equal(t, "X-Request-ID", list.Parameters[2].Name)
+	assert.True(t, list.Parameters[2].Required)
+	assert.Equal(t, "10", list.Parameters[0].Examples[0].Value)
+	assert.Equal(t, "integer (int32)", list.Parameters[0].Schema.TypeName())
+	assert.Equal(t, "20", list.Parameters[0].Schema.Default)
+
+	require.Len(t, list.Responses, 2)
+	assert.Equal(t, "200", list.Responses[0].Status)
+	assert.Equal(t, "X-Next", list.Responses[0].Headers[0].Name)
+	assert.Equal(t, "Pets", list.Responses[0].Content[0].Schema.TypeName())
+	assert.Equal(t, "default", list.Responses[1].Status)
+	assert.Equal(t, "Error.", list.Responses[1].Description)
+
+	pet := list.Responses[0].Content[0].Schema.Items
+	require.Len(t, pet.AllOf, 2)
+
+	owner := pet.AllOf[1].Properties[2]
+	assert.Equal(t, "owner", owner.Name)
+	assert.Equal(t, "Owner | null", owner.TypeName())
+	assert.Equal(t, "Owner of pet.", owner.Description)
+	assert.Len(t, owner.Properties, 2)
+
+	// Recursive reference is not expanded.
+	categories := d.Groups[3].Operations[0].Responses[0].Content[0].Schema
+	assert.Equal(t, "array of Category", categories.TypeName())
+
+	parent := categories.Items.Properties[1]
+	assert.Equal(t, "Category", parent.Ref)
+	assert.True(t, parent.Recursive)
+	assert.False(t, parent.HasChildren())
+
+	require.Len(t, d.Schemas, 7)
+	assert.Equal(t, "schema-Category", d.Schemas[0].Anchor)
+	assert.Equal(t, "Category", d.Schemas[0].Schema.Ref)
+	assert.True(t, d.Schemas[0].Schema.Properties[1].Recursive)
+}
+
+func TestNewDocument_security(t *testing.T) {
+	d, err := apidoc.NewDocument(loadPetstore(t))
+	require.NoError(t, err)
+
+	require.Len(t, d.SecuritySchemes, 2)
+	assert.Equal(t, apidoc.SecurityScheme{
+		Anchor:        "security-apiKey",
+		Name:          "apiKey",
+		Type:          "apiKey",
+		Description:   "Key of | client.",
+		In:            "header",
+		ParameterName: "X-API-Key",
+	}, d.SecuritySchemes[0])
+	assert.Equal(t, []apidoc.OAuthFlow{{
+		Type:             "authorizationCode",
+		AuthorizationURL: "https://auth.example.com/authorize",
+		TokenURL:         "https://auth.example.com/token",
+		Scopes:           []apidoc.Scope{{Name: "pets:read", Description: "Read pets."}, {Name: "pets:write", Description: "Modify pets."}},
+	}}, d.SecuritySchemes[1].Flows)
+
+	// Spec requirements are inherited.
+	assert.Equal(t, []apidoc.SecurityRequirement{
+		{Schemes: []apidoc.RequiredScheme{{Anchor: "security-apiKey", Name: "apiKey", Scopes: []string{}}}},
+		{Schemes: []apidoc.RequiredScheme{{Anchor: "security-oauth", Name: "oauth", Scopes: []string{"pets:read"}}}},
+	}, d.Groups[0].Operations[0].Security)
+
+	// Empty requirement allows anonymous access.
+	assert.Equal(t, []apidoc.SecurityRequirement{
+		{},
+		{Schemes: []apidoc.RequiredScheme{
+			{Anchor: "security-apiKey", Name: "apiKey", Scopes: []string{}},
+			{Anchor: "security-oauth", Name: "oauth", Scopes: []string{"pets:read", "pets:write"}},
+		}},
+	}, d.Groups[0].Operations[1].Security)
+
+	// Empty list disables security.
+	assert.Empty(t, d.Groups[3].Operations[0].Security)
+}
+
+func TestMarkdown(t *testing.T) {
+	files, err := apidoc.Markdown(loadPetstore(t), apidoc.MarkdownOptions{})
+	require.NoError(t, err)
+
+	expected, err := filepath.Glob("testdata/markdown/*.md")
+	require.NoError(t, err)
+
+	var names []string
+
+	for _, fn := range expected {
+		name := filepath.Base(fn)
+		names = append(names, name)
+
+		assertGenerated(t, fn, files[name])
+	}
+
+	assert.Len(t, files, len(names))
+	assert.Equal(t, []string{"admin.md", "index.md", "other.md", "pets.md", "photos.md", "schemas.md"}, names)
+}
+
+func TestMarkdown_singleFile(t *testing.T) {
+	files, err := apidoc.Markdown(loadPetstore(t), apidoc.MarkdownOptions{SingleFile: true})
+	require.NoError(t, err)
+
+	require.Len(t, files, 1)
+	assertGenerated(t, "testdata/markdown-single/index.md", files["index.md"])
+}
+
+func TestMarkdown_templates(t *testing.T) {
+	files, err := apidoc.Markdown(loadPetstore(t), apidoc.MarkdownOptions{
+		SingleFile: true,
+		Templates: `{{define "operation"}}
+{{h 3}} {{.Method}} {{.Path}}
+{{end}}
+{{define "schemas"}}See [schemas](https://example.com/schemas).{{end}}`,
+	})
+	require.NoError(t, err)
+
+	index := string(files["index.md"])
+	assert.Contains(t, index, "\n### GET /pets\n\n### POST /pets\n")
+	assert.NotContains(t, index, "Operation ID")
+	assert.NotContains(t, index, "## Schemas")
+	assert.True(t, strings.HasSuffix(index, "\n\nSee [schemas](https://example.com/schemas).\n"))
+	assert.Contains(t, index, "## Security schemes")
+
+	_, err = apidoc.Markdown(loadPetstore(t), apidoc.MarkdownOptions{Templates: `{{define "group"}}{{.Unknown}}{{end}}`})
+	require.Error(t, err)
+}
+
+func TestHTML_openapi3(t *testing.T) {
+	s := openapi3.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.0.3
+info: {title: Legacy, version: 0.1.0}
+paths:
+  /items:
+    get:
+      summary: List items.
+      responses:
+        "200":
+          description: Items.
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Item'}
+components:
+  schemas:
+    Item:
+      type: object
+      properties:
+        name: {type: string, nullable: true}
+`)))
+
+	html, err := apidoc.HTML(&s, apidoc.HTMLOptions{})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(html), `<li><a href="#operation-get-items"><span class="method get">GET</span> /items</a></li>`)
+	assert.Contains(t, string(html), `<span class="type">array of Item</span>`)
+	assert.Contains(t, string(html), `<li><code>name</code> <span class="type">string | null</span></li>`)
+	assert.Equal(t, 1, strings.Count(string(html), "<article"))
+}
@@ -0,0 +1,90 @@
+package apidoc
+
+import (
+	"bytes"
+	_ "embed" // Embedded templates.
+	"html/template"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+var (
+	//go:embed html.tmpl
+	htmlTemplate string
+
+	//go:embed style.css
+	defaultStyle string
+)
+
+// HTMLOptions configures HTML rendering.
+type HTMLOptions struct {
+	// Title is a title of page, title of spec by default.
+	Title string
+
+	// Style is a CSS that replaces default style of page.
+	Style string
+}
+
+// HTML renders spec as a self-contained HTML page with operations grouped by tags,
+// tables of parameters, resolved schema trees and examples.
+//
+// Descriptions are rendered as plain text paragraphs, Markdown formatting is not interpreted.
+func HTML(s openapi.SpecSchema, options HTMLOptions) ([]byte, error) {
+	d, err := NewDocument(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.HTML(options)
+}
+
+// HTML renders document as a self-contained HTML page.
+func (d *Document) HTML(options HTMLOptions) ([]byte, error) {
+	t, err := template.New("html").Funcs(template.FuncMap{
+		"lower":      strings.ToLower,
+		"paragraphs": paragraphs,
+		"inc":        func(i int) int { return i + 1 },
+	}).Parse(htmlTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Title == "" {
+		options.Title = d.Title
+	}
+
+	if options.Style == "" {
+		options.Style = defaultStyle
+	}
+
+	var b bytes.Buffer
+
+	err = t.Execute(&b, struct {
+		*Document
+		PageTitle string
+		Style     template.CSS
+	}{
+		Document:  d,
+		PageTitle: options.Title,
+		Style:     template.CSS(options.Style), //nolint:gosec // Style is provided by developer.
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// paragraphs splits text into paragraphs separated by empty lines.
+func paragraphs(text string) []string {
+	var res []string
+
+	for _, p := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
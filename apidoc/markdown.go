@@ -0,0 +1,390 @@
+package apidoc
+
+import (
+	"bytes"
+	_ "embed" // Embedded templates.
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/swaggest/openapi-go"
+)
+
+//go:embed markdown.tmpl
+var markdownTemplate string
+
+// MarkdownOptions configures Markdown rendering.
+type MarkdownOptions struct {
+	// SingleFile renders document into a single "index.md" file instead of a file per tag.
+	SingleFile bool
+
+	// Templates redefine default templates, e.g. `{{define "operation"}}...{{end}}`.
+	//
+	// Templates are parsed with text/template after default templates of markdown.tmpl, that has
+	// "single" template for SingleFile mode, and "index", "group" and "schemas" templates for files
+	// of multi-file mode. Data of top-level templates is *Document, data of "group" is Group.
+	Templates string
+}
+
+// Markdown renders spec as Markdown files with operation summaries, tables of parameters and schemas
+// with links to sections of component schemas, and security requirements.
+//
+// Result maps file names to contents. Document is rendered into "index.md" with overview and security schemes,
+// a file per tag, e.g. "pets.md", and "schemas.md" with component schemas, or into a single "index.md".
+func Markdown(s openapi.SpecSchema, options MarkdownOptions) (map[string][]byte, error) {
+	d, err := NewDocument(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Markdown(options)
+}
+
+// Markdown renders document as Markdown files.
+func (d *Document) Markdown(options MarkdownOptions) (map[string][]byte, error) {
+	r := markdownRenderer{
+		doc:        d,
+		single:     options.SingleFile,
+		groupFiles: map[string]string{},
+		opFiles:    map[string]string{},
+		schemas:    map[string]string{},
+	}
+
+	t, err := r.template(options.Templates)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.single {
+		data, err := r.execute(t, "single", d, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string][]byte{"index.md": data}, nil
+	}
+
+	files := make(map[string][]byte, len(d.Groups)+2)
+
+	if files["index.md"], err = r.execute(t, "index", d, 0); err != nil {
+		return nil, err
+	}
+
+	for _, g := range d.Groups {
+		if files[r.groupFiles[g.Anchor]], err = r.execute(t, "group", g, -1); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(d.Schemas) > 0 {
+		if files["schemas.md"], err = r.execute(t, "schemas", d, -1); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+type markdownRenderer struct {
+	doc    *Document
+	single bool
+
+	// offset is added to heading levels of executed template.
+	offset int
+
+	// groupFiles and opFiles map anchors to file names, schemas map names of component schemas to anchors.
+	groupFiles map[string]string
+	opFiles    map[string]string
+	schemas    map[string]string
+}
+
+func (r *markdownRenderer) template(templates string) (*template.Template, error) {
+	files := map[string]bool{"index": true, "schemas": true}
+
+	for _, g := range r.doc.Groups {
+		name := strings.TrimPrefix(g.Anchor, "tag-")
+		file := name + ".md"
+
+		for i := 2; files[strings.TrimSuffix(file, ".md")]; i++ {
+			file = fmt.Sprintf("%s-%d.md", name, i)
+		}
+
+		files[strings.TrimSuffix(file, ".md")] = true
+		r.groupFiles[g.Anchor] = file
+
+		for _, o := range g.Operations {
+			r.opFiles[o.Anchor] = file
+		}
+	}
+
+	for _, c := range r.doc.Schemas {
+		r.schemas[c.Name] = c.Anchor
+	}
+
+	t, err := template.New("markdown").Funcs(template.FuncMap{
+		"h":             r.heading,
+		"groupLink":     r.groupLink,
+		"operationLink": r.operationLink,
+		"securityLink":  r.securityLink,
+		"type":          r.typeName,
+		"baseType":      r.baseType,
+		"fields":        fields,
+		"details":       details,
+		"cell":          cell,
+		"code":          code,
+		"join":          strings.Join,
+	}).Parse(markdownTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	if templates != "" {
+		if t, err = t.Parse(templates); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+var regexBlankLines = regexp.MustCompile(`\n{3,}`)
+
+func (r *markdownRenderer) execute(t *template.Template, name string, data interface{}, offset int) ([]byte, error) {
+	var b bytes.Buffer
+
+	r.offset = offset
+
+	if err := t.ExecuteTemplate(&b, name, data); err != nil {
+		return nil, err
+	}
+
+	res := regexBlankLines.ReplaceAll(bytes.TrimSpace(b.Bytes()), []byte("\n\n"))
+
+	return append(res, '\n'), nil
+}
+
+// heading returns Markdown heading prefix of level, e.g. "##" for 2.
+func (r *markdownRenderer) heading(level int) string {
+	level += r.offset
+	if level < 1 {
+		level = 1
+	}
+
+	return strings.Repeat("#", level)
+}
+
+func (r *markdownRenderer) link(file, anchor string) string {
+	if r.single {
+		return "#" + anchor
+	}
+
+	return file + "#" + anchor
+}
+
+func (r *markdownRenderer) groupLink(g Group) string {
+	if r.single {
+		return "#" + g.Anchor
+	}
+
+	return r.groupFiles[g.Anchor]
+}
+
+func (r *markdownRenderer) operationLink(o Operation) string {
+	return r.link(r.opFiles[o.Anchor], o.Anchor)
+}
+
+func (r *markdownRenderer) securityLink(s RequiredScheme) string {
+	return r.link("index.md", s.Anchor)
+}
+
+// typeName returns type of schema with links to component schemas, e.g. "array of [Pet](schemas.md#schema-Pet)".
+func (r *markdownRenderer) typeName(s *Schema) string {
+	if s == nil {
+		return ""
+	}
+
+	if anchor, ok := r.schemas[s.Ref]; ok {
+		res := "[" + s.Ref + "](" + r.link("schemas.md", anchor) + ")"
+
+		if s.Nullable {
+			res += " \\| null"
+		}
+
+		return res
+	}
+
+	if s.Ref == "" && s.Type == "array" && s.Items != nil {
+		res := "array of " + r.typeName(s.Items)
+
+		if s.Nullable {
+			res += " \\| null"
+		}
+
+		return res
+	}
+
+	return cell(s.TypeName())
+}
+
+// baseType returns type of schema without name of component, e.g. "object" for component schema.
+func (r *markdownRenderer) baseType(s *Schema) string {
+	cp := *s
+	cp.Ref = ""
+
+	return r.typeName(&cp)
+}
+
+// field is a row of schema table.
+type field struct {
+	// Name is a path of field, e.g. "owner.name", "tags[]" or "labels.*".
+	Name   string
+	Schema *Schema
+}
+
+// fields flattens schema tree into rows, references to component schemas are not expanded.
+func fields(s *Schema) []field {
+	var res []field
+
+	var walk func(prefix string, s *Schema)
+
+	walk = func(prefix string, s *Schema) {
+		add := func(name string, child *Schema) {
+			res = append(res, field{Name: name, Schema: child})
+
+			if child.Ref == "" {
+				walk(name, child)
+			}
+		}
+
+		for _, p := range s.Properties {
+			add(join(prefix, p.Name), p)
+		}
+
+		if s.Items != nil {
+			add(prefix+"[]", s.Items)
+		}
+
+		if s.AdditionalProperties != nil {
+			add(join(prefix, "*"), s.AdditionalProperties)
+		}
+
+		variants := func(kind string, items []*Schema) {
+			for i, v := range items {
+				label := fmt.Sprintf("(%s #%d)", kind, i+1)
+				if prefix != "" {
+					label = prefix + " " + label
+				}
+
+				res = append(res, field{Name: label, Schema: v})
+
+				// Properties of variants are properties of parent.
+				if v.Ref == "" {
+					walk(prefix, v)
+				}
+			}
+		}
+
+		variants("all of", s.AllOf)
+		variants("any of", s.AnyOf)
+		variants("one of", s.OneOf)
+
+		if s.Not != nil {
+			res = append(res, field{Name: join(prefix, "(not)"), Schema: s.Not})
+		}
+	}
+
+	if s != nil {
+		walk("", s)
+	}
+
+	return res
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
+
+// details describes schema with constraints, e.g. "Name of pet. Min length: `1`.".
+func details(description string, s *Schema) string {
+	var parts []string
+
+	if description != "" {
+		parts = append(parts, description)
+	}
+
+	if s == nil {
+		return strings.Join(parts, " ")
+	}
+
+	if s.ReadOnly {
+		parts = append(parts, "Read only.")
+	}
+
+	if s.WriteOnly {
+		parts = append(parts, "Write only.")
+	}
+
+	if s.Deprecated {
+		parts = append(parts, "Deprecated.")
+	}
+
+	if s.Recursive {
+		parts = append(parts, "Recursive.")
+	}
+
+	if len(s.Enum) > 0 {
+		parts = append(parts, "Enum: "+codes(s.Enum)+".")
+	}
+
+	if s.Default != "" {
+		parts = append(parts, "Default: "+code(s.Default)+".")
+	}
+
+	for _, c := range s.Constraints {
+		parts = append(parts, c.Name+": "+code(c.Value)+".")
+	}
+
+	if len(s.Examples) > 0 {
+		parts = append(parts, "Examples: "+codes(s.Examples)+".")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func codes(values []string) string {
+	res := make([]string, 0, len(values))
+
+	for _, v := range values {
+		res = append(res, code(v))
+	}
+
+	return strings.Join(res, ", ")
+}
+
+// code makes inline code span, delimiter is longer than backtick sequences of value.
+func code(s string) string {
+	delim := "`"
+	for strings.Contains(s, delim) {
+		delim += "`"
+	}
+
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+
+	return delim + s + delim
+}
+
+// cell escapes text for a table cell.
+func cell(s string) string {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\n\n", "<br><br>")
+	s = strings.ReplaceAll(s, "\n", " ")
+
+	return strings.ReplaceAll(s, "|", "\\|")
+}
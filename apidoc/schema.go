@@ -0,0 +1,422 @@
+package apidoc
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// Schema is a node of resolved schema tree.
+//
+// References are replaced with referenced schemas, Ref keeps the name of component schema.
+// A reference to a component that is already expanded by a parent node is not expanded again
+// to stop recursion, such node has Recursive flag and no children.
+type Schema struct {
+	// Name is a name of property in parent object schema.
+	Name string
+
+	// Ref is a name of component schema.
+	Ref       string
+	Recursive bool
+
+	// Type is a type of schema without "null", empty for schemas of any type, e.g. "integer" or "string | number".
+	Type   string
+	Format string
+
+	Title       string
+	Description string
+	Required    bool
+	Nullable    bool
+	ReadOnly    bool
+	WriteOnly   bool
+	Deprecated  bool
+
+	// Enum, Default and Examples are formatted as JSON, e.g. `"available"`.
+	Enum     []string
+	Default  string
+	Examples []string
+
+	// Constraints are validation keywords of schema, e.g. "minimum" or "pattern".
+	Constraints []Constraint
+
+	Properties           []*Schema
+	Items                *Schema
+	AdditionalProperties *Schema
+	AllOf                []*Schema
+	AnyOf                []*Schema
+	OneOf                []*Schema
+	Not                  *Schema
+}
+
+// Constraint is a validation keyword with formatted value.
+type Constraint struct {
+	Name  string
+	Value string
+}
+
+// TypeName returns a short description of schema type, e.g. "array of Pet" or "integer (int64) | null",
+// schema without type is described by composition keyword, e.g. "one of", or as "any".
+func (s *Schema) TypeName() string {
+	res := s.Ref
+
+	if res == "" {
+		res = s.Type
+
+		if res == "array" && s.Items != nil {
+			res += " of " + s.Items.TypeName()
+		} else if s.Format != "" {
+			res += " (" + s.Format + ")"
+		}
+	}
+
+	if res == "" {
+		switch {
+		case len(s.AllOf) > 0:
+			res = "all of"
+		case len(s.AnyOf) > 0:
+			res = "any of"
+		case len(s.OneOf) > 0:
+			res = "one of"
+		default:
+			res = "any"
+		}
+	}
+
+	if s.Nullable {
+		res += " | null"
+	}
+
+	return res
+}
+
+// HasChildren is true if schema has nested schemas.
+func (s *Schema) HasChildren() bool {
+	return len(s.Properties) > 0 || s.Items != nil || s.AdditionalProperties != nil ||
+		len(s.AllOf) > 0 || len(s.AnyOf) > 0 || len(s.OneOf) > 0 || s.Not != nil
+}
+
+// constraintKeywords are schema keywords that are listed as constraints.
+var constraintKeywords = []string{
+	"const", "multipleOf", "minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum",
+	"minLength", "maxLength", "pattern", "minItems", "maxItems", "uniqueItems",
+	"minProperties", "maxProperties", "contentEncoding", "contentMediaType",
+}
+
+const componentSchemaPrefix = "#/components/schemas/"
+
+// schema builds a tree of schema, nil schema results in nil tree.
+func (b *builder) schema(s map[string]interface{}) *Schema {
+	if s == nil {
+		return nil
+	}
+
+	return b.node(s, nil)
+}
+
+// node builds a tree of schema, stack contains names of component schemas that are being expanded.
+func (b *builder) node(s map[string]interface{}, stack []string) *Schema {
+	res := &Schema{}
+
+	s, ref, ok := b.deref(s)
+	if ref != "" {
+		res.Ref = ref
+
+		for _, name := range stack {
+			if name == ref {
+				res.Recursive = true
+
+				return res
+			}
+		}
+
+		stack = append(stack[:len(stack):len(stack)], ref)
+	}
+
+	if !ok {
+		return res
+	}
+
+	s, res.Nullable = nonNull(s)
+
+	// Nullable reference, e.g. {"anyOf": [{"type": "null"}, {"$ref": "#/components/schemas/Pet"}]},
+	// or a component that refers to another component.
+	if _, isRef := s["$ref"]; isRef {
+		n := b.node(s, stack)
+		n.Nullable = n.Nullable || res.Nullable
+
+		if res.Ref != "" {
+			n.Ref = res.Ref
+		}
+
+		return n
+	}
+
+	res.Type = schemaType(s)
+	res.Format, _ = s["format"].(string)           //nolint:errcheck // Empty is valid.
+	res.Title, _ = s["title"].(string)             //nolint:errcheck // Empty is valid.
+	res.Description, _ = s["description"].(string) //nolint:errcheck // Empty is valid.
+	res.ReadOnly = s["readOnly"] == true
+	res.WriteOnly = s["writeOnly"] == true
+	res.Deprecated = s["deprecated"] == true
+
+	if enum, ok := s["enum"].([]interface{}); ok {
+		for _, v := range enum {
+			res.Enum = append(res.Enum, compactValue(v))
+		}
+	}
+
+	if v, ok := s["default"]; ok {
+		res.Default = compactValue(v)
+	}
+
+	if examples, ok := s["examples"].([]interface{}); ok {
+		for _, v := range examples {
+			res.Examples = append(res.Examples, compactValue(v))
+		}
+	}
+
+	if v, ok := s["example"]; ok {
+		res.Examples = append(res.Examples, compactValue(v))
+	}
+
+	for _, k := range constraintKeywords {
+		v, ok := s[k]
+		if !ok {
+			continue
+		}
+
+		value, isString := v.(string)
+		if !isString || k == "const" {
+			value = compactValue(v)
+		}
+
+		res.Constraints = append(res.Constraints, Constraint{Name: k, Value: value})
+	}
+
+	b.children(res, s, stack)
+
+	return res
+}
+
+// children adds nested schemas to node.
+func (b *builder) children(res *Schema, s map[string]interface{}, stack []string) {
+	required := map[string]bool{}
+
+	if req, ok := s["required"].([]interface{}); ok {
+		for _, v := range req {
+			if name, ok := v.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	if props, ok := s["properties"].(map[string]interface{}); ok {
+		for _, name := range internal.SortedKeys(props) {
+			ps, ok := asSchema(props[name])
+			if !ok {
+				continue
+			}
+
+			p := b.node(ps, stack)
+			p.Name = name
+			p.Required = required[name]
+
+			res.Properties = append(res.Properties, p)
+		}
+	}
+
+	if items, ok := asSchema(s["items"]); ok {
+		res.Items = b.node(items, stack)
+	}
+
+	if ap, ok := asSchema(s["additionalProperties"]); ok {
+		res.AdditionalProperties = b.node(ap, stack)
+	}
+
+	if not, ok := asSchema(s["not"]); ok {
+		res.Not = b.node(not, stack)
+	}
+
+	res.AllOf = b.variants(s["allOf"], stack)
+	res.AnyOf = b.variants(s["anyOf"], stack)
+	res.OneOf = b.variants(s["oneOf"], stack)
+}
+
+func (b *builder) variants(v interface{}, stack []string) []*Schema {
+	items, _ := v.([]interface{}) //nolint:errcheck // Nil is valid.
+
+	var res []*Schema
+
+	for _, item := range items {
+		if s, ok := asSchema(item); ok {
+			res = append(res, b.node(s, stack))
+		}
+	}
+
+	return res
+}
+
+// deref follows schema reference, it returns referenced schema with properties of reference applied,
+// name of component schema and false if reference can not be resolved.
+func (b *builder) deref(s map[string]interface{}) (map[string]interface{}, string, bool) {
+	name := ""
+
+	for i := 0; i < 16; i++ {
+		ref, ok := s["$ref"].(string)
+		if !ok {
+			return s, name, true
+		}
+
+		var target map[string]interface{}
+
+		if strings.HasPrefix(ref, componentSchemaPrefix) {
+			name = internal.UnescapeJSONPointer(strings.TrimPrefix(ref, componentSchemaPrefix))
+
+			if b.spec.Components != nil {
+				target = b.spec.Components.Schemas[name]
+			}
+		} else if err := b.resolver.Resolve(ref, &target); err != nil {
+			target = nil
+		}
+
+		if target == nil {
+			return s, name, false
+		}
+
+		// Properties next to reference override properties of referenced schema.
+		merged := make(map[string]interface{}, len(target)+len(s))
+
+		for k, v := range target {
+			merged[k] = v
+		}
+
+		for k, v := range s {
+			if k != "$ref" {
+				merged[k] = v
+			}
+		}
+
+		s = merged
+
+		// Nested reference of a component is expanded by node to check recursion.
+		if name != "" {
+			return s, name, true
+		}
+	}
+
+	return s, name, false
+}
+
+// nonNull removes "null" from schema types, it returns true if "null" was removed.
+func nonNull(s map[string]interface{}) (map[string]interface{}, bool) {
+	for _, key := range []string{"anyOf", "oneOf"} {
+		variants, ok := s[key].([]interface{})
+		if !ok || len(variants) != 2 {
+			continue
+		}
+
+		for i, v := range variants {
+			if vs, ok := v.(map[string]interface{}); ok && vs["type"] == "null" && len(vs) == 1 {
+				other, _ := variants[1-i].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+				res := make(map[string]interface{}, len(s)+len(other))
+
+				for k, v := range s {
+					if k != key {
+						res[k] = v
+					}
+				}
+
+				for k, v := range other {
+					res[k] = v
+				}
+
+				return res, true
+			}
+		}
+	}
+
+	types, ok := s["type"].([]interface{})
+	if !ok {
+		return s, false
+	}
+
+	var (
+		nullable bool
+		rest     []interface{}
+	)
+
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+		} else {
+			rest = append(rest, t)
+		}
+	}
+
+	if !nullable || len(rest) == 0 {
+		return s, false
+	}
+
+	res := make(map[string]interface{}, len(s))
+	for k, v := range s {
+		res[k] = v
+	}
+
+	res["type"] = rest
+
+	return res, true
+}
+
+// schemaType returns types of schema joined with " | ", types are inferred from keywords if not set.
+func schemaType(s map[string]interface{}) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		types := make([]string, 0, len(t))
+
+		for _, v := range t {
+			if name, ok := v.(string); ok {
+				types = append(types, name)
+			}
+		}
+
+		return strings.Join(types, " | ")
+	}
+
+	if _, ok := s["properties"]; ok {
+		return "object"
+	}
+
+	if _, ok := s["items"]; ok {
+		return "array"
+	}
+
+	return ""
+}
+
+// asSchema returns schema of value, true boolean schema is an empty schema, false is not a schema.
+func asSchema(v interface{}) (map[string]interface{}, bool) {
+	switch s := v.(type) {
+	case map[string]interface{}:
+		return s, true
+	case bool:
+		if s {
+			return map[string]interface{}{}, true
+		}
+	}
+
+	return nil, false
+}
+
+// compactValue formats value as JSON.
+func compactValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
@@ -0,0 +1,159 @@
+package apidoc
+
+import (
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// SecurityScheme describes a security scheme of components.
+type SecurityScheme struct {
+	// Anchor is a unique identifier of security scheme in document, e.g. "security-apiKey".
+	Anchor string
+
+	Name        string
+	Type        string
+	Description string
+
+	// In and ParameterName locate API key, e.g. "header" and "X-API-Key".
+	In            string
+	ParameterName string
+
+	// Scheme and BearerFormat describe HTTP authentication, e.g. "bearer" and "JWT".
+	Scheme       string
+	BearerFormat string
+
+	OpenIDConnectURL string
+	Flows            []OAuthFlow
+}
+
+// OAuthFlow describes OAuth 2 flow, Type is one of "implicit", "password", "clientCredentials"
+// or "authorizationCode".
+type OAuthFlow struct {
+	Type             string
+	AuthorizationURL string
+	TokenURL         string
+	RefreshURL       string
+	Scopes           []Scope
+}
+
+// Scope is an OAuth 2 scope.
+type Scope struct {
+	Name        string
+	Description string
+}
+
+// SecurityRequirement lists security schemes that are all required to call an operation,
+// requirement without schemes allows anonymous access.
+type SecurityRequirement struct {
+	Schemes []RequiredScheme
+}
+
+// RequiredScheme is a security scheme of requirement with scopes.
+type RequiredScheme struct {
+	// Anchor is an anchor of security scheme, empty if scheme is not defined in components.
+	Anchor string
+
+	Name   string
+	Scopes []string
+}
+
+// oauthFlowTypes is an order of OAuth 2 flows.
+var oauthFlowTypes = []string{"implicit", "password", "clientCredentials", "authorizationCode"}
+
+func (b *builder) securitySchemes() ([]SecurityScheme, error) {
+	if b.spec.Components == nil {
+		return nil, nil
+	}
+
+	schemes := b.spec.Components.SecuritySchemes
+	res := make([]SecurityScheme, 0, len(schemes))
+
+	for _, name := range internal.SortedMapKeys(schemes) {
+		var (
+			ss  = schemes[name]
+			doc map[string]interface{}
+			err error
+		)
+
+		if ss.Reference != nil {
+			err = b.resolver.Resolve(ss.Reference.Ref, &doc)
+		} else {
+			doc, err = internal.ToDocument(ss)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		s := SecurityScheme{
+			Anchor:           b.anchor("security", name),
+			Name:             name,
+			Type:             docString(doc, "type"),
+			Description:      docString(doc, "description"),
+			In:               docString(doc, "in"),
+			ParameterName:    docString(doc, "name"),
+			Scheme:           docString(doc, "scheme"),
+			BearerFormat:     docString(doc, "bearerFormat"),
+			OpenIDConnectURL: docString(doc, "openIdConnectUrl"),
+		}
+
+		flows, _ := doc["flows"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+		for _, typ := range oauthFlowTypes {
+			f, ok := flows[typ].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			flow := OAuthFlow{
+				Type:             typ,
+				AuthorizationURL: docString(f, "authorizationUrl"),
+				TokenURL:         docString(f, "tokenUrl"),
+				RefreshURL:       docString(f, "refreshUrl"),
+			}
+
+			scopes, _ := f["scopes"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+			for _, scope := range internal.SortedKeys(scopes) {
+				flow.Scopes = append(flow.Scopes, Scope{Name: scope, Description: docString(scopes, scope)})
+			}
+
+			s.Flows = append(s.Flows, flow)
+		}
+
+		b.securityAnchors[name] = s.Anchor
+		res = append(res, s)
+	}
+
+	return res, nil
+}
+
+// security makes requirements of operation, operation without security inherits requirements of spec.
+func (b *builder) security(requirements []map[string][]string) []SecurityRequirement {
+	if requirements == nil {
+		requirements = b.spec.Security
+	}
+
+	res := make([]SecurityRequirement, 0, len(requirements))
+
+	for _, req := range requirements {
+		r := SecurityRequirement{}
+
+		for _, name := range internal.SortedMapKeys(req) {
+			r.Schemes = append(r.Schemes, RequiredScheme{
+				Anchor: b.securityAnchors[name],
+				Name:   name,
+				Scopes: req[name],
+			})
+		}
+
+		res = append(res, r)
+	}
+
+	return res
+}
+
+func docString(doc map[string]interface{}, key string) string {
+	s, _ := doc[key].(string) //nolint:errcheck // Empty is valid.
+
+	return s
+}
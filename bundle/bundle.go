@@ -0,0 +1,371 @@
+// Package bundle assembles OpenAPI documents split across multiple files into a single document.
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// ErrUnsupportedReference is returned for references that can not be loaded from file system, e.g. URLs.
+var ErrUnsupportedReference = errors.New("unsupported reference")
+
+// Load reads root document by name from file system and bundles external references into a self-contained spec.
+//
+// Relative references to other files (e.g. "common/errors.yaml#/components/schemas/Error") are followed
+// in any object position. Referenced objects are added to components of the root document under the name
+// of the last reference token (or file name for whole-file references) with a numeric suffix in case of
+// collision, and references are rewritten to point to them. Path items are inlined, because they can not
+// be defined in components of OpenAPI 3.0.
+//
+// Result is *openapi3.Spec or *openapi31.Spec depending on "openapi" version of the root document.
+func Load(fsys fs.FS, name string) (openapi.SpecSchema, error) {
+	b := bundler{
+		fsys:     fsys,
+		root:     path.Clean(name),
+		files:    map[string]interface{}{},
+		refs:     map[string]string{},
+		inlining: map[string]bool{},
+	}
+
+	v, err := b.load(b.root)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: document must be an object", b.root)
+	}
+
+	b.doc = doc
+
+	if err := b.adoptComponents(); err != nil {
+		return nil, err
+	}
+
+	if _, err := b.walk(doc, stDocument, b.root); err != nil {
+		return nil, err
+	}
+
+	version, _ := doc["openapi"].(string) //nolint:errcheck // Empty version is reported below.
+
+	var s openapi.SpecSchema
+
+	switch {
+	case strings.HasPrefix(version, "3.0."):
+		s = &openapi3.Spec{}
+	case strings.HasPrefix(version, "3.1."):
+		s = &openapi31.Spec{}
+	default:
+		return nil, fmt.Errorf("%s: unsupported OpenAPI version %q", b.root, version)
+	}
+
+	if err := internal.FromDocument(doc, s); err != nil {
+		return nil, fmt.Errorf("loading bundled document: %w", err)
+	}
+
+	return s, nil
+}
+
+type bundler struct {
+	fsys fs.FS
+	root string
+	doc  map[string]interface{}
+
+	// files contains loaded documents by path.
+	files map[string]interface{}
+
+	// refs maps external references ("file#pointer") to local references of bundled components.
+	refs map[string]string
+
+	// inlining contains external references that are being inlined.
+	inlining map[string]bool
+}
+
+func (b *bundler) load(name string) (interface{}, error) {
+	if v, ok := b.files[name]; ok {
+		return v, nil
+	}
+
+	data, err := fs.ReadFile(b.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := internal.DecodeDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	b.files[name] = v
+
+	return v, nil
+}
+
+// walk rewrites external references in a value of a file, st describes position of the value in the document.
+func (b *bundler) walk(v interface{}, st state, file string) (interface{}, error) {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := vv["$ref"].(string); ok && !st.isCollection() {
+			return b.ref(vv, ref, st, file)
+		}
+
+		for _, k := range internal.SortedKeys(vv) {
+			nst := st.next(k)
+			if nst == stLiteral {
+				continue
+			}
+
+			rv, err := b.walk(vv[k], nst, file)
+			if err != nil {
+				return nil, err
+			}
+
+			vv[k] = rv
+		}
+	case []interface{}:
+		for i, item := range vv {
+			rv, err := b.walk(item, st.next(strconv.Itoa(i)), file)
+			if err != nil {
+				return nil, err
+			}
+
+			vv[i] = rv
+		}
+	}
+
+	return v, nil
+}
+
+// adoptComponents replaces components of root document that refer to other files with referenced values,
+// so that references to the same values are bundled under existing names.
+func (b *bundler) adoptComponents() error {
+	components, ok := b.doc["components"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	type adopted struct {
+		items       map[string]interface{}
+		name        string
+		st          state
+		ref         string
+		target, ptr string
+	}
+
+	var list []adopted
+
+	for _, section := range internal.SortedKeys(components) {
+		st := stComponents.next(section).next("")
+		items, ok := components[section].(map[string]interface{})
+
+		if !ok || st.section() == "" {
+			continue
+		}
+
+		for _, name := range internal.SortedKeys(items) {
+			obj, ok := items[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			ref, ok := obj["$ref"].(string)
+			if !ok || strings.HasPrefix(ref, "#") {
+				continue
+			}
+
+			target, ptr, err := b.target(ref, b.root)
+			if err != nil {
+				return err
+			}
+
+			key := target + "#" + ptr
+			if _, ok := b.refs[key]; ok {
+				continue // Another component refers to the same value and stays a reference.
+			}
+
+			b.refs[key] = "#/components/" + section + "/" + internal.EscapeJSONPointer(name)
+			list = append(list, adopted{items: items, name: name, st: st, ref: ref, target: target, ptr: ptr})
+		}
+	}
+
+	for _, a := range list {
+		value, err := b.resolve(a.target, a.ptr, b.root, a.ref)
+		if err != nil {
+			return err
+		}
+
+		if a.items[a.name], err = b.walk(value, a.st, a.target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// target returns file path and pointer of a reference in a file.
+func (b *bundler) target(ref, file string) (string, string, error) {
+	target, ptr := ref, ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		target, ptr = ref[:i], ref[i+1:]
+	}
+
+	if strings.Contains(target, "://") || path.IsAbs(target) {
+		return "", "", fmt.Errorf("%s: %w: %s", file, ErrUnsupportedReference, ref)
+	}
+
+	if target == "" {
+		return file, ptr, nil
+	}
+
+	return path.Join(path.Dir(file), target), ptr, nil
+}
+
+// resolve returns a copy of referenced value.
+func (b *bundler) resolve(target, ptr, file, ref string) (interface{}, error) {
+	doc, err := b.load(target)
+	if err != nil {
+		return nil, fmt.Errorf("%s: resolving %s: %w", file, ref, err)
+	}
+
+	value, err := internal.ResolvePointer(doc, "#"+ptr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: resolving %s: %w", file, ref, err)
+	}
+
+	return clone(value), nil
+}
+
+func (b *bundler) ref(obj map[string]interface{}, ref string, st state, file string) (interface{}, error) {
+	if file == b.root && strings.HasPrefix(ref, "#") {
+		return obj, nil
+	}
+
+	target, ptr, err := b.target(ref, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if target == b.root {
+		obj["$ref"] = "#" + ptr
+
+		return obj, nil
+	}
+
+	key := target + "#" + ptr
+
+	if local, ok := b.refs[key]; ok {
+		obj["$ref"] = local
+
+		return obj, nil
+	}
+
+	value, err := b.resolve(target, ptr, file, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	section := st.section()
+	if section == "" {
+		return b.inline(obj, key, value, st, target)
+	}
+
+	components, _ := b.doc["components"].(map[string]interface{}) //nolint:errcheck // Nil is replaced.
+	if components == nil {
+		components = map[string]interface{}{}
+		b.doc["components"] = components
+	}
+
+	items, _ := components[section].(map[string]interface{}) //nolint:errcheck // Nil is replaced.
+	if items == nil {
+		items = map[string]interface{}{}
+		components[section] = items
+	}
+
+	name := componentName(target, ptr)
+	for i := 2; items[name] != nil; i++ {
+		name = componentName(target, ptr) + strconv.Itoa(i)
+	}
+
+	local := "#/components/" + section + "/" + internal.EscapeJSONPointer(name)
+	b.refs[key] = local
+	items[name] = map[string]interface{}{} // Reserving the name for recursive references.
+
+	if items[name], err = b.walk(value, st, target); err != nil {
+		return nil, err
+	}
+
+	obj["$ref"] = local
+
+	return obj, nil
+}
+
+// inline replaces reference object with referenced value, siblings of "$ref" override properties of the value.
+func (b *bundler) inline(obj map[string]interface{}, key string, value interface{}, st state, file string) (interface{}, error) {
+	if b.inlining[key] {
+		return nil, fmt.Errorf("%w: %s", openapi.ErrCircularReference, key)
+	}
+
+	b.inlining[key] = true
+	value, err := b.walk(value, st, file)
+	delete(b.inlining, key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if m, ok := value.(map[string]interface{}); ok {
+		for k, v := range obj {
+			if k != "$ref" {
+				m[k] = v
+			}
+		}
+	}
+
+	return value, nil
+}
+
+// componentName returns a name of bundled component by the last token of reference pointer or by file name.
+func componentName(file, ptr string) string {
+	name := ""
+
+	if i := strings.LastIndex(ptr, "/"); i >= 0 {
+		name = internal.UnescapeJSONPointer(ptr[i+1:])
+	}
+
+	if name == "" {
+		name = strings.TrimSuffix(path.Base(file), path.Ext(file))
+	}
+
+	return internal.SanitizeComponentName(name)
+}
+
+func clone(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(vv))
+		for k, v := range vv {
+			res[k] = clone(v)
+		}
+
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(vv))
+		for i, v := range vv {
+			res[i] = clone(v)
+		}
+
+		return res
+	default:
+		return v
+	}
+}
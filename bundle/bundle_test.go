@@ -0,0 +1,197 @@
+package bundle_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/bundle"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"api/openapi.yaml": {Data: []byte(`
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    $ref: paths/pets.yaml
+components:
+  parameters:
+    limit:
+      $ref: common/parameters.yaml#/limit
+  schemas:
+    Error:
+      type: string
+`)},
+		"api/paths/pets.yaml": {Data: []byte(`
+get:
+  parameters:
+    - $ref: ../common/parameters.yaml#/limit
+  responses:
+    "200":
+      description: OK
+      content:
+        application/json:
+          schema:
+            type: array
+            items: {$ref: ../schemas/pet.yaml}
+    default:
+      $ref: ../common/errors.yaml#/components/responses/Error
+`)},
+		"api/common/parameters.yaml": {Data: []byte(`
+limit: {name: limit, in: query, schema: {type: integer}}
+`)},
+		"api/common/errors.yaml": {Data: []byte(`
+components:
+  responses:
+    Error:
+      description: Failure.
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Error'}
+  schemas:
+    Error:
+      type: object
+      properties:
+        message: {type: string}
+`)},
+		"api/schemas/pet.yaml": {Data: []byte(`
+type: object
+properties:
+  name: {type: string}
+  parent: {$ref: pet.yaml}
+  error: {$ref: ../common/errors.yaml#/components/schemas/Error}
+example: {$ref: not a reference}
+`)},
+	}
+
+	s, err := bundle.Load(fsys, "api/openapi.yaml")
+	require.NoError(t, err)
+	require.IsType(t, &openapi3.Spec{}, s)
+
+	assertjson.EqualMarshal(t, []byte(`{
+	  "openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+	  "paths":{
+		"/pets":{
+		  "get":{
+			"parameters":[{"$ref":"#/components/parameters/limit"}],
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/json":{"schema":{"type":"array","items":{"$ref":"#/components/schemas/pet"}}}
+				}
+			  },
+			  "default":{"$ref":"#/components/responses/Error"}
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Error":{"type":"string"},
+		  "Error2":{"type":"object","properties":{"message":{"type":"string"}}},
+		  "pet":{
+			"type":"object",
+			"properties":{
+			  "error":{"$ref":"#/components/schemas/Error2"},"name":{"type":"string"},
+			  "parent":{"$ref":"#/components/schemas/pet"}
+			},
+			"example":{"$ref":"not a reference"}
+		  }
+		},
+		"responses":{
+		  "Error":{
+			"description":"Failure.",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Error2"}}}
+		  }
+		},
+		"parameters":{"limit":{"name":"limit","in":"query","schema":{"type":"integer"}}}
+	  }
+	}`), s)
+}
+
+func TestLoad_openAPI31(t *testing.T) {
+	fsys := fstest.MapFS{
+		"openapi.json": {Data: []byte(`{
+		  "openapi":"3.1.0","info":{"title":"Pets","version":"1.0.0"},
+		  "webhooks":{"newPet":{"$ref":"webhooks.json#/newPet"}}
+		}`)},
+		"webhooks.json": {Data: []byte(`{
+		  "newPet":{
+			"post":{
+			  "requestBody":{"$ref":"#/requestBody"},
+			  "responses":{"200":{"description":"OK"}}
+			}
+		  },
+		  "requestBody":{"content":{"application/json":{"schema":{"type":"object"}}}}
+		}`)},
+	}
+
+	s, err := bundle.Load(fsys, "openapi.json")
+	require.NoError(t, err)
+	require.IsType(t, &openapi31.Spec{}, s)
+
+	assertjson.EqualMarshal(t, []byte(`{
+	  "openapi":"3.1.0","info":{"title":"Pets","version":"1.0.0"},
+	  "webhooks":{
+		"newPet":{
+		  "post":{
+			"requestBody":{"$ref":"#/components/requestBodies/requestBody"},
+			"responses":{"200":{"description":"OK"}}
+		  }
+		}
+	  },
+	  "components":{
+		"requestBodies":{"requestBody":{"content":{"application/json":{"schema":{"type":"object"}}}}}
+	  }
+	}`), s)
+}
+
+func TestLoad_errors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"openapi.yaml": {Data: []byte(`
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets: {$ref: 'https://example.com/pets.yaml'}
+`)},
+		"missing.yaml": {Data: []byte(`
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets: {$ref: 'pets.yaml#/pets'}
+`)},
+		"cycle.yaml": {Data: []byte(`
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets: {$ref: 'paths.yaml#/a'}
+`)},
+		"paths.yaml": {Data: []byte(`
+a: {$ref: '#/b'}
+b: {$ref: '#/a'}
+`)},
+		"swagger.yaml": {Data: []byte(`swagger: "2.0"`)},
+	}
+
+	_, err := bundle.Load(fsys, "openapi.yaml")
+	assert.True(t, errors.Is(err, bundle.ErrUnsupportedReference))
+	assert.EqualError(t, err, "openapi.yaml: unsupported reference: https://example.com/pets.yaml")
+
+	_, err = bundle.Load(fsys, "missing.yaml")
+	assert.EqualError(t, err, "missing.yaml: resolving pets.yaml#/pets: open pets.yaml: file does not exist")
+
+	_, err = bundle.Load(fsys, "cycle.yaml")
+	assert.True(t, errors.Is(err, openapi.ErrCircularReference))
+
+	_, err = bundle.Load(fsys, "swagger.yaml")
+	assert.EqualError(t, err, `swagger.yaml: unsupported OpenAPI version ""`)
+}
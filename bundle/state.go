@@ -0,0 +1,185 @@
+package bundle
+
+import "strings"
+
+// state describes a kind of value at a position in OpenAPI document.
+type state string
+
+const (
+	stUnknown        = state("")
+	stLiteral        = state("literal") // Value is not a part of document structure, e.g. example.
+	stDocument       = state("document")
+	stComponents     = state("components")
+	stPathItem       = state("pathItem")
+	stOperation      = state("operation")
+	stParameter      = state("parameter")
+	stHeader         = state("header")
+	stRequestBody    = state("requestBody")
+	stResponses      = state("responses")
+	stResponse       = state("response")
+	stMediaType      = state("mediaType")
+	stEncoding       = state("encoding")
+	stSchema         = state("schema")
+	stExample        = state("example")
+	stLink           = state("link")
+	stCallback       = state("callback")
+	stSecurityScheme = state("securityScheme")
+)
+
+// mapOf is a state of an object with arbitrary names as keys and values of a kind.
+func mapOf(st state) state {
+	return "map:" + st
+}
+
+// listOf is a state of an array with values of a kind.
+func listOf(st state) state {
+	return "list:" + st
+}
+
+func (st state) isCollection() bool {
+	return strings.HasPrefix(string(st), "map:") || strings.HasPrefix(string(st), "list:")
+}
+
+var (
+	documentStates = map[string]state{
+		"paths":      mapOf(stPathItem),
+		"webhooks":   mapOf(stPathItem),
+		"components": stComponents,
+	}
+
+	componentsStates = map[string]state{
+		"schemas":         mapOf(stSchema),
+		"responses":       mapOf(stResponse),
+		"parameters":      mapOf(stParameter),
+		"examples":        mapOf(stExample),
+		"requestBodies":   mapOf(stRequestBody),
+		"headers":         mapOf(stHeader),
+		"securitySchemes": mapOf(stSecurityScheme),
+		"links":           mapOf(stLink),
+		"callbacks":       mapOf(stCallback),
+		"pathItems":       mapOf(stPathItem),
+	}
+
+	operationStates = map[string]state{
+		"parameters":  listOf(stParameter),
+		"requestBody": stRequestBody,
+		"responses":   stResponses,
+		"callbacks":   mapOf(stCallback),
+	}
+
+	parameterStates = map[string]state{
+		"schema":   stSchema,
+		"content":  mapOf(stMediaType),
+		"examples": mapOf(stExample),
+	}
+
+	mediaTypeStates = map[string]state{
+		"schema":   stSchema,
+		"examples": mapOf(stExample),
+		"encoding": mapOf(stEncoding),
+	}
+
+	responseStates = map[string]state{
+		"headers": mapOf(stHeader),
+		"content": mapOf(stMediaType),
+		"links":   mapOf(stLink),
+	}
+
+	schemaLiterals = map[string]bool{
+		"example": true, "examples": true, "default": true, "const": true, "enum": true,
+	}
+
+	schemaMaps = map[string]bool{
+		"properties": true, "patternProperties": true, "$defs": true, "definitions": true, "dependentSchemas": true,
+	}
+
+	httpMethods = map[string]bool{
+		"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true,
+	}
+)
+
+// next returns state of a value by its key in the value of current state.
+func (st state) next(key string) state {
+	if st.isCollection() {
+		return state(st[strings.Index(string(st), ":")+1:])
+	}
+
+	if strings.HasPrefix(key, "x-") || key == "example" {
+		return stLiteral
+	}
+
+	switch st {
+	case stDocument:
+		return documentStates[key]
+	case stComponents:
+		return componentsStates[key]
+	case stPathItem:
+		if key == "parameters" {
+			return listOf(stParameter)
+		}
+
+		if httpMethods[key] {
+			return stOperation
+		}
+	case stOperation:
+		return operationStates[key]
+	case stResponses:
+		return stResponse
+	case stCallback:
+		return stPathItem
+	case stParameter, stHeader:
+		return parameterStates[key]
+	case stMediaType:
+		return mediaTypeStates[key]
+	case stEncoding:
+		if key == "headers" {
+			return mapOf(stHeader)
+		}
+	case stRequestBody:
+		if key == "content" {
+			return mapOf(stMediaType)
+		}
+	case stResponse:
+		return responseStates[key]
+	case stSchema:
+		if schemaLiterals[key] {
+			return stLiteral
+		}
+
+		if schemaMaps[key] {
+			return mapOf(stSchema)
+		}
+
+		return stSchema
+	case stExample, stLink, stLiteral:
+		return stLiteral
+	}
+
+	return stUnknown
+}
+
+// section returns name of components section for objects of a state, empty for objects that are inlined.
+func (st state) section() string {
+	switch st { //nolint:exhaustive // Other states are inlined.
+	case stSchema:
+		return "schemas"
+	case stParameter:
+		return "parameters"
+	case stHeader:
+		return "headers"
+	case stRequestBody:
+		return "requestBodies"
+	case stResponse:
+		return "responses"
+	case stExample:
+		return "examples"
+	case stLink:
+		return "links"
+	case stCallback:
+		return "callbacks"
+	case stSecurityScheme:
+		return "securitySchemes"
+	}
+
+	return ""
+}
@@ -0,0 +1,364 @@
+package codegen
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Client generates Go source of a client with a method for each operation.
+//
+// Client relies on types generated by Types in the same package. Non-2xx responses are returned
+// as *StatusError with decoded body of documented response in Value.
+func Client(s openapi.SpecSchema, options Options) ([]byte, error) {
+	g, err := newGenerator(s, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+
+	b.WriteString(strings.ReplaceAll(clientRuntime, "{{title}}", g.title()))
+
+	for _, o := range g.operations {
+		g.clientMethod(&b, o)
+	}
+
+	return g.file("", b.Bytes())
+}
+
+const clientRuntime = `
+// Client calls operations of {{title}} API.
+type Client struct {
+	// BaseURL is a URL of API server without trailing slash, e.g. "https://api.example.com/v1".
+	BaseURL string
+
+	// HTTPClient sends requests, http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client of API server.
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// StatusError is returned for responses with non-2xx status.
+type StatusError struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Value is a decoded body of documented response, it is nil for undocumented responses.
+	Value interface{}
+}
+
+// Error implements error.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected response status: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (c *Client) send(req *http.Request) (*http.Response, []byte, error) {
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, body, nil
+}
+
+func statusError(resp *http.Response, body []byte, value interface{}) error {
+	e := &StatusError{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}
+
+	if value != nil && json.Unmarshal(body, value) == nil {
+		e.Value = value
+	}
+
+	return e
+}
+
+// formatValue formats scalar or object parameter value with simple style.
+func formatValue(v interface{}) string {
+	if m, ok := v.(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Slice:
+		return strings.Join(formatValues(v), ",")
+	case reflect.Map:
+		return formatMap(rv)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func formatMap(rv reflect.Value) string {
+	keys := make([]string, 0, rv.Len())
+	for _, k := range rv.MapKeys() {
+		keys = append(keys, k.String())
+	}
+
+	sort.Strings(keys)
+
+	items := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		items = append(items, k, formatValue(rv.MapIndex(reflect.ValueOf(k)).Interface()))
+	}
+
+	return strings.Join(items, ",")
+}
+
+// formatValues formats items of array parameter value.
+func formatValues(v interface{}) []string {
+	rv := reflect.ValueOf(v)
+	res := make([]string, 0, rv.Len())
+
+	for i := 0; i < rv.Len(); i++ {
+		res = append(res, formatValue(rv.Index(i).Interface()))
+	}
+
+	return res
+}
+
+// encodeForm encodes fields with formData tags of a struct value.
+func encodeForm(v interface{}) url.Values {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	res := url.Values{}
+
+	for i := 0; i < rv.NumField(); i++ {
+		name := strings.Split(rv.Type().Field(i).Tag.Get("formData"), ",")[0]
+		fv := rv.Field(i)
+
+		if name == "" || name == "-" {
+			continue
+		}
+
+		switch fv.Kind() { //nolint:exhaustive // Other kinds are never nil.
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			if fv.IsNil() {
+				continue
+			}
+		}
+
+		fv = reflect.Indirect(fv)
+
+		if fv.Kind() == reflect.Slice {
+			res[name] = append(res[name], formatValues(fv.Interface())...)
+		} else {
+			res.Set(name, formatValue(fv.Interface()))
+		}
+	}
+
+	return res
+}
+`
+
+// clientMethod generates a method that sends operation request.
+func (g *generator) clientMethod(b *bytes.Buffer, o *operation) {
+	signature, zero := g.signature(o)
+
+	b.WriteString("\n")
+	operationDoc(b, o, o.name+" calls "+o.method+" "+o.path+".")
+	b.WriteString("func (c *Client) " + signature + " {\n")
+	b.WriteString("u := c.BaseURL + " + o.pathExpr("params") + "\n")
+
+	g.clientQuery(b, o)
+	g.clientBody(b, o, zero)
+
+	b.WriteString("if err != nil {\nreturn " + zero + "err\n}\n\n")
+
+	if o.body != nil {
+		optional := (o.body.json || o.body.form) && g.nilable(o.body.typ)
+		if optional {
+			b.WriteString("if reqBody != nil {\n")
+		}
+
+		b.WriteString("req.Header.Set(\"Content-Type\", " + quote(o.body.contentType) + ")\n")
+
+		if optional {
+			b.WriteString("}\n")
+		}
+	}
+
+	if o.result != "" {
+		b.WriteString("req.Header.Set(\"Accept\", \"application/json\")\n")
+	}
+
+	g.clientHeaders(b, o)
+
+	b.WriteString("\nresp, respBody, err := c.send(req)\n")
+	b.WriteString("if err != nil {\nreturn " + zero + "err\n}\n\n")
+	b.WriteString("if resp.StatusCode/100 != 2 {\n")
+
+	switch {
+	case len(o.errors) == 0:
+		b.WriteString("return " + zero + "statusError(resp, respBody, nil)\n}\n")
+	case len(o.errors) == 1 && o.errors[0].key == "default":
+		b.WriteString("return " + zero + "statusError(resp, respBody, new(" + o.errors[0].typ + "))\n}\n")
+	default:
+		b.WriteString("var value interface{}\n\nswitch {\n")
+
+		for _, e := range o.errors {
+			if e.key == "default" {
+				b.WriteString("default:\n")
+			} else {
+				b.WriteString("case " + statusCondition("resp.StatusCode", e.key) + ":\n")
+			}
+
+			b.WriteString("value = new(" + e.typ + ")\n")
+		}
+
+		b.WriteString("}\n\nreturn " + zero + "statusError(resp, respBody, value)\n}\n")
+	}
+
+	if o.result == "" {
+		b.WriteString("\nreturn nil\n}\n")
+
+		return
+	}
+
+	b.WriteString("\nif len(respBody) == 0 {\nreturn nil, nil\n}\n\n")
+	b.WriteString("var res " + o.result + "\n\n")
+	b.WriteString("if err := json.Unmarshal(respBody, &res); err != nil {\nreturn nil, err\n}\n\n")
+
+	if g.nilable(o.result) {
+		b.WriteString("return res, nil\n}\n")
+	} else {
+		b.WriteString("return &res, nil\n}\n")
+	}
+}
+
+// operationDoc writes doc comment of operation method.
+func operationDoc(b *bytes.Buffer, o *operation, first string) {
+	comment(b, first)
+
+	for _, text := range []*string{o.op.Summary, o.op.Description} {
+		if text != nil && strings.TrimSpace(*text) != "" {
+			b.WriteString("//\n")
+			comment(b, *text)
+		}
+	}
+
+	if o.op.Deprecated != nil && *o.op.Deprecated {
+		b.WriteString("//\n// Deprecated: operation is deprecated.\n")
+	}
+}
+
+func (g *generator) clientQuery(b *bytes.Buffer, o *operation) {
+	hasQuery := false
+
+	for _, p := range o.params {
+		if p.in == openapi31.ParameterInQuery {
+			hasQuery = true
+		}
+	}
+
+	if !hasQuery {
+		return
+	}
+
+	b.WriteString("\nq := url.Values{}\n")
+
+	for _, p := range o.params {
+		if p.in != openapi31.ParameterInQuery {
+			continue
+		}
+
+		v := "params." + p.field
+		name := quote(p.name)
+
+		switch {
+		case strings.HasPrefix(p.typ, "map["):
+			key := "k"
+			if p.style == openapi31.ParameterStyleDeepObject {
+				key = name + "+\"[\"+k+\"]\""
+			}
+
+			b.WriteString("for k, v := range " + v + " {\nq.Set(" + key + ", formatValue(v))\n}\n")
+		case strings.HasPrefix(p.typ, "[]") && p.explode:
+			b.WriteString("for _, v := range formatValues(" + v + ") {\nq.Add(" + name + ", v)\n}\n")
+		case g.omittable(p.typ):
+			b.WriteString(g.nilCheck(p, v) + "q.Set(" + name + ", " + p.format(v) + ")\n}\n")
+		default:
+			b.WriteString("q.Set(" + name + ", " + p.format(v) + ")\n")
+		}
+	}
+
+	b.WriteString("\nif len(q) > 0 {\nu += \"?\" + q.Encode()\n}\n")
+}
+
+// nilCheck opens a condition block for present parameter value.
+func (g *generator) nilCheck(p param, v string) string {
+	if strings.HasPrefix(p.typ, "[]") {
+		return "if len(" + v + ") > 0 {\n"
+	}
+
+	if g.emptyEnums[p.typ] {
+		return "if " + v + " != \"\" {\n"
+	}
+
+	return "if " + v + " != nil {\n"
+}
+
+func (g *generator) clientBody(b *bytes.Buffer, o *operation, zero string) {
+	method := "http.Method" + strings.ToUpper(o.method[:1]) + strings.ToLower(o.method[1:])
+
+	switch {
+	case o.body == nil:
+		b.WriteString("\nreq, err := http.NewRequestWithContext(ctx, " + method + ", u, nil)\n")
+	case o.body.form && g.nilable(o.body.typ):
+		b.WriteString("\nvar reqBody io.Reader\n\nif body != nil {\n")
+		b.WriteString("reqBody = strings.NewReader(encodeForm(body).Encode())\n}\n\n")
+		b.WriteString("req, err := http.NewRequestWithContext(ctx, " + method + ", u, reqBody)\n")
+	case o.body.form:
+		b.WriteString("\nreq, err := http.NewRequestWithContext(ctx, " + method +
+			", u, strings.NewReader(encodeForm(body).Encode()))\n")
+	case !o.body.json:
+		b.WriteString("\nreq, err := http.NewRequestWithContext(ctx, " + method + ", u, body)\n")
+	case g.nilable(o.body.typ):
+		b.WriteString("\nvar reqBody io.Reader\n\nif body != nil {\n")
+		b.WriteString("data, err := json.Marshal(body)\nif err != nil {\nreturn " + zero + "err\n}\n\n")
+		b.WriteString("reqBody = bytes.NewReader(data)\n}\n\n")
+		b.WriteString("req, err := http.NewRequestWithContext(ctx, " + method + ", u, reqBody)\n")
+	default:
+		b.WriteString("\ndata, err := json.Marshal(body)\nif err != nil {\nreturn " + zero + "err\n}\n\n")
+		b.WriteString("req, err := http.NewRequestWithContext(ctx, " + method + ", u, bytes.NewReader(data))\n")
+	}
+}
+
+func (g *generator) clientHeaders(b *bytes.Buffer, o *operation) {
+	for _, p := range o.params {
+		if p.in != openapi31.ParameterInHeader && p.in != openapi31.ParameterInCookie {
+			continue
+		}
+
+		v := "params." + p.field
+
+		set := "req.Header.Set(" + quote(p.name) + ", " + p.format(v) + ")\n"
+		if p.in == openapi31.ParameterInCookie {
+			set = "req.AddCookie(&http.Cookie{Name: " + quote(p.name) + ", Value: " + p.format(v) + "})\n"
+		}
+
+		if g.omittable(p.typ) {
+			b.WriteString(g.nilCheck(p, v) + set + "}\n")
+		} else {
+			b.WriteString(set)
+		}
+	}
+}
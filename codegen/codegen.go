@@ -0,0 +1,805 @@
+// Package codegen generates Go code of types, clients and servers from OpenAPI 3.0 and 3.1 specs.
+//
+// Types of component schemas carry `json` tags and the same field tags that the reflector reads
+// (e.g. `required`, `minimum` or `enum`), so that the reflected spec of generated types matches the source.
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/convert"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Options configures code generation.
+type Options struct {
+	// Package is a name of generated package, "api" by default.
+	Package string
+}
+
+// generatedBy is a header of generated files.
+const generatedBy = "// Code generated by github.com/swaggest/openapi-go/codegen. DO NOT EDIT.\n\n"
+
+// reservedNames are package-level names of generated code.
+var reservedNames = []string{"Client", "NewClient", "StatusError", "Server", "ErrNotImplemented"}
+
+// standardImports maps package names to import paths of packages that generated code may use.
+var standardImports = map[string]string{
+	"bytes":    "bytes",
+	"context":  "context",
+	"encoding": "encoding",
+	"errors":   "errors",
+	"fmt":      "fmt",
+	"http":     "net/http",
+	"io":       "io",
+	"json":     "encoding/json",
+	"reflect":  "reflect",
+	"sort":     "sort",
+	"strings":  "strings",
+	"time":     "time",
+	"url":      "net/url",
+}
+
+type generator struct {
+	spec     *openapi31.Spec
+	resolver *openapi31.RefResolver
+	options  Options
+
+	// names contains used package-level identifiers.
+	names map[string]bool
+
+	// schemas maps component schema names to Go type names.
+	schemas map[string]string
+
+	// nilableTypes contains names of component types that are slices or maps.
+	nilableTypes map[string]bool
+
+	// emptyEnums contains names of string enum types that do not allow empty value,
+	// such types are not pointers when optional, because zero value means absence.
+	emptyEnums map[string]bool
+
+	operations []*operation
+}
+
+func newGenerator(s openapi.SpecSchema, options Options) (*generator, error) {
+	spec, err := convert.AsOpenAPI31(s)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := openapi31.NewRefResolver(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Package == "" {
+		options.Package = "api"
+	}
+
+	g := &generator{
+		spec:     spec,
+		resolver: resolver,
+		options:  options,
+		names:    map[string]bool{},
+		schemas:  map[string]string{},
+
+		nilableTypes: map[string]bool{},
+		emptyEnums:   map[string]bool{},
+	}
+
+	for _, name := range reservedNames {
+		g.names[name] = true
+	}
+
+	if spec.Components != nil {
+		for _, name := range internal.SortedMapKeys(spec.Components.Schemas) {
+			g.schemas[name] = g.uniqueName(goName(name))
+		}
+
+		for name, cs := range spec.Components.Schemas {
+			s, _ := nonNull(cs)
+
+			values, goType, ok := g.enum(s)
+			if !ok && !isStruct(s) {
+				g.nilableTypes[g.schemas[name]] = isNilable(g.goType(s))
+			}
+
+			if ok && goType == "string" && !hasValue(values, "") {
+				g.emptyEnums[g.schemas[name]] = true
+			}
+		}
+	}
+
+	if err := g.loadOperations(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// uniqueName reserves package-level identifier.
+func (g *generator) uniqueName(name string) string {
+	return uniqueName(g.names, name)
+}
+
+func uniqueName(names map[string]bool, name string) string {
+	res := name
+
+	for i := 2; names[res]; i++ {
+		res = fmt.Sprintf("%s%d", name, i)
+	}
+
+	names[res] = true
+
+	return res
+}
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "https": true, "id": true, "ip": true, "json": true,
+	"sql": true, "tls": true, "ttl": true, "uri": true, "url": true, "uuid": true, "xml": true,
+}
+
+// goName makes an exported Go identifier, e.g. "UserID" for "user_id" or "userId".
+func goName(s string) string {
+	var (
+		parts []string
+		cur   []rune
+		prev  rune
+	)
+
+	flush := func() {
+		if len(cur) > 0 {
+			parts = append(parts, string(cur))
+			cur = nil
+		}
+	}
+
+	for _, r := range s {
+		if !isLetter(r) && !isDigit(r) {
+			flush()
+
+			prev = 0
+
+			continue
+		}
+
+		if isUpper(r) && (isLower(prev) || isDigit(prev)) {
+			flush()
+		}
+
+		cur = append(cur, r)
+		prev = r
+	}
+
+	flush()
+
+	res := ""
+
+	for _, p := range parts {
+		if initialisms[strings.ToLower(p)] {
+			res += strings.ToUpper(p)
+		} else {
+			res += strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+
+	if res == "" || isDigit(rune(res[0])) {
+		res = "X" + res
+	}
+
+	return res
+}
+
+func isLetter(r rune) bool {
+	return isLower(r) || isUpper(r)
+}
+
+func isLower(r rune) bool {
+	return r >= 'a' && r <= 'z'
+}
+
+func isUpper(r rune) bool {
+	return r >= 'A' && r <= 'Z'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// file formats generated code and adds imports of used standard packages.
+func (g *generator) file(doc string, body []byte) ([]byte, error) {
+	src := generatedBy + doc + "package " + g.options.Package + "\n\n" + string(body)
+
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing generated code: %w", err)
+	}
+
+	used := map[string]bool{}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+				if path, ok := standardImports[id.Name]; ok {
+					used[path] = true
+				}
+			}
+		}
+
+		return true
+	})
+
+	imports := make([]string, 0, len(used))
+	for path := range used {
+		imports = append(imports, path)
+	}
+
+	sort.Strings(imports)
+
+	if len(imports) > 0 {
+		var b strings.Builder
+
+		b.WriteString("import (\n")
+
+		for _, path := range imports {
+			b.WriteString("\t\"" + path + "\"\n")
+		}
+
+		b.WriteString(")\n\n")
+
+		src = generatedBy + doc + "package " + g.options.Package + "\n\n" + b.String() + string(body)
+	}
+
+	res, err := format.Source([]byte(src))
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+
+	return res, nil
+}
+
+// title returns API title for doc comments.
+func (g *generator) title() string {
+	if g.spec.Info.Title != "" {
+		return g.spec.Info.Title
+	}
+
+	return "the"
+}
+
+// comment makes a Go comment of text lines.
+func comment(w *bytes.Buffer, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			w.WriteString("//\n")
+		} else {
+			w.WriteString("// " + line + "\n")
+		}
+	}
+}
+
+// schemaRef returns Go type name of a reference to component schema.
+func (g *generator) schemaRef(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, "#/components/schemas/") {
+		return "", false
+	}
+
+	name, ok := g.schemas[internal.UnescapeJSONPointer(strings.TrimPrefix(ref, "#/components/schemas/"))]
+
+	return name, ok
+}
+
+// resolveSchema follows non-component schema references, component references are kept to use named types.
+func (g *generator) resolveSchema(s map[string]interface{}) map[string]interface{} {
+	for i := 0; i < 16; i++ {
+		ref, ok := s["$ref"].(string)
+		if !ok {
+			return s
+		}
+
+		if _, ok := g.schemaRef(ref); ok {
+			return s
+		}
+
+		var res map[string]interface{}
+		if err := g.resolver.Resolve(ref, &res); err != nil {
+			return map[string]interface{}{}
+		}
+
+		s = res
+	}
+
+	return s
+}
+
+// component returns component schema by Go type name of reference.
+func (g *generator) component(ref string) map[string]interface{} {
+	if g.spec.Components == nil {
+		return nil
+	}
+
+	return g.spec.Components.Schemas[internal.UnescapeJSONPointer(strings.TrimPrefix(ref, "#/components/schemas/"))]
+}
+
+// nonNull returns schema without null type, e.g. {"type":"string"} for {"type":["string","null"]}
+// or {"$ref":"..."} for {"anyOf":[{"type":"null"},{"$ref":"..."}]}.
+func nonNull(s map[string]interface{}) (map[string]interface{}, bool) {
+	for _, key := range []string{"anyOf", "oneOf"} {
+		variants, ok := s[key].([]interface{})
+		if !ok || len(variants) != 2 {
+			continue
+		}
+
+		for i, v := range variants {
+			if vs, ok := v.(map[string]interface{}); ok && vs["type"] == "null" && len(vs) == 1 {
+				other, _ := variants[1-i].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+				return other, true
+			}
+		}
+	}
+
+	if s["type"] == "null" {
+		return map[string]interface{}{}, true
+	}
+
+	types, ok := s["type"].([]interface{})
+	if !ok {
+		return s, false
+	}
+
+	var (
+		nullable bool
+		rest     []interface{}
+	)
+
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+		} else {
+			rest = append(rest, t)
+		}
+	}
+
+	if !nullable {
+		return s, false
+	}
+
+	res := make(map[string]interface{}, len(s))
+
+	for k, v := range s {
+		res[k] = v
+	}
+
+	if len(rest) == 1 {
+		res["type"] = rest[0]
+	} else {
+		res["type"] = rest
+	}
+
+	return res, true
+}
+
+// schemaType returns single JSON Schema type, empty for no or multiple types.
+func schemaType(s map[string]interface{}) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		if len(t) == 1 {
+			res, _ := t[0].(string) //nolint:errcheck // Empty is valid.
+
+			return res
+		}
+	}
+
+	if _, ok := s["properties"]; ok {
+		return "object"
+	}
+
+	if _, ok := s["allOf"]; ok {
+		return "object"
+	}
+
+	return ""
+}
+
+func asSchema(v interface{}) map[string]interface{} {
+	if s, ok := v.(map[string]interface{}); ok {
+		return s
+	}
+
+	return map[string]interface{}{}
+}
+
+// goType returns Go type of a schema, inline objects are anonymous structs.
+func (g *generator) goType(s map[string]interface{}) string {
+	s, _ = nonNull(g.resolveSchema(s))
+
+	if ref, ok := s["$ref"].(string); ok {
+		if name, ok := g.schemaRef(ref); ok {
+			return name
+		}
+
+		return "interface{}"
+	}
+
+	if allOf, ok := s["allOf"].([]interface{}); ok && len(allOf) == 1 && s["properties"] == nil {
+		return g.goType(asSchema(allOf[0]))
+	}
+
+	format, _ := s["format"].(string) //nolint:errcheck // Empty is valid.
+
+	switch schemaType(s) {
+	case "string":
+		if format == "date-time" {
+			return "time.Time"
+		}
+
+		return "string"
+	case "integer":
+		switch format {
+		case "int64":
+			return "int64"
+		case "int32":
+			return "int32"
+		}
+
+		return "int"
+	case "number":
+		if format == "float" {
+			return "float32"
+		}
+
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		items, ok := s["items"].(map[string]interface{})
+		if !ok {
+			return "[]interface{}"
+		}
+
+		return "[]" + g.goType(items)
+	case "object":
+		if _, ok := s["properties"]; ok {
+			return g.structType(s, false, "json")
+		}
+
+		if _, ok := s["allOf"]; ok {
+			return g.structType(s, false, "json")
+		}
+
+		if ap, ok := s["additionalProperties"].(map[string]interface{}); ok {
+			return "map[string]" + g.goType(ap)
+		}
+
+		return "map[string]interface{}"
+	}
+
+	return "interface{}"
+}
+
+// impliedFormats are formats that are reflected from Go types.
+var impliedFormats = map[string]string{
+	"time.Time": "date-time",
+	"int64":     "int64",
+	"int32":     "int32",
+	"float64":   "double",
+	"float32":   "float",
+}
+
+// schemaTagKeywords are schema keywords that are populated from field tags by the reflector.
+var schemaTagKeywords = []string{
+	"title", "description", "format", "default", "example", "examples", "enum", "const",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf",
+	"minLength", "maxLength", "pattern", "minItems", "maxItems", "uniqueItems",
+	"minProperties", "maxProperties", "readOnly", "writeOnly", "deprecated",
+}
+
+// tags builds struct field tags.
+type tags []string
+
+func (t *tags) add(key, value string) {
+	*t = append(*t, key+":"+quote(value))
+}
+
+func (t tags) String() string {
+	s := strings.Join(t, " ")
+	if strings.Contains(s, "`") {
+		return quote(s)
+	}
+
+	return "`" + s + "`"
+}
+
+// quote makes a double-quoted Go string literal.
+func quote(s string) string {
+	return strconv.Quote(s)
+}
+
+// jsonMarshal encodes value without escaping HTML characters.
+func jsonMarshal(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// schemaTags adds tags of schema keywords with a prefix (e.g. "items.").
+func schemaTags(t *tags, prefix string, s map[string]interface{}, typ string) {
+	if _, ok := s["$ref"]; ok {
+		for _, k := range []string{"title", "description", "deprecated", "readOnly", "writeOnly"} {
+			if v, ok := s[k]; ok {
+				t.add(prefix+k, tagValue(v))
+			}
+		}
+
+		return
+	}
+
+	for _, k := range schemaTagKeywords {
+		v, ok := s[k]
+		if !ok {
+			continue
+		}
+
+		switch k {
+		case "format":
+			// Formats of pointers are only implied for time.Time.
+			if impliedFormats[typ] == v || (typ == "*time.Time" && v == "date-time") {
+				continue
+			}
+		case "examples":
+			b, err := jsonMarshal(v)
+			if err != nil {
+				continue
+			}
+
+			t.add(prefix+k, string(b))
+
+			continue
+		case "enum":
+			t.add(prefix+k, enumTag(v))
+
+			continue
+		}
+
+		t.add(prefix+k, tagValue(v))
+	}
+}
+
+// enumTag makes comma-separated list of string values or JSON array.
+func enumTag(v interface{}) string {
+	items, _ := v.([]interface{}) //nolint:errcheck // Nil is valid.
+	values := make([]string, 0, len(items))
+
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok || s == "" || strings.Contains(s, ",") || strings.TrimSpace(s) != s || strings.HasPrefix(s, "[") {
+			b, err := jsonMarshal(v)
+			if err != nil {
+				return ""
+			}
+
+			return string(b)
+		}
+
+		values = append(values, s)
+	}
+
+	return strings.Join(values, ",")
+}
+
+// tagValue formats scalar value as is and other values as JSON.
+func tagValue(v interface{}) string {
+	switch vv := v.(type) {
+	case string:
+		return vv
+	case bool, float64, int64, int:
+		return fmt.Sprint(vv)
+	}
+
+	b, err := jsonMarshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+// structType makes Go struct type of object schema, named is true for component types,
+// nameTag is a field tag of property names, e.g. "json" or "formData".
+func (g *generator) structType(s map[string]interface{}, named bool, nameTag string) string {
+	var b strings.Builder
+
+	b.WriteString("struct {\n")
+
+	var t tags
+
+	keywords := []string{"minProperties", "maxProperties"}
+	if named {
+		keywords = append(keywords, "title", "description")
+	}
+
+	for _, k := range keywords {
+		if v, ok := s[k]; ok {
+			t.add(k, tagValue(v))
+		}
+	}
+
+	if s["additionalProperties"] == false {
+		t.add("additionalProperties", "false")
+	}
+
+	if len(t) > 0 {
+		b.WriteString("_ struct{} " + t.String() + "\n")
+	}
+
+	fields := map[string]bool{"_": true}
+	props := map[string]interface{}{}
+	required := map[string]bool{}
+
+	g.collectProperties(s, props, required, func(name string) {
+		fields[name] = true
+		b.WriteString(name + " `refer:\"true\"`\n")
+	})
+
+	for _, name := range internal.SortedKeys(props) {
+		ps, ok := props[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		b.WriteString(g.field(fields, nameTag, name, ps, required[name]) + "\n")
+	}
+
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// collectProperties collects properties of object schema and its inline allOf members,
+// embed is called for allOf references to component objects.
+func (g *generator) collectProperties(
+	s map[string]interface{},
+	props map[string]interface{},
+	required map[string]bool,
+	embed func(name string),
+) {
+	if pp, ok := s["properties"].(map[string]interface{}); ok {
+		for k, v := range pp {
+			props[k] = v
+		}
+	}
+
+	req, _ := s["required"].([]interface{}) //nolint:errcheck // Nil is valid.
+	for _, r := range req {
+		if name, ok := r.(string); ok {
+			required[name] = true
+		}
+	}
+
+	allOf, _ := s["allOf"].([]interface{}) //nolint:errcheck // Nil is valid.
+	for _, item := range allOf {
+		is := asSchema(item)
+
+		if ref, ok := is["$ref"].(string); ok {
+			if name, ok := g.schemaRef(ref); ok && schemaType(g.component(ref)) == "object" {
+				embed(name)
+			}
+
+			continue
+		}
+
+		g.collectProperties(is, props, required, embed)
+	}
+}
+
+// field makes struct field of object property.
+func (g *generator) field(
+	fields map[string]bool,
+	nameTag, prop string,
+	ps map[string]interface{},
+	required bool,
+) string {
+	s, nullable := nonNull(g.resolveSchema(ps))
+	typ := g.goType(ps)
+	_, isRef := s["$ref"]
+
+	omitEmpty := !required
+	if g.nilable(typ) {
+		nullable = nullable && !isRef
+	} else if nullable || (!required && !g.emptyEnums[typ]) {
+		typ = "*" + typ
+	}
+
+	t := tags{}
+	if omitEmpty {
+		t.add(nameTag, prop+",omitempty")
+	} else {
+		t.add(nameTag, prop)
+	}
+
+	if required {
+		t.add("required", "true")
+	}
+
+	switch {
+	case nullable && !isRef:
+		t.add("nullable", "true")
+	case !nullable && !omitEmpty && isNilable(typ) && typ != "interface{}":
+		t.add("nullable", "false")
+	case !nullable && !isRef && strings.HasPrefix(typ, "*") && !strings.HasPrefix(typ, "*struct {"):
+		// Reflector makes pointers to scalars nullable regardless of omitempty.
+		t.add("nullable", "false")
+	}
+
+	schemaTags(&t, "", s, typ)
+	itemsTags(&t, s)
+
+	return uniqueName(fields, goName(prop)) + " " + typ + " " + t.String()
+}
+
+// itemsTags adds tags of scalar array items.
+func itemsTags(t *tags, s map[string]interface{}) {
+	items, ok := s["items"].(map[string]interface{})
+	if !ok || schemaType(s) != "array" {
+		return
+	}
+
+	if _, ok := items["$ref"]; ok {
+		return
+	}
+
+	switch schemaType(items) {
+	case "string", "integer", "number", "boolean":
+		schemaTags(t, "items.", items, "")
+	}
+}
+
+// nilable is true for Go types that have nil value, including named slices and maps.
+func (g *generator) nilable(typ string) bool {
+	return isNilable(typ) || g.nilableTypes[typ]
+}
+
+// omittable is true for Go types that have zero value to represent absent optional value.
+func (g *generator) omittable(typ string) bool {
+	return g.nilable(typ) || g.emptyEnums[typ]
+}
+
+// hasValue is true if values contain v.
+func hasValue(values []interface{}, v interface{}) bool {
+	for _, item := range values {
+		if item == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isNilable is true for Go types that have nil value.
+func isNilable(typ string) bool {
+	return strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") ||
+		strings.HasPrefix(typ, "*") || typ == "interface{}"
+}
@@ -0,0 +1,364 @@
+package codegen_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/codegen"
+	"github.com/swaggest/openapi-go/codegen/testdata/petstore"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func loadPetstore(t *testing.T) *openapi31.Spec {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/petstore.yaml")
+	require.NoError(t, err)
+
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML(data))
+
+	return &s
+}
+
+func assertGenerated(t *testing.T, fileName string, generated []byte) {
+	t.Helper()
+
+	expected, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(generated))
+}
+
+func TestTypes(t *testing.T) {
+	src, err := codegen.Types(loadPetstore(t), codegen.Options{Package: "petstore"})
+	require.NoError(t, err)
+
+	assertGenerated(t, "testdata/petstore/types.go", src)
+}
+
+func TestClient(t *testing.T) {
+	src, err := codegen.Client(loadPetstore(t), codegen.Options{Package: "petstore"})
+	require.NoError(t, err)
+
+	assertGenerated(t, "testdata/petstore/client.go", src)
+}
+
+func TestServer(t *testing.T) {
+	src, err := codegen.Server(loadPetstore(t), codegen.Options{Package: "petstore"})
+	require.NoError(t, err)
+
+	assertGenerated(t, "testdata/petstore/server.go", src)
+
+	// Client implements server interfaces.
+	var (
+		_ petstore.PetsServer   = petstore.NewClient("")
+		_ petstore.PhotosServer = petstore.NewClient("")
+		_ petstore.Server       = petstore.NewClient("")
+	)
+}
+
+func TestServer_roundTrip(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.JSONSchemaReflector().DefaultOptions = append(r.JSONSchemaReflector().DefaultOptions,
+		jsonschema.InterceptDefName(func(t reflect.Type, _ string) string {
+			return t.Name()
+		}),
+	)
+	r.Spec.Info.WithTitle("Petstore").WithVersion("1.0.0")
+
+	binary := func(cu *openapi.ContentUnit) {
+		cu.ContentType = "image/png"
+		cu.Format = "binary"
+	}
+
+	required := openapi.WithCustomize(func(cor openapi.ContentOrReference) {
+		cor.(*openapi31.RequestBodyOrReference).RequestBody.WithRequired(true)
+	})
+
+	type response struct {
+		status      int // HTTP status, status class (e.g. 4 for 4XX) or 0 for default response.
+		structure   interface{}
+		description string
+	}
+
+	// Structures are passed as they are used in signatures of generated server interfaces.
+	for _, op := range []struct {
+		method, path, id, summary string
+		tags                      []string
+		deprecated                bool
+		params, body              interface{}
+		bodyOptions               []openapi.ContentOption
+		responses                 []response
+	}{
+		{
+			method: http.MethodGet, path: "/pets", id: "listPets", tags: []string{"pets"}, summary: "List all pets.",
+			params: new(petstore.ListPetsParams),
+			responses: []response{
+				{status: http.StatusOK, structure: petstore.Pets{}, description: "A list of pets."},
+				{structure: new(petstore.Error), description: "Unexpected error."},
+			},
+		},
+		{
+			method: http.MethodPost, path: "/pets", id: "createPet", tags: []string{"pets"},
+			body: new(petstore.NewPet), bodyOptions: []openapi.ContentOption{required},
+			responses: []response{
+				{status: http.StatusCreated, structure: new(petstore.Pet), description: "Created."},
+				{status: 4, structure: new(petstore.Error), description: "Bad request."},
+			},
+		},
+		{
+			method: http.MethodGet, path: "/pets/{petId}", id: "showPetById", tags: []string{"pets"},
+			params: new(petstore.ShowPetByIDParams),
+			responses: []response{
+				{status: http.StatusOK, structure: new(petstore.Pet), description: "Pet."},
+				{status: http.StatusNotFound, structure: new(petstore.Error), description: "Not found."},
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/pets/{petId}", id: "deletePet", tags: []string{"pets"}, deprecated: true,
+			params:    new(petstore.DeletePetParams),
+			responses: []response{{status: http.StatusNoContent, description: "Deleted."}},
+		},
+		{
+			method: http.MethodPut, path: "/pets/{petId}/photo", id: "uploadPhoto", tags: []string{"photos"},
+			params: new(petstore.UploadPhotoParams), bodyOptions: []openapi.ContentOption{binary},
+			responses: []response{
+				{status: http.StatusOK, structure: new(petstore.UploadPhotoResponse), description: "Photo metadata."},
+			},
+		},
+		{
+			method: http.MethodPost, path: "/pets/{petId}/rename", id: "renamePet",
+			params: new(petstore.RenamePetParams),
+			body:   new(petstore.RenamePetRequest), bodyOptions: []openapi.ContentOption{required},
+			responses: []response{{status: http.StatusNoContent, description: "Renamed."}},
+		},
+	} {
+		oc, err := r.NewOperationContext(op.method, op.path)
+		require.NoError(t, err)
+
+		oc.SetID(op.id)
+		oc.SetTags(op.tags...)
+
+		if op.summary != "" {
+			oc.SetSummary(op.summary)
+		}
+
+		if op.deprecated {
+			oc.SetIsDeprecated(true)
+		}
+
+		if op.params != nil {
+			oc.AddReqStructure(op.params)
+		}
+
+		if op.body != nil || len(op.bodyOptions) > 0 {
+			oc.AddReqStructure(op.body, op.bodyOptions...)
+		}
+
+		for _, resp := range op.responses {
+			resp := resp
+
+			oc.AddRespStructure(resp.structure, func(cu *openapi.ContentUnit) {
+				cu.HTTPStatus = resp.status
+				cu.IsDefault = resp.status == 0
+				cu.Description = resp.description
+			})
+		}
+
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	// Cat is not used in operations, so it is reflected into components directly.
+	_, err := r.JSONSchemaReflector().Reflect(petstore.Cat{},
+		jsonschema.RootRef,
+		jsonschema.DefinitionsPrefix("#/components/schemas/"),
+		jsonschema.CollectDefinitions(func(name string, schema jsonschema.Schema) {
+			sm, err := schema.ToSchemaOrBool().ToSimpleMap()
+			require.NoError(t, err)
+
+			r.Spec.ComponentsEns().WithSchemasItem(name, sm)
+		}),
+	)
+	require.NoError(t, err)
+
+	assertjson.EqualMarshal(t, mustMarshal(t, loadPetstore(t)), r.Spec)
+}
+
+func TestTypes_roundTrip(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.JSONSchemaReflector().DefaultOptions = append(r.JSONSchemaReflector().DefaultOptions,
+		jsonschema.InterceptDefName(func(t reflect.Type, _ string) string {
+			return t.Name()
+		}),
+	)
+
+	type components struct {
+		Cat   petstore.Cat   `json:"cat"`
+		Error petstore.Error `json:"error"`
+		Pets  petstore.Pets  `json:"pets,omitempty"`
+	}
+
+	for path, structures := range map[string][]interface{}{
+		"/pets":               {new(petstore.ListPetsParams), new(components)},
+		"/new-pet":            {new(petstore.NewPet)},
+		"/pets/{petId}/photo": {new(petstore.UploadPhotoParams)},
+	} {
+		oc, err := r.NewOperationContext(http.MethodPut, path)
+		require.NoError(t, err)
+
+		oc.AddReqStructure(structures[0])
+
+		if len(structures) > 1 {
+			oc.AddRespStructure(structures[1])
+		}
+
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	source := loadPetstore(t)
+
+	// Components of reflected types match source.
+	delete(r.Spec.Components.Schemas, "components")
+	assertjson.EqualMarshal(t, mustMarshal(t, source.Components.Schemas), r.Spec.Components.Schemas)
+
+	// Parameters of reflected types match source.
+	params := func(s *openapi31.Spec, path string) []openapi31.ParameterOrReference {
+		pi := s.Paths.MapOfPathItemValues[path]
+
+		var res []openapi31.ParameterOrReference
+
+		for _, method := range []string{http.MethodGet, http.MethodPut} {
+			op, err := pi.Operation(method)
+			require.NoError(t, err)
+
+			if op != nil {
+				res = append(res, op.Parameters...)
+			}
+		}
+
+		sort.Slice(res, func(i, j int) bool {
+			return res[i].Parameter.Name < res[j].Parameter.Name
+		})
+
+		return res
+	}
+
+	assertjson.EqualMarshal(t, mustMarshal(t, params(source, "/pets")), params(r.Spec, "/pets"))
+	assertjson.EqualMarshal(t, mustMarshal(t, params(source, "/pets/{petId}/photo")),
+		params(r.Spec, "/pets/{petId}/photo"))
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestClient_call(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /pets":
+			assert.Equal(t, "limit=10&status=sold&tags=a&tags=b", r.URL.RawQuery)
+			assert.Equal(t, "abc", r.Header.Get("X-Request-ID"))
+
+			_, _ = rw.Write([]byte(`[{"id":1,"name":"Rex","status":"sold"}]`)) //nolint:errcheck
+		case "POST /pets":
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"name":"Rex"}`, string(body))
+
+			rw.WriteHeader(http.StatusConflict)
+			_, _ = rw.Write([]byte(`{"code":1,"message":"exists"}`)) //nolint:errcheck
+		case "POST /pets/12/rename":
+			assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "aliases=Max&aliases=Rx&name=Rex", r.PostForm.Encode())
+
+			rw.WriteHeader(http.StatusNoContent)
+		case "DELETE /pets/12":
+			rw.WriteHeader(http.StatusNoContent)
+		default:
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	c := petstore.NewClient(srv.URL + "/")
+	ctx := context.Background()
+	limit := int32(10)
+
+	pets, err := c.ListPets(ctx, petstore.ListPetsParams{
+		Limit: &limit, Tags: []string{"a", "b"}, Status: petstore.PetStatusSold, XRequestID: "abc",
+	})
+	require.NoError(t, err)
+	require.Len(t, pets, 1)
+	assert.Equal(t, "Rex", pets[0].Name)
+	assert.Equal(t, petstore.PetStatusSold, pets[0].Status)
+
+	_, err = c.CreatePet(ctx, petstore.NewPet{Name: "Rex"})
+
+	var se *petstore.StatusError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, http.StatusConflict, se.StatusCode)
+	assert.Equal(t, &petstore.Error{Code: 1, Message: "exists"}, se.Value)
+	assert.EqualError(t, err, "unexpected response status: 409 Conflict")
+
+	require.NoError(t, c.DeletePet(ctx, petstore.DeletePetParams{PetID: 12}))
+	require.NoError(t, c.RenamePet(ctx, petstore.RenamePetParams{PetID: 12}, petstore.RenamePetRequest{
+		Name: "Rex", Aliases: []string{"Max", "Rx"},
+	}))
+
+	_, err = c.ShowPetByID(ctx, petstore.ShowPetByIDParams{PetID: 1})
+	require.True(t, errors.As(err, &se))
+	assert.Nil(t, se.Value)
+}
+
+func TestTypes_openapi3(t *testing.T) {
+	s := openapi3.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.0.3
+info: {title: Users, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id]
+      properties:
+        id: {type: integer}
+        nickname: {type: string, nullable: true}
+`)))
+
+	src, err := codegen.Types(&s, codegen.Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, `// Code generated by github.com/swaggest/openapi-go/codegen. DO NOT EDIT.
+
+// Package api contains types of Users API.
+package api
+
+// User is generated from components/schemas/User.
+type User struct {
+	ID       int     `+"`"+`json:"id" required:"true"`+"`"+`
+	Nickname *string `+"`"+`json:"nickname,omitempty" nullable:"true"`+"`"+`
+}
+`, string(src))
+}
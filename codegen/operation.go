@@ -0,0 +1,561 @@
+package codegen
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// operation describes Go API of a spec operation.
+type operation struct {
+	name   string
+	method string
+	path   string
+	op     *openapi31.Operation
+
+	params     []param
+	paramsType string
+
+	body *body
+
+	// result is a Go type of successful response body, empty if response has no JSON body.
+	result string
+	status string
+
+	// errors are documented non-2xx responses with JSON body.
+	errors []response
+
+	// types are named types of inline request and response objects.
+	types []namedType
+}
+
+type param struct {
+	field    string
+	name     string
+	in       openapi31.ParameterIn
+	typ      string
+	required bool
+	style    openapi31.ParameterStyle
+	explode  bool
+	schema   map[string]interface{}
+	p        *openapi31.Parameter
+}
+
+type body struct {
+	typ         string
+	contentType string
+	json        bool
+	form        bool
+	required    bool
+}
+
+type response struct {
+	key string
+	typ string
+}
+
+type namedType struct {
+	name string
+	doc  string
+	typ  string
+
+	// inline is true for types of inline schemas, such types are not reflected as components.
+	inline bool
+}
+
+var methodOrder = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+	http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace,
+}
+
+func (g *generator) loadOperations() error {
+	if g.spec.Paths == nil {
+		return nil
+	}
+
+	for _, path := range internal.SortedMapKeys(g.spec.Paths.MapOfPathItemValues) {
+		pi := g.spec.Paths.MapOfPathItemValues[path]
+
+		for _, method := range methodOrder {
+			op, err := pi.Operation(method)
+			if err != nil {
+				return err
+			}
+
+			if op == nil {
+				continue
+			}
+
+			o, err := g.operation(method, path, pi, op)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", method, path, err)
+			}
+
+			g.operations = append(g.operations, o)
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) operation(method, path string, pi openapi31.PathItem, op *openapi31.Operation) (*operation, error) {
+	o := &operation{method: method, path: path, op: op}
+
+	if op.ID != nil && *op.ID != "" {
+		o.name = g.uniqueName(goName(*op.ID))
+	} else {
+		o.name = g.uniqueName(goName(strings.ToLower(method) + " " + strings.NewReplacer("{", "", "}", "").Replace(path)))
+	}
+
+	if err := g.operationParams(o, append(pi.Parameters, op.Parameters...)); err != nil {
+		return nil, err
+	}
+
+	if err := g.operationBody(o); err != nil {
+		return nil, err
+	}
+
+	if err := g.operationResponses(o); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func (g *generator) operationParams(o *operation, params []openapi31.ParameterOrReference) error {
+	byKey := map[string]int{}
+	fields := map[string]bool{"_": true}
+
+	for _, pr := range params {
+		p := pr.Parameter
+
+		if pr.Reference != nil {
+			p = &openapi31.Parameter{}
+
+			if err := g.resolver.Resolve(pr.Reference.Ref, p); err != nil {
+				return err
+			}
+		}
+
+		if p == nil {
+			continue
+		}
+
+		prm := param{
+			name:     p.Name,
+			in:       p.In,
+			required: p.In == openapi31.ParameterInPath || (p.Required != nil && *p.Required),
+			schema:   p.Schema,
+			p:        p,
+		}
+
+		if prm.schema == nil {
+			prm.schema = map[string]interface{}{"type": "string"}
+		}
+
+		prm.style = defaultStyle(p.In)
+		if p.Style != nil {
+			prm.style = *p.Style
+		}
+
+		prm.explode = prm.style == openapi31.ParameterStyleForm || prm.style == openapi31.ParameterStyleDeepObject
+		if p.Explode != nil {
+			prm.explode = *p.Explode
+		}
+
+		prm.typ = g.paramType(prm.schema)
+		if !prm.required && !g.omittable(prm.typ) {
+			prm.typ = "*" + prm.typ
+		}
+
+		// Operation parameters override path item parameters with the same name and location.
+		key := string(p.In) + "/" + p.Name
+		if i, ok := byKey[key]; ok {
+			prm.field = o.params[i].field
+			o.params[i] = prm
+
+			continue
+		}
+
+		prm.field = uniqueName(fields, goName(p.Name))
+		byKey[key] = len(o.params)
+		o.params = append(o.params, prm)
+	}
+
+	if len(o.params) > 0 {
+		o.paramsType = g.uniqueName(o.name + "Params")
+	}
+
+	return nil
+}
+
+func defaultStyle(in openapi31.ParameterIn) openapi31.ParameterStyle {
+	if in == openapi31.ParameterInQuery || in == openapi31.ParameterInCookie {
+		return openapi31.ParameterStyleForm
+	}
+
+	return openapi31.ParameterStyleSimple
+}
+
+// paramType returns Go type of parameter schema, objects are represented by maps.
+func (g *generator) paramType(schema map[string]interface{}) string {
+	s, _ := nonNull(g.resolveSchema(schema))
+
+	if ref, ok := s["$ref"].(string); ok {
+		s, _ = nonNull(g.component(ref))
+	}
+
+	if schemaType(s) != "object" {
+		return g.goType(schema)
+	}
+
+	if ap, ok := s["additionalProperties"].(map[string]interface{}); ok && s["properties"] == nil {
+		return "map[string]" + g.goType(ap)
+	}
+
+	return "map[string]interface{}"
+}
+
+func (g *generator) operationBody(o *operation) error {
+	rbr := o.op.RequestBody
+	if rbr == nil {
+		return nil
+	}
+
+	rb := rbr.RequestBody
+
+	if rbr.Reference != nil {
+		rb = &openapi31.RequestBody{}
+
+		if err := g.resolver.Resolve(rbr.Reference.Ref, rb); err != nil {
+			return err
+		}
+	}
+
+	if rb == nil || len(rb.Content) == 0 {
+		return nil
+	}
+
+	b := &body{required: rb.Required != nil && *rb.Required, typ: "io.Reader"}
+
+	if ct, mt, ok := jsonContent(rb.Content); ok {
+		b.contentType = ct
+		b.json = true
+		b.typ = g.bodyType(o, "Request", "request body", mt.Schema)
+	} else if ct, s, ok := g.formContent(rb.Content); ok {
+		b.contentType = ct
+		b.form = true
+		b.typ = g.uniqueName(o.name + "Request")
+		_, isRef := rb.Content[ct].Schema["$ref"]
+
+		o.types = append(o.types, namedType{
+			name:   b.typ,
+			doc:    b.typ + " is a form request body of " + o.name + " operation.",
+			typ:    g.structType(s, false, "formData"),
+			inline: !isRef,
+		})
+	} else {
+		b.contentType = internal.SortedMapKeys(rb.Content)[0]
+	}
+
+	if (b.json || b.form) && !b.required && !g.nilable(b.typ) {
+		b.typ = "*" + b.typ
+	}
+
+	o.body = b
+
+	return nil
+}
+
+// jsonContent finds JSON media type of content.
+func jsonContent(content map[string]openapi31.MediaType) (string, openapi31.MediaType, bool) {
+	for _, ct := range internal.SortedMapKeys(content) {
+		if isJSON(ct) {
+			return ct, content[ct], true
+		}
+	}
+
+	return "", openapi31.MediaType{}, false
+}
+
+// formContent finds URL-encoded form media type of content with object schema.
+func (g *generator) formContent(content map[string]openapi31.MediaType) (string, map[string]interface{}, bool) {
+	for _, ct := range internal.SortedMapKeys(content) {
+		if mediaType(ct) != "application/x-www-form-urlencoded" {
+			continue
+		}
+
+		s, _ := nonNull(g.resolveSchema(content[ct].Schema))
+		if ref, ok := s["$ref"].(string); ok {
+			s, _ = nonNull(g.component(ref))
+		}
+
+		if _, ok := s["properties"]; ok {
+			return ct, s, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// mediaType returns lowercase content type without parameters.
+func mediaType(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+}
+
+func isJSON(contentType string) bool {
+	ct := mediaType(contentType)
+
+	return ct == "application/json" || strings.HasSuffix(ct, "+json")
+}
+
+// bodyType returns Go type of request or response body, inline objects are named after operation.
+func (g *generator) bodyType(o *operation, suffix, what string, schema map[string]interface{}) string {
+	if schema == nil {
+		return "interface{}"
+	}
+
+	typ := g.goType(schema)
+	if !strings.HasPrefix(typ, "struct {") {
+		return typ
+	}
+
+	name := g.uniqueName(o.name + suffix)
+	o.types = append(o.types, namedType{
+		name:   name,
+		doc:    name + " is a " + what + " of " + o.name + " operation.",
+		typ:    typ,
+		inline: true,
+	})
+
+	return name
+}
+
+func (g *generator) operationResponses(o *operation) error {
+	rr := o.op.Responses
+	if rr == nil {
+		return nil
+	}
+
+	all := make(map[string]openapi31.ResponseOrReference, len(rr.MapOfResponseOrReferenceValues)+1)
+
+	for k, v := range rr.MapOfResponseOrReferenceValues {
+		all[k] = v
+	}
+
+	if rr.Default != nil {
+		all["default"] = *rr.Default
+	}
+
+	keys := make([]string, 0, len(all))
+	for k := range all {
+		keys = append(keys, k)
+	}
+
+	// Exact status codes go before ranges, and default goes last.
+	sort.Slice(keys, func(i, j int) bool {
+		return responseOrder(keys[i]) < responseOrder(keys[j])
+	})
+
+	for _, key := range keys {
+		ror := all[key]
+		resp := ror.Response
+
+		if ror.Reference != nil {
+			resp = &openapi31.Response{}
+
+			if err := g.resolver.Resolve(ror.Reference.Ref, resp); err != nil {
+				return err
+			}
+		}
+
+		if resp == nil {
+			continue
+		}
+
+		_, mt, hasJSON := jsonContent(resp.Content)
+
+		if strings.HasPrefix(key, "2") {
+			if o.status == "" {
+				o.status = key
+			}
+
+			if o.result == "" && hasJSON && mt.Schema != nil {
+				o.result = g.bodyType(o, "Response", "response body", mt.Schema)
+				o.status = key
+			}
+
+			continue
+		}
+
+		if hasJSON && mt.Schema != nil {
+			o.errors = append(o.errors, response{
+				key: key,
+				typ: g.bodyType(o, goName(key)+"Response", key+" response body", mt.Schema),
+			})
+		}
+	}
+
+	return nil
+}
+
+func responseOrder(key string) string {
+	switch {
+	case key == "default":
+		return "3" + key
+	case strings.HasSuffix(key, "XX"):
+		return "2" + key
+	}
+
+	return "1" + key
+}
+
+// statusCondition returns Go condition that matches response key, e.g. "resp.StatusCode == 404".
+func statusCondition(v, key string) string {
+	switch {
+	case key == "default":
+		return "true"
+	case strings.HasSuffix(key, "XX"):
+		return v + "/100 == " + key[:1]
+	}
+
+	return v + " == " + key
+}
+
+// signature returns Go method signature of operation and zero result values to return with an error,
+// e.g. "ListPets(ctx context.Context, params ListPetsParams) (Pets, error)" and "nil, ".
+func (g *generator) signature(o *operation) (string, string) {
+	args := []string{"ctx context.Context"}
+	if o.paramsType != "" {
+		args = append(args, "params "+o.paramsType)
+	}
+
+	if o.body != nil {
+		args = append(args, "body "+o.body.typ)
+	}
+
+	zero := "nil, "
+	results := "error"
+	resType := ""
+
+	switch {
+	case o.result == "":
+		zero = ""
+	case g.nilable(o.result):
+		resType = o.result
+	default:
+		resType = "*" + o.result
+	}
+
+	if resType != "" {
+		results = "(" + resType + ", error)"
+	}
+
+	return o.name + "(" + strings.Join(args, ", ") + ") " + results, zero
+}
+
+// pathExpr returns Go expression of request path with parameters of params variable.
+func (o *operation) pathExpr(params string) string {
+	var parts []string
+
+	path := o.path
+
+	for {
+		start := strings.Index(path, "{")
+		end := strings.Index(path, "}")
+
+		if start < 0 || end < start {
+			break
+		}
+
+		if start > 0 {
+			parts = append(parts, quote(path[:start]))
+		}
+
+		name := path[start+1 : end]
+		expr := quote("{" + name + "}")
+
+		for _, p := range o.params {
+			if p.in == openapi31.ParameterInPath && p.name == name {
+				expr = "url.PathEscape(" + p.format(params+"."+p.field) + ")"
+			}
+		}
+
+		parts = append(parts, expr)
+		path = path[end+1:]
+	}
+
+	if path != "" || len(parts) == 0 {
+		parts = append(parts, quote(path))
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+// format returns Go expression of a string value of scalar or array parameter.
+func (p param) format(v string) string {
+	switch {
+	case strings.HasPrefix(p.typ, "*"):
+		return "formatValue(*" + v + ")"
+	case strings.HasPrefix(p.typ, "[]"):
+		return "strings.Join(formatValues(" + v + "), " + quote(p.separator()) + ")"
+	}
+
+	return "formatValue(" + v + ")"
+}
+
+// separator returns separator of array items for parameter style.
+func (p param) separator() string {
+	switch p.style {
+	case openapi31.ParameterStyleSpaceDelimited:
+		return " "
+	case openapi31.ParameterStylePipeDelimited:
+		return "|"
+	}
+
+	return ","
+}
+
+// tags returns field tags of parameter.
+func (p param) tags(g *generator) tags {
+	t := tags{}
+	t.add(string(p.in), p.name)
+
+	if p.required && p.in != openapi31.ParameterInPath {
+		t.add("required", "true")
+	}
+
+	if g.nilable(p.typ) && p.typ != "interface{}" {
+		t.add("nullable", "false")
+	}
+
+	if strings.HasPrefix(p.typ, "[]") && !p.explode && p.in == openapi31.ParameterInQuery {
+		switch p.style {
+		case openapi31.ParameterStyleSpaceDelimited:
+			t.add("collectionFormat", "ssv")
+		case openapi31.ParameterStylePipeDelimited:
+			t.add("collectionFormat", "pipes")
+		default:
+			t.add("collectionFormat", "csv")
+		}
+	}
+
+	s, _ := nonNull(g.resolveSchema(p.schema))
+
+	if p.p.Description != nil && s["description"] == nil {
+		t.add("description", *p.p.Description)
+	}
+
+	if p.p.Deprecated != nil && *p.p.Deprecated && s["deprecated"] == nil {
+		t.add("deprecated", "true")
+	}
+
+	schemaTags(&t, "", s, p.typ)
+	itemsTags(&t, s)
+
+	return t
+}
@@ -0,0 +1,96 @@
+package codegen
+
+import (
+	"bytes"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// Server generates Go source of server interfaces with a method for each operation.
+//
+// Operations are grouped into an interface per their first tag, e.g. PetsServer for "pets" tag,
+// operations without tags go to Server interface. Each interface has an Unimplemented stub that
+// can be embedded in implementation to return ErrNotImplemented for operations that are not yet handled.
+//
+// Server relies on types generated by Types in the same package. Parameters, request and response
+// bodies of generated types can be registered with Reflector.AddOperation to reproduce operations of source spec.
+// Client generated for the same spec implements server interfaces.
+func Server(s openapi.SpecSchema, options Options) ([]byte, error) {
+	g, err := newGenerator(s, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		b      bytes.Buffer
+		groups []string
+	)
+
+	byGroup := map[string][]*operation{}
+
+	for _, o := range g.operations {
+		group := ""
+		if len(o.op.Tags) > 0 {
+			group = o.op.Tags[0]
+		}
+
+		if _, ok := byGroup[group]; !ok {
+			groups = append(groups, group)
+		}
+
+		byGroup[group] = append(byGroup[group], o)
+	}
+
+	b.WriteString("\n// ErrNotImplemented is returned by stubs of operations.\n")
+	b.WriteString("var ErrNotImplemented = errors.New(\"not implemented\")\n")
+
+	for _, group := range groups {
+		name := "Server"
+		doc := name + " handles operations of " + g.title() + " API without tags."
+
+		if group != "" {
+			name = g.uniqueName(goName(group) + "Server")
+			doc = name + " handles operations of " + g.title() + " API with " + quote(group) + " tag."
+		}
+
+		g.serverInterface(&b, name, doc, byGroup[group])
+	}
+
+	return g.file("", b.Bytes())
+}
+
+// serverInterface generates interface of operations and its stub.
+func (g *generator) serverInterface(b *bytes.Buffer, name, doc string, operations []*operation) {
+	b.WriteString("\n")
+	comment(b, doc)
+	b.WriteString("type " + name + " interface {\n")
+
+	for i, o := range operations {
+		signature, _ := g.signature(o)
+
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		operationDoc(b, o, o.name+" handles "+o.method+" "+o.path+".")
+		b.WriteString(signature + "\n")
+	}
+
+	b.WriteString("}\n")
+
+	stub := g.uniqueName("Unimplemented" + name)
+
+	b.WriteString("\n")
+	comment(b, stub+" returns ErrNotImplemented for all operations of "+name+".")
+	b.WriteString("type " + stub + " struct{}\n")
+	b.WriteString("\nvar _ " + name + " = " + stub + "{}\n")
+
+	for _, o := range operations {
+		signature, zero := g.signature(o)
+
+		b.WriteString("\n")
+		comment(b, o.name+" implements "+name+".")
+		b.WriteString("func (" + stub + ") " + signature + " {\n")
+		b.WriteString("return " + zero + "ErrNotImplemented\n}\n")
+	}
+}
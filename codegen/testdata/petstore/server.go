@@ -0,0 +1,88 @@
+// Code generated by github.com/swaggest/openapi-go/codegen. DO NOT EDIT.
+
+package petstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotImplemented is returned by stubs of operations.
+var ErrNotImplemented = errors.New("not implemented")
+
+// PetsServer handles operations of Petstore API with "pets" tag.
+type PetsServer interface {
+	// ListPets handles GET /pets.
+	//
+	// List all pets.
+	ListPets(ctx context.Context, params ListPetsParams) (Pets, error)
+
+	// CreatePet handles POST /pets.
+	CreatePet(ctx context.Context, body NewPet) (*Pet, error)
+
+	// ShowPetByID handles GET /pets/{petId}.
+	ShowPetByID(ctx context.Context, params ShowPetByIDParams) (*Pet, error)
+
+	// DeletePet handles DELETE /pets/{petId}.
+	//
+	// Deprecated: operation is deprecated.
+	DeletePet(ctx context.Context, params DeletePetParams) error
+}
+
+// UnimplementedPetsServer returns ErrNotImplemented for all operations of PetsServer.
+type UnimplementedPetsServer struct{}
+
+var _ PetsServer = UnimplementedPetsServer{}
+
+// ListPets implements PetsServer.
+func (UnimplementedPetsServer) ListPets(ctx context.Context, params ListPetsParams) (Pets, error) {
+	return nil, ErrNotImplemented
+}
+
+// CreatePet implements PetsServer.
+func (UnimplementedPetsServer) CreatePet(ctx context.Context, body NewPet) (*Pet, error) {
+	return nil, ErrNotImplemented
+}
+
+// ShowPetByID implements PetsServer.
+func (UnimplementedPetsServer) ShowPetByID(ctx context.Context, params ShowPetByIDParams) (*Pet, error) {
+	return nil, ErrNotImplemented
+}
+
+// DeletePet implements PetsServer.
+func (UnimplementedPetsServer) DeletePet(ctx context.Context, params DeletePetParams) error {
+	return ErrNotImplemented
+}
+
+// PhotosServer handles operations of Petstore API with "photos" tag.
+type PhotosServer interface {
+	// UploadPhoto handles PUT /pets/{petId}/photo.
+	UploadPhoto(ctx context.Context, params UploadPhotoParams, body io.Reader) (*UploadPhotoResponse, error)
+}
+
+// UnimplementedPhotosServer returns ErrNotImplemented for all operations of PhotosServer.
+type UnimplementedPhotosServer struct{}
+
+var _ PhotosServer = UnimplementedPhotosServer{}
+
+// UploadPhoto implements PhotosServer.
+func (UnimplementedPhotosServer) UploadPhoto(ctx context.Context, params UploadPhotoParams, body io.Reader) (*UploadPhotoResponse, error) {
+	return nil, ErrNotImplemented
+}
+
+// Server handles operations of Petstore API without tags.
+type Server interface {
+	// RenamePet handles POST /pets/{petId}/rename.
+	RenamePet(ctx context.Context, params RenamePetParams, body RenamePetRequest) error
+}
+
+// UnimplementedServer returns ErrNotImplemented for all operations of Server.
+type UnimplementedServer struct{}
+
+var _ Server = UnimplementedServer{}
+
+// RenamePet implements Server.
+func (UnimplementedServer) RenamePet(ctx context.Context, params RenamePetParams, body RenamePetRequest) error {
+	return ErrNotImplemented
+}
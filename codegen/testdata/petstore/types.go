@@ -0,0 +1,117 @@
+// Code generated by github.com/swaggest/openapi-go/codegen. DO NOT EDIT.
+
+// Package petstore contains types of Petstore API.
+package petstore
+
+import (
+	"time"
+)
+
+// Cat is generated from components/schemas/Cat.
+type Cat struct {
+	Pet    `refer:"true"`
+	Indoor *bool `json:"indoor,omitempty" nullable:"false"`
+}
+
+// Error is generated from components/schemas/Error.
+type Error struct {
+	Code    int32  `json:"code" required:"true"`
+	Message string `json:"message" required:"true"`
+}
+
+// NewPet is generated from components/schemas/NewPet.
+//
+// A pet to create.
+type NewPet struct {
+	_    struct{} `description:"A pet to create." additionalProperties:"false"`
+	Name string   `json:"name" required:"true" examples:"[\"Rex\"]" minLength:"1"`
+	Tag  *string  `json:"tag,omitempty" nullable:"false"`
+	Tags []string `json:"tags,omitempty" items.minLength:"1"`
+}
+
+// Owner is generated from components/schemas/Owner.
+type Owner struct {
+	Name  *string `json:"name,omitempty" nullable:"false"`
+	Phone *string `json:"phone,omitempty" nullable:"true" pattern:"^\\+[0-9]+$"`
+}
+
+// Pet is generated from components/schemas/Pet.
+type Pet struct {
+	BornAt   *time.Time        `json:"bornAt,omitempty" nullable:"false"`
+	ID       int64             `json:"id" required:"true" readOnly:"true"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Location *struct {
+		Lat *float64 `json:"lat,omitempty" nullable:"false" format:"double"`
+		Lon *float64 `json:"lon,omitempty" nullable:"false" format:"double"`
+	} `json:"location,omitempty"`
+	Name   string    `json:"name" required:"true" description:"Name of pet."`
+	Owner  *Owner    `json:"owner,omitempty"`
+	Status PetStatus `json:"status,omitempty"`
+	Weight *float64  `json:"weight,omitempty" nullable:"false" format:"double" exclusiveMinimum:"0"`
+}
+
+// PetStatus is generated from components/schemas/PetStatus.
+type PetStatus string
+
+// PetStatus values enumeration.
+const (
+	PetStatusAvailable = PetStatus("available")
+	PetStatusPending   = PetStatus("pending")
+	PetStatusSold      = PetStatus("sold")
+)
+
+// Enum returns allowed values of PetStatus.
+func (PetStatus) Enum() []interface{} {
+	return []interface{}{"available", "pending", "sold"}
+}
+
+// Pets is generated from components/schemas/Pets.
+type Pets []Pet
+
+// ListPetsParams contains parameters of ListPets operation.
+type ListPetsParams struct {
+	Limit      *int32    `query:"limit" nullable:"false" description:"How many items to return." format:"int32" maximum:"100"`
+	Tags       []string  `query:"tags" nullable:"false"`
+	Status     PetStatus `query:"status"`
+	XRequestID string    `header:"X-Request-ID" required:"true" format:"uuid"`
+}
+
+// ShowPetByIDParams contains parameters of ShowPetByID operation.
+type ShowPetByIDParams struct {
+	PetID int64 `path:"petId" minimum:"1"`
+}
+
+// DeletePetParams contains parameters of DeletePet operation.
+type DeletePetParams struct {
+	PetID int64 `path:"petId" minimum:"1"`
+}
+
+// UploadPhotoResponse is a response body of UploadPhoto operation.
+type UploadPhotoResponse struct {
+	Size *int   `json:"size,omitempty" nullable:"false"`
+	URL  string `json:"url" required:"true" format:"uri"`
+}
+
+// InlineJSONSchema keeps reflected schema of UploadPhotoResponse inline, as it is in source spec.
+func (UploadPhotoResponse) InlineJSONSchema() {}
+
+// UploadPhotoParams contains parameters of UploadPhoto operation.
+type UploadPhotoParams struct {
+	PetID   int64   `path:"petId"`
+	Session *string `cookie:"session" nullable:"false"`
+}
+
+// RenamePetRequest is a form request body of RenamePet operation.
+type RenamePetRequest struct {
+	Aliases []string `formData:"aliases,omitempty"`
+	Name    string   `formData:"name" required:"true" minLength:"1"`
+	Notify  *bool    `formData:"notify,omitempty" nullable:"false"`
+}
+
+// InlineJSONSchema keeps reflected schema of RenamePetRequest inline, as it is in source spec.
+func (RenamePetRequest) InlineJSONSchema() {}
+
+// RenamePetParams contains parameters of RenamePet operation.
+type RenamePetParams struct {
+	PetID int64 `path:"petId"`
+}
@@ -0,0 +1,152 @@
+package codegen
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// Types generates Go source of types of component schemas, operation parameters and inline bodies.
+//
+// Generated file also has package doc, other generated files of the package (e.g. Client) rely on its types.
+func Types(s openapi.SpecSchema, options Options) ([]byte, error) {
+	g, err := newGenerator(s, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+
+	g.componentTypes(&b)
+
+	for _, o := range g.operations {
+		g.operationTypes(&b, o)
+	}
+
+	return g.file("// Package "+g.options.Package+" contains types of "+g.title()+" API.\n", b.Bytes())
+}
+
+func (g *generator) componentTypes(b *bytes.Buffer) {
+	if g.spec.Components == nil {
+		return
+	}
+
+	for _, name := range internal.SortedMapKeys(g.spec.Components.Schemas) {
+		typ := g.schemas[name]
+		s, _ := nonNull(g.spec.Components.Schemas[name])
+
+		b.WriteString("\n")
+		comment(b, typ+" is generated from components/schemas/"+name+".")
+
+		if desc, ok := s["description"].(string); ok {
+			b.WriteString("//\n")
+			comment(b, desc)
+		}
+
+		if g.enumType(b, typ, s) {
+			continue
+		}
+
+		if isStruct(s) {
+			b.WriteString("type " + typ + " " + g.structType(s, true, "json") + "\n")
+
+			continue
+		}
+
+		goType := g.goType(s)
+		if goType == typ {
+			goType = "interface{}"
+		}
+
+		b.WriteString("type " + typ + " " + goType + "\n")
+	}
+}
+
+// isStruct is true for object schemas that are represented by Go structs.
+func isStruct(s map[string]interface{}) bool {
+	if _, ok := s["$ref"]; ok || schemaType(s) != "object" {
+		return false
+	}
+
+	if _, ok := s["additionalProperties"].(map[string]interface{}); ok && s["properties"] == nil {
+		return false
+	}
+
+	return s["properties"] != nil || s["allOf"] != nil || s["additionalProperties"] == false
+}
+
+// enum returns values and Go type of scalar enum schema.
+func (g *generator) enum(s map[string]interface{}) ([]interface{}, string, bool) {
+	values, ok := s["enum"].([]interface{})
+	if !ok || len(values) == 0 {
+		return nil, "", false
+	}
+
+	goType := g.goType(s)
+	if goType != "string" && goType != "int" && goType != "int64" && goType != "int32" {
+		return nil, "", false
+	}
+
+	return values, goType, true
+}
+
+// enumType generates a named type with constants for scalar enum schema.
+func (g *generator) enumType(b *bytes.Buffer, typ string, s map[string]interface{}) bool {
+	values, goType, ok := g.enum(s)
+	if !ok {
+		return false
+	}
+
+	b.WriteString("type " + typ + " " + goType + "\n\n")
+	comment(b, typ+" values enumeration.")
+	b.WriteString("const (\n")
+
+	literals := make([]string, 0, len(values))
+
+	for _, v := range values {
+		lit := tagValue(v)
+		if goType == "string" {
+			lit = quote(lit)
+		}
+
+		literals = append(literals, lit)
+		b.WriteString(g.uniqueName(typ+goName(tagValue(v))) + " = " + typ + "(" + lit + ")\n")
+	}
+
+	b.WriteString(")\n\n")
+	comment(b, "Enum returns allowed values of "+typ+".")
+	b.WriteString("func (" + typ + ") Enum() []interface{} {\n")
+	b.WriteString("return []interface{}{" + strings.Join(literals, ", ") + "}\n}\n")
+
+	return true
+}
+
+func (g *generator) operationTypes(b *bytes.Buffer, o *operation) {
+	for _, nt := range o.types {
+		b.WriteString("\n")
+		comment(b, nt.doc)
+		b.WriteString("type " + nt.name + " " + nt.typ + "\n")
+
+		if nt.inline {
+			b.WriteString("\n")
+			comment(b, "InlineJSONSchema keeps reflected schema of "+nt.name+" inline, as it is in source spec.")
+			b.WriteString("func (" + nt.name + ") InlineJSONSchema() {}\n")
+		}
+	}
+
+	if o.paramsType == "" {
+		return
+	}
+
+	b.WriteString("\n")
+	comment(b, o.paramsType+" contains parameters of "+o.name+" operation.")
+	b.WriteString("type " + o.paramsType + " struct {\n")
+
+	for _, p := range o.params {
+		b.WriteString(p.field + " " + p.typ + " " + p.tags(g).String() + "\n")
+	}
+
+	b.WriteString("}\n")
+}
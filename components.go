@@ -0,0 +1,40 @@
+package openapi
+
+// ComponentKind is a section of reusable components, e.g. "parameters".
+type ComponentKind string
+
+// ComponentKind values enumeration.
+const (
+	ComponentParameters    = ComponentKind("parameters")
+	ComponentHeaders       = ComponentKind("headers")
+	ComponentResponses     = ComponentKind("responses")
+	ComponentRequestBodies = ComponentKind("requestBodies")
+)
+
+// ReuseComponents configures deduplication of identical entities into reusable components.
+//
+// Entity that occurs in more than one place, or is equal to an existing component,
+// is stored in `#/components/<kind>` and replaced with a `$ref`.
+type ReuseComponents struct {
+	// Kinds limits deduplication to particular kinds, all kinds are deduplicated if empty.
+	Kinds []ComponentKind
+
+	// Name customizes name of a new component, it receives default name and
+	// revision-specific entity, e.g. *openapi3.Parameter or *openapi31.Response.
+	Name func(kind ComponentKind, defaultName string, entity interface{}) string
+}
+
+// IsEnabled checks if deduplication is enabled for a kind of component.
+func (rc ReuseComponents) IsEnabled(kind ComponentKind) bool {
+	if len(rc.Kinds) == 0 {
+		return true
+	}
+
+	for _, k := range rc.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
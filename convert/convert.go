@@ -0,0 +1,257 @@
+// Package convert provides conversion of documents between OpenAPI 3.0 and OpenAPI 3.1.
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Warning describes a feature that could not be converted losslessly.
+type Warning struct {
+	// Pointer is a JSON Pointer to the affected location in source document.
+	Pointer string
+
+	// Message describes the problem.
+	Message string
+}
+
+// String implements fmt.Stringer.
+func (w Warning) String() string {
+	return w.Pointer + ": " + w.Message
+}
+
+// ToOpenAPI31 converts OpenAPI 3.0 spec to OpenAPI 3.1.
+//
+// Schemas are converted to JSON Schema 2020-12, `nullable` becomes a type array (or `anyOf` with `null`),
+// `example` becomes `examples`, boolean `exclusiveMaximum`/`exclusiveMinimum` become numeric.
+func ToOpenAPI31(s *openapi3.Spec) (*openapi31.Spec, []Warning, error) {
+	doc, err := internal.ToDocument(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := schemaConverter{}
+
+	doc["openapi"] = "3.1.0"
+
+	internal.WalkDocument(doc, internal.DocumentVisitor{
+		Schema: c.upgrade,
+		Object: func(ptr string, kind internal.ObjectKind, obj map[string]interface{}) {
+			switch kind { //nolint:exhaustive // Other objects are compatible.
+			case internal.KindParameter, internal.KindHeader:
+				for _, k := range []string{"allowEmptyValue", "allowReserved"} {
+					if _, ok := obj[k]; ok {
+						c.warn(ptr+"/"+k, "unsupported property removed: "+k)
+						delete(obj, k)
+					}
+				}
+			case internal.KindLink:
+				upgradeLink(&c, ptr, obj)
+			}
+		},
+	})
+
+	if paths, ok := doc["paths"].(map[string]interface{}); ok {
+		for _, k := range internal.SortedKeys(paths) {
+			if pi, ok := paths[k].(map[string]interface{}); ok && pi["$ref"] != nil {
+				c.warn("/paths/"+internal.EscapeJSONPointer(k)+"/$ref", "path item reference removed")
+				delete(pi, "$ref")
+			}
+		}
+	}
+
+	res := &openapi31.Spec{}
+
+	if err := internal.FromDocument(doc, res); err != nil {
+		return nil, c.warnings, fmt.Errorf("loading converted document: %w", err)
+	}
+
+	return res, c.warnings, nil
+}
+
+func upgradeLink(c *schemaConverter, ptr string, obj map[string]interface{}) {
+	if _, ok := obj["server"]; ok {
+		c.warn(ptr+"/server", "unsupported property removed: server")
+		delete(obj, "server")
+	}
+
+	params, ok := obj["parameters"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for _, name := range internal.SortedKeys(params) {
+		if _, ok := params[name].(string); ok {
+			continue
+		}
+
+		j, err := json.Marshal(params[name])
+		if err != nil {
+			continue
+		}
+
+		c.warn(ptr+"/parameters/"+internal.EscapeJSONPointer(name), "non-string link parameter converted to JSON string")
+		params[name] = string(j)
+	}
+}
+
+// ToOpenAPI3 converts OpenAPI 3.1 spec to OpenAPI 3.0.
+//
+// Features that are not available in OpenAPI 3.0 (for example webhooks, JSON Schema 2020-12 keywords,
+// `mutualTLS` security schemes) are removed and reported as warnings.
+func ToOpenAPI3(s *openapi31.Spec) (*openapi3.Spec, []Warning, error) {
+	doc, err := internal.ToDocument(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := schemaConverter{}
+
+	doc["openapi"] = "3.0.3"
+
+	for _, k := range []string{"webhooks", "jsonSchemaDialect"} {
+		if _, ok := doc[k]; ok {
+			c.warn("/"+k, "unsupported property removed: "+k)
+			delete(doc, k)
+		}
+	}
+
+	if _, ok := doc["paths"]; !ok {
+		doc["paths"] = map[string]interface{}{}
+	}
+
+	downgradeInfo(&c, doc)
+
+	if components, ok := doc["components"].(map[string]interface{}); ok {
+		if _, ok := components["pathItems"]; ok {
+			c.warn("/components/pathItems", "unsupported property removed: pathItems")
+			delete(components, "pathItems")
+		}
+	}
+
+	internal.WalkDocument(doc, internal.DocumentVisitor{
+		Schema: c.downgrade,
+		Reference: func(ptr string, ref map[string]interface{}) {
+			for _, k := range []string{"summary", "description"} {
+				if _, ok := ref[k]; ok {
+					c.warn(ptr+"/"+k, "unsupported reference property removed: "+k)
+					delete(ref, k)
+				}
+			}
+		},
+		Object: func(ptr string, kind internal.ObjectKind, obj map[string]interface{}) {
+			if kind == internal.KindLink {
+				if _, ok := obj["body"]; ok {
+					c.warn(ptr+"/body", "unsupported property removed: body")
+					delete(obj, "body")
+				}
+			}
+		},
+	})
+
+	downgradeSecuritySchemes(&c, doc)
+
+	res := &openapi3.Spec{}
+
+	if err := internal.FromDocument(doc, res); err != nil {
+		return nil, c.warnings, fmt.Errorf("loading converted document: %w", err)
+	}
+
+	return res, c.warnings, nil
+}
+
+func downgradeInfo(c *schemaConverter, doc map[string]interface{}) {
+	info, ok := doc["info"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if _, ok := info["summary"]; ok {
+		c.warn("/info/summary", "unsupported property removed: summary")
+		delete(info, "summary")
+	}
+
+	if license, ok := info["license"].(map[string]interface{}); ok {
+		if _, ok := license["identifier"]; ok {
+			c.warn("/info/license/identifier", "unsupported property removed: identifier")
+			delete(license, "identifier")
+		}
+	}
+}
+
+func downgradeSecuritySchemes(c *schemaConverter, doc map[string]interface{}) {
+	components, ok := doc["components"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	schemes, ok := components["securitySchemes"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for _, name := range internal.SortedKeys(schemes) {
+		if ss, ok := schemes[name].(map[string]interface{}); ok && ss["type"] == "mutualTLS" {
+			c.warn("/components/securitySchemes/"+internal.EscapeJSONPointer(name),
+				"unsupported security scheme removed: mutualTLS")
+			delete(schemes, name)
+		}
+	}
+}
+
+// SchemaToOpenAPI31 converts OpenAPI 3.0 schema to OpenAPI 3.1 (JSON Schema 2020-12) schema.
+func SchemaToOpenAPI31(s openapi3.SchemaOrRef) (map[string]interface{}, error) {
+	var v interface{}
+
+	if err := internal.FromDocument(s, &v); err != nil {
+		return nil, err
+	}
+
+	c := schemaConverter{}
+
+	res, ok := c.upgrade("", v).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected schema type %T", v)
+	}
+
+	return res, nil
+}
+
+// SchemaToOpenAPI3 converts OpenAPI 3.1 (JSON Schema 2020-12) schema to OpenAPI 3.0 schema.
+func SchemaToOpenAPI3(s map[string]interface{}) (openapi3.SchemaOrRef, []Warning, error) {
+	var (
+		v   interface{}
+		res openapi3.SchemaOrRef
+	)
+
+	if err := internal.FromDocument(s, &v); err != nil {
+		return res, nil, err
+	}
+
+	c := schemaConverter{}
+
+	err := internal.FromDocument(c.downgrade("", v), &res)
+
+	return res, c.warnings, err
+}
+
+// AsOpenAPI31 provides OpenAPI 3.1 representation of a spec.
+//
+// OpenAPI 3.1 spec is returned as is, OpenAPI 3.0 spec is converted.
+func AsOpenAPI31(s openapi.SpecSchema) (*openapi31.Spec, error) {
+	switch sp := s.(type) {
+	case *openapi31.Spec:
+		return sp, nil
+	case *openapi3.Spec:
+		res, _, err := ToOpenAPI31(sp)
+
+		return res, err
+	default:
+		return nil, fmt.Errorf("unsupported spec type %T", s)
+	}
+}
@@ -0,0 +1,204 @@
+package convert_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/convert"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+type item struct {
+	ID     int     `json:"id" minimum:"1" example:"123"`
+	Name   *string `json:"name"`
+	Status string  `json:"status" enum:"active,inactive"`
+}
+
+func TestToOpenAPI31(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.Spec.Info.WithTitle("Items").WithVersion("1.0.0")
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(new(struct {
+		ID int `path:"id"`
+	}))
+	oc.AddRespStructure(new(item))
+
+	require.NoError(t, r.AddOperation(oc))
+
+	s, warnings, err := convert.ToOpenAPI31(r.Spec)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.1.0","info":{"title":"Items","version":"1.0.0"},
+	  "paths":{
+		"/items/{id}":{
+		  "get":{
+			"parameters":[
+			  {"name":"id","in":"path","required":true,"schema":{"type":"integer"}}
+			],
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/json":{"schema":{"$ref":"#/components/schemas/ConvertTestItem"}}
+				}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "ConvertTestItem":{
+			"properties":{
+			  "id":{"examples":[123],"minimum":1,"type":"integer"},
+			  "name":{"type":["string","null"]},
+			  "status":{"enum":["active","inactive"],"type":"string"}
+			},
+			"type":"object"
+		  }
+		}
+	  }
+	}`, s)
+}
+
+func TestToOpenAPI3(t *testing.T) {
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.1.0
+info:
+  title: Events
+  summary: Event streaming.
+  version: 1.0.0
+paths:
+  /events:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Event'
+                description: List of events.
+webhooks:
+  newEvent:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Event'
+      responses:
+        "204":
+          description: Accepted
+components:
+  schemas:
+    Event:
+      type: object
+      properties:
+        id:
+          type: [string, "null"]
+          examples: [abc, def]
+        kind:
+          const: created
+        payload:
+          type: [object, array]
+        score:
+          type: number
+          exclusiveMaximum: 10
+        tags:
+          type: array
+          prefixItems:
+            - type: string
+`)))
+
+	s3, warnings, err := convert.ToOpenAPI3(&s)
+	require.NoError(t, err)
+
+	var messages []string
+	for _, w := range warnings {
+		messages = append(messages, w.String())
+	}
+
+	assert.Equal(t, []string{
+		"/webhooks: unsupported property removed: webhooks",
+		"/info/summary: unsupported property removed: summary",
+		"/components/schemas/Event/properties/id/examples: only first of multiple schema examples is kept",
+		"/components/schemas/Event/properties/tags/prefixItems: unsupported schema keyword removed: prefixItems",
+	}, messages)
+
+	assertjson.EqMarshal(t, `{
+	  "openapi":"3.0.3","info":{"title":"Events","version":"1.0.0"},
+	  "paths":{
+		"/events":{
+		  "get":{
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/json":{
+					"schema":{
+					  "description":"List of events.",
+					  "allOf":[{"$ref":"#/components/schemas/Event"}]
+					}
+				  }
+				}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Event":{
+			"properties":{
+			  "id":{"type":"string","nullable":true,"example":"abc"},
+			  "kind":{"enum":["created"]},
+			  "payload":{"anyOf":[{"type":"array"},{"type":"object"}]},
+			  "score":{"exclusiveMaximum":true,"maximum":10,"type":"number"},
+			  "tags":{"type":"array"}
+			},
+			"type":"object"
+		  }
+		}
+	  }
+	}`, s3)
+}
+
+func TestSchemaToOpenAPI31(t *testing.T) {
+	s := openapi3.SchemaOrRef{}
+	require.NoError(t, s.UnmarshalJSON([]byte(`{"allOf":[{"$ref":"#/components/schemas/Foo"}],"nullable":true}`)))
+
+	s31, err := convert.SchemaToOpenAPI31(s)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{"anyOf":[{"allOf":[{"$ref":"#/components/schemas/Foo"}]},{"type":"null"}]}`, s31)
+
+	s3, warnings, err := convert.SchemaToOpenAPI3(map[string]interface{}{
+		"type": []interface{}{"integer", "null"}, "enum": []interface{}{1, 2, nil},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assertjson.EqMarshal(t, `{"enum":[1,2,null],"type":"integer","nullable":true}`, s3)
+}
+
+func TestAsOpenAPI31(t *testing.T) {
+	s31 := &openapi31.Spec{}
+
+	s, err := convert.AsOpenAPI31(s31)
+	require.NoError(t, err)
+	assert.Same(t, s31, s)
+
+	s, err = convert.AsOpenAPI31(&openapi3.Spec{Openapi: "3.0.3"})
+	require.NoError(t, err)
+	assert.Equal(t, "3.1.0", s.Openapi)
+}
@@ -0,0 +1,239 @@
+package convert
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// schemaKeys30 lists keywords of OpenAPI 3.0 Schema Object.
+var schemaKeys30 = map[string]bool{
+	"title": true, "multipleOf": true, "maximum": true, "exclusiveMaximum": true, "minimum": true,
+	"exclusiveMinimum": true, "maxLength": true, "minLength": true, "pattern": true, "maxItems": true,
+	"minItems": true, "uniqueItems": true, "maxProperties": true, "minProperties": true, "required": true,
+	"enum": true, "type": true, "not": true, "allOf": true, "oneOf": true, "anyOf": true, "items": true,
+	"properties": true, "additionalProperties": true, "description": true, "format": true, "default": true,
+	"nullable": true, "discriminator": true, "readOnly": true, "writeOnly": true, "example": true,
+	"externalDocs": true, "deprecated": true, "xml": true,
+}
+
+type schemaConverter struct {
+	warnings []Warning
+}
+
+func (c *schemaConverter) warn(ptr, msg string) {
+	c.warnings = append(c.warnings, Warning{Pointer: ptr, Message: msg})
+}
+
+// subSchemas walks nested schemas of a generic schema and replaces them with results of f.
+func subSchemas(ptr string, s map[string]interface{}, f func(ptr string, s interface{}) interface{}) {
+	for _, k := range []string{"not", "items", "additionalProperties", "if", "then", "else",
+		"contains", "propertyNames", "unevaluatedItems", "unevaluatedProperties", "contentSchema"} {
+		if v, ok := s[k]; ok {
+			if _, isBool := v.(bool); isBool && k == "additionalProperties" {
+				continue
+			}
+
+			s[k] = f(ptr+"/"+k, v)
+		}
+	}
+
+	for _, k := range []string{"allOf", "oneOf", "anyOf", "prefixItems"} {
+		if items, ok := s[k].([]interface{}); ok {
+			for i, v := range items {
+				items[i] = f(ptr+"/"+k+"/"+strconv.Itoa(i), v)
+			}
+		}
+	}
+
+	for _, k := range []string{"properties", "patternProperties", "dependentSchemas", "$defs"} {
+		if props, ok := s[k].(map[string]interface{}); ok {
+			for _, name := range internal.SortedKeys(props) {
+				props[name] = f(ptr+"/"+k+"/"+internal.EscapeJSONPointer(name), props[name])
+			}
+		}
+	}
+}
+
+// upgrade converts generic OpenAPI 3.0 schema to OpenAPI 3.1 (JSON Schema 2020-12) schema.
+func (c *schemaConverter) upgrade(ptr string, v interface{}) interface{} {
+	s, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	subSchemas(ptr, s, c.upgrade)
+
+	if ex, ok := s["example"]; ok {
+		delete(s, "example")
+
+		s["examples"] = []interface{}{ex}
+	}
+
+	for _, k := range []string{"Maximum", "Minimum"} {
+		exclusive, ok := s["exclusive"+k].(bool)
+		if !ok {
+			continue
+		}
+
+		delete(s, "exclusive"+k)
+
+		if limit, ok := s[strings.ToLower(k)]; ok && exclusive {
+			delete(s, strings.ToLower(k))
+
+			s["exclusive"+k] = limit
+		}
+	}
+
+	nullable, _ := s["nullable"].(bool) //nolint:errcheck // False by default.
+	delete(s, "nullable")
+
+	if !nullable {
+		return s
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok && !hasNull(enum) {
+		s["enum"] = append(enum, nil)
+	}
+
+	if t, ok := s["type"].(string); ok {
+		s["type"] = []interface{}{t, "null"}
+
+		return s
+	}
+
+	return map[string]interface{}{
+		"anyOf": []interface{}{s, map[string]interface{}{"type": "null"}},
+	}
+}
+
+func hasNull(items []interface{}) bool {
+	for _, item := range items {
+		if item == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// downgrade converts generic OpenAPI 3.1 (JSON Schema 2020-12) schema to OpenAPI 3.0 schema.
+func (c *schemaConverter) downgrade(ptr string, v interface{}) interface{} {
+	if b, ok := v.(bool); ok {
+		if b {
+			return map[string]interface{}{}
+		}
+
+		return map[string]interface{}{"not": map[string]interface{}{}}
+	}
+
+	s, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	subSchemas(ptr, s, c.downgrade)
+
+	if ref, ok := s["$ref"]; ok && len(s) > 1 {
+		delete(s, "$ref")
+
+		s["allOf"] = append([]interface{}{map[string]interface{}{"$ref": ref}}, toSlice(s["allOf"])...)
+	}
+
+	if cnst, ok := s["const"]; ok {
+		delete(s, "const")
+
+		if _, ok := s["enum"]; !ok {
+			s["enum"] = []interface{}{cnst}
+		}
+	}
+
+	if examples, ok := s["examples"].([]interface{}); ok {
+		delete(s, "examples")
+
+		if len(examples) > 1 {
+			c.warn(ptr+"/examples", "only first of multiple schema examples is kept")
+		}
+
+		if len(examples) > 0 {
+			s["example"] = examples[0]
+		}
+	}
+
+	for _, k := range []string{"Maximum", "Minimum"} {
+		if limit, ok := s["exclusive"+k].(float64); ok {
+			s[strings.ToLower(k)] = limit
+			s["exclusive"+k] = true
+		}
+	}
+
+	c.downgradeType(ptr, s)
+
+	if _, ok := s["$ref"]; ok {
+		return s
+	}
+
+	for _, k := range internal.SortedKeys(s) {
+		if !schemaKeys30[k] && !strings.HasPrefix(k, "x-") {
+			c.warn(ptr+"/"+internal.EscapeJSONPointer(k), "unsupported schema keyword removed: "+k)
+			delete(s, k)
+		}
+	}
+
+	return s
+}
+
+func (c *schemaConverter) downgradeType(ptr string, s map[string]interface{}) {
+	types, ok := s["type"].([]interface{})
+	if !ok {
+		return
+	}
+
+	var nonNull []string
+
+	nullable := false
+
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+		} else if ts, ok := t.(string); ok {
+			nonNull = append(nonNull, ts)
+		}
+	}
+
+	delete(s, "type")
+
+	if nullable {
+		s["nullable"] = true
+	}
+
+	switch len(nonNull) {
+	case 0:
+		if nullable {
+			c.warn(ptr+"/type", "null type is only expressible as nullable")
+		}
+	case 1:
+		s["type"] = nonNull[0]
+	default:
+		sort.Strings(nonNull)
+
+		anyOf := make([]interface{}, 0, len(nonNull))
+		for _, t := range nonNull {
+			anyOf = append(anyOf, map[string]interface{}{"type": t})
+		}
+
+		if _, ok := s["anyOf"]; ok {
+			s["allOf"] = append(toSlice(s["allOf"]), map[string]interface{}{"anyOf": anyOf})
+		} else {
+			s["anyOf"] = anyOf
+		}
+	}
+}
+
+func toSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{}) //nolint:errcheck // Nil is a valid result.
+
+	return s
+}
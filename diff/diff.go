@@ -0,0 +1,567 @@
+// Package diff detects changes between two revisions of an OpenAPI document.
+package diff
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/convert"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Change describes a difference between two documents.
+type Change struct {
+	// Pointer is a JSON Pointer to the changed location, it refers to the head document
+	// for added elements and to the base document otherwise.
+	Pointer string
+
+	// Message describes the change.
+	Message string
+
+	// Breaking is true if change can break existing API consumers.
+	Breaking bool
+}
+
+// String implements fmt.Stringer.
+func (c Change) String() string {
+	s := c.Pointer + ": " + c.Message
+	if c.Breaking {
+		s = "[breaking] " + s
+	}
+
+	return s
+}
+
+// Report contains changes between two documents.
+type Report struct {
+	Changes []Change
+}
+
+// HasBreaking is true if there are breaking changes in the report.
+func (r Report) HasBreaking() bool {
+	return len(r.Breaking()) > 0
+}
+
+// Breaking returns breaking changes.
+func (r Report) Breaking() []Change {
+	var res []Change
+
+	for _, c := range r.Changes {
+		if c.Breaking {
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
+
+// Compare detects changes between base and head revisions of a spec.
+//
+// Specs can be *openapi3.Spec or *openapi31.Spec, OpenAPI 3.0 documents are compared in their
+// OpenAPI 3.1 representation, so JSON Pointers into schemas may differ in case of `nullable` conversion.
+//
+// Paths that only differ by names of path parameters (e.g. "/items/{id}" and "/items/{itemId}") are compared
+// as the same path with renamed parameters, which is not a breaking change.
+func Compare(base, head openapi.SpecSchema) (Report, error) {
+	b, err := convert.AsOpenAPI31(base)
+	if err != nil {
+		return Report{}, err
+	}
+
+	h, err := convert.AsOpenAPI31(head)
+	if err != nil {
+		return Report{}, err
+	}
+
+	c := comparator{base: b, head: h, visited: map[refPair]bool{}, index: map[Change]int{}}
+
+	c.comparePaths()
+	c.compareComponentSchemas()
+
+	return Report{Changes: c.changes}, nil
+}
+
+type comparator struct {
+	base, head *openapi31.Spec
+	changes    []Change
+	visited    map[refPair]bool
+	index      map[Change]int // Pointer and message of a change to its position in changes.
+}
+
+// add reports a change, same change at the same pointer is reported once.
+//
+// Component schemas can be compared in both request and response directions,
+// such change is breaking if it breaks either direction.
+func (c *comparator) add(ptr string, breaking bool, msg string) {
+	k := Change{Pointer: ptr, Message: msg}
+
+	if i, ok := c.index[k]; ok {
+		c.changes[i].Breaking = c.changes[i].Breaking || breaking
+
+		return
+	}
+
+	c.index[k] = len(c.changes)
+	c.changes = append(c.changes, Change{Pointer: ptr, Message: msg, Breaking: breaking})
+}
+
+var methods = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+	http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace,
+}
+
+func pathItems(s *openapi31.Spec) map[string]openapi31.PathItem {
+	if s.Paths == nil {
+		return nil
+	}
+
+	return s.Paths.MapOfPathItemValues
+}
+
+var regexPathParam = regexp.MustCompile(`{([^}]+)}`)
+
+// pathParamNames returns names of parameters in a path template.
+func pathParamNames(path string) []string {
+	var names []string
+
+	for _, m := range regexPathParam.FindAllStringSubmatch(path, -1) {
+		names = append(names, m[1])
+	}
+
+	return names
+}
+
+// matchPaths maps base paths to head paths, paths are matched by template with parameter names
+// ignored if there is no exact match, e.g. "/items/{id}" matches "/items/{itemId}".
+func matchPaths(basePaths, headPaths map[string]openapi31.PathItem) map[string]string {
+	res := make(map[string]string, len(basePaths))
+	headByTemplate := map[string]string{}
+
+	for _, path := range internal.SortedMapKeys(headPaths) {
+		if _, ok := basePaths[path]; ok {
+			res[path] = path
+
+			continue
+		}
+
+		tpl := regexPathParam.ReplaceAllString(path, "{}")
+		if _, ok := headByTemplate[tpl]; !ok {
+			headByTemplate[tpl] = path
+		}
+	}
+
+	for _, path := range internal.SortedMapKeys(basePaths) {
+		if _, ok := res[path]; ok {
+			continue
+		}
+
+		tpl := regexPathParam.ReplaceAllString(path, "{}")
+		if hp, ok := headByTemplate[tpl]; ok {
+			res[path] = hp
+
+			delete(headByTemplate, tpl)
+		}
+	}
+
+	return res
+}
+
+func (c *comparator) comparePaths() {
+	basePaths := pathItems(c.base)
+	headPaths := pathItems(c.head)
+	matched := matchPaths(basePaths, headPaths)
+	matchedHead := make(map[string]string, len(matched))
+
+	for _, path := range internal.SortedMapKeys(basePaths) {
+		bpi := basePaths[path]
+		headPath, pathFound := matched[path]
+		hpi := headPaths[headPath]
+		renames := map[string]string{}
+
+		if pathFound {
+			matchedHead[headPath] = path
+
+			if headPath != path {
+				hNames := pathParamNames(headPath)
+
+				for i, name := range pathParamNames(path) {
+					if name == hNames[i] {
+						continue
+					}
+
+					renames[name] = hNames[i]
+					c.add("/paths/"+internal.EscapeJSONPointer(path), false,
+						"path parameter renamed: "+name+" to "+hNames[i]+" in "+path)
+				}
+			}
+		}
+
+		for _, method := range methods {
+			bop, _ := bpi.Operation(method) //nolint:errcheck // Method is valid.
+			if bop == nil {
+				continue
+			}
+
+			ptr := "/paths/" + internal.EscapeJSONPointer(path) + "/" + strings.ToLower(method)
+
+			var hop *openapi31.Operation
+			if pathFound {
+				hop, _ = hpi.Operation(method) //nolint:errcheck // Method is valid.
+			}
+
+			if hop == nil {
+				c.add(ptr, true, "operation removed: "+method+" "+path)
+
+				continue
+			}
+
+			c.compareOperation(ptr, renames, bpi, hpi, bop, hop)
+		}
+	}
+
+	for _, path := range internal.SortedMapKeys(headPaths) {
+		hpi := headPaths[path]
+		bpi := basePaths[matchedHead[path]]
+
+		for _, method := range methods {
+			hop, _ := hpi.Operation(method) //nolint:errcheck // Method is valid.
+			bop, _ := bpi.Operation(method) //nolint:errcheck // Method is valid.
+
+			if hop != nil && bop == nil {
+				c.add("/paths/"+internal.EscapeJSONPointer(path)+"/"+strings.ToLower(method), false,
+					"operation added: "+method+" "+path)
+			}
+		}
+	}
+}
+
+func (c *comparator) compareComponentSchemas() {
+	var bs, hs map[string]map[string]interface{}
+
+	if c.base.Components != nil {
+		bs = c.base.Components.Schemas
+	}
+
+	if c.head.Components != nil {
+		hs = c.head.Components.Schemas
+	}
+
+	for _, name := range internal.SortedMapKeys(bs) {
+		if _, ok := hs[name]; !ok {
+			c.add("/components/schemas/"+internal.EscapeJSONPointer(name), false, "component schema removed: "+name)
+		}
+	}
+
+	for _, name := range internal.SortedMapKeys(hs) {
+		if _, ok := bs[name]; !ok {
+			c.add("/components/schemas/"+internal.EscapeJSONPointer(name), false, "component schema added: "+name)
+		}
+	}
+}
+
+type paramKey struct {
+	in   openapi31.ParameterIn
+	name string
+}
+
+// parameters collects parameters of path item and operation, renames maps names of path parameters to
+// names in the other revision.
+func (c *comparator) parameters(s *openapi31.Spec, renames map[string]string, pi openapi31.PathItem, op *openapi31.Operation) (
+	map[paramKey]*openapi31.Parameter, map[paramKey]string,
+) {
+	res := map[paramKey]*openapi31.Parameter{}
+	ptrs := map[paramKey]string{}
+
+	collect := func(prefix string, params []openapi31.ParameterOrReference) {
+		for i, p := range params {
+			pp := p.Parameter
+
+			if p.Reference != nil {
+				pp = resolveParameter(s, p.Reference.Ref)
+			}
+
+			if pp == nil {
+				continue
+			}
+
+			k := paramKey{in: pp.In, name: pp.Name}
+			if n, ok := renames[pp.Name]; ok && pp.In == openapi31.ParameterInPath {
+				k.name = n
+			}
+
+			res[k] = pp
+			ptrs[k] = prefix + "/parameters/" + strconv.Itoa(i)
+		}
+	}
+
+	collect("..", pi.Parameters)
+	collect("", op.Parameters)
+
+	return res, ptrs
+}
+
+func sortedParamKeys(m map[paramKey]*openapi31.Parameter) []paramKey {
+	keys := make([]paramKey, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].in != keys[j].in {
+			return keys[i].in < keys[j].in
+		}
+
+		return keys[i].name < keys[j].name
+	})
+
+	return keys
+}
+
+func paramPtr(opPtr, rel string) string {
+	if strings.HasPrefix(rel, "..") {
+		return opPtr[:strings.LastIndex(opPtr, "/")] + rel[2:]
+	}
+
+	return opPtr + rel
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+func (c *comparator) compareOperation(ptr string, renames map[string]string, bpi, hpi openapi31.PathItem, bop, hop *openapi31.Operation) {
+	bParams, bPtrs := c.parameters(c.base, renames, bpi, bop)
+	hParams, hPtrs := c.parameters(c.head, nil, hpi, hop)
+
+	for _, k := range sortedParamKeys(bParams) {
+		bp := bParams[k]
+		pptr := paramPtr(ptr, bPtrs[k])
+
+		hp, ok := hParams[k]
+		if !ok {
+			c.add(pptr, false, "request parameter removed: "+string(k.in)+"."+k.name)
+
+			continue
+		}
+
+		if !isTrue(bp.Required) && isTrue(hp.Required) {
+			c.add(pptr+"/required", true, "request parameter became required: "+string(k.in)+"."+k.name)
+		}
+
+		if isTrue(bp.Required) && !isTrue(hp.Required) {
+			c.add(pptr+"/required", false, "request parameter became optional: "+string(k.in)+"."+k.name)
+		}
+
+		if bp.Schema != nil && hp.Schema != nil {
+			c.compareSchema(pptr+"/schema", bp.Schema, hp.Schema, true)
+		}
+
+		c.compareContent(pptr+"/content", bp.Content, hp.Content, true)
+	}
+
+	for _, k := range sortedParamKeys(hParams) {
+		if _, ok := bParams[k]; ok {
+			continue
+		}
+
+		hp := hParams[k]
+		pptr := paramPtr(ptr, hPtrs[k])
+
+		if isTrue(hp.Required) {
+			c.add(pptr, true, "required request parameter added: "+string(k.in)+"."+k.name)
+		} else {
+			c.add(pptr, false, "optional request parameter added: "+string(k.in)+"."+k.name)
+		}
+	}
+
+	c.compareRequestBody(ptr+"/requestBody", bop.RequestBody, hop.RequestBody)
+	c.compareResponses(ptr+"/responses", bop.Responses, hop.Responses)
+}
+
+func (c *comparator) compareRequestBody(ptr string, b, h *openapi31.RequestBodyOrReference) {
+	var brb, hrb *openapi31.RequestBody
+
+	if b != nil {
+		brb = resolveRequestBody(c.base, *b)
+	}
+
+	if h != nil {
+		hrb = resolveRequestBody(c.head, *h)
+	}
+
+	switch {
+	case brb == nil && hrb == nil:
+		return
+	case brb == nil:
+		c.add(ptr, isTrue(hrb.Required), "request body added")
+
+		return
+	case hrb == nil:
+		c.add(ptr, false, "request body removed")
+
+		return
+	}
+
+	if !isTrue(brb.Required) && isTrue(hrb.Required) {
+		c.add(ptr+"/required", true, "request body became required")
+	}
+
+	c.compareContent(ptr+"/content", brb.Content, hrb.Content, true)
+}
+
+func (c *comparator) compareContent(ptr string, b, h map[string]openapi31.MediaType, isRequest bool) {
+	for _, ct := range internal.SortedMapKeys(b) {
+		mptr := ptr + "/" + internal.EscapeJSONPointer(ct)
+
+		hmt, ok := h[ct]
+		if !ok {
+			c.add(mptr, true, "media type removed: "+ct)
+
+			continue
+		}
+
+		if bs := b[ct].Schema; bs != nil && hmt.Schema != nil {
+			c.compareSchema(mptr+"/schema", bs, hmt.Schema, isRequest)
+		}
+	}
+
+	for _, ct := range internal.SortedMapKeys(h) {
+		if _, ok := b[ct]; !ok {
+			c.add(ptr+"/"+internal.EscapeJSONPointer(ct), false, "media type added: "+ct)
+		}
+	}
+}
+
+func (c *comparator) compareResponses(ptr string, b, h *openapi31.Responses) {
+	bm := map[string]openapi31.ResponseOrReference{}
+	hm := map[string]openapi31.ResponseOrReference{}
+
+	if b != nil {
+		for k, v := range b.MapOfResponseOrReferenceValues {
+			bm[k] = v
+		}
+
+		if b.Default != nil {
+			bm["default"] = *b.Default
+		}
+	}
+
+	if h != nil {
+		for k, v := range h.MapOfResponseOrReferenceValues {
+			hm[k] = v
+		}
+
+		if h.Default != nil {
+			hm["default"] = *h.Default
+		}
+	}
+
+	for _, status := range internal.SortedMapKeys(bm) {
+		rptr := ptr + "/" + status
+
+		hr, ok := hm[status]
+		if !ok {
+			c.add(rptr, true, "response removed: "+status)
+
+			continue
+		}
+
+		br := resolveResponse(c.base, bm[status])
+		hrr := resolveResponse(c.head, hr)
+
+		if br == nil || hrr == nil {
+			continue
+		}
+
+		c.compareResponseHeaders(rptr+"/headers", br, hrr)
+		c.compareContent(rptr+"/content", br.Content, hrr.Content, false)
+	}
+
+	for _, status := range internal.SortedMapKeys(hm) {
+		if _, ok := bm[status]; !ok {
+			c.add(ptr+"/"+status, false, "response added: "+status)
+		}
+	}
+}
+
+func (c *comparator) compareResponseHeaders(ptr string, b, h *openapi31.Response) {
+	names := make([]string, 0, len(b.Headers))
+	for name := range b.Headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		hptr := ptr + "/" + internal.EscapeJSONPointer(name)
+
+		hh, ok := h.Headers[name]
+		if !ok {
+			c.add(hptr, true, "response header removed: "+name)
+
+			continue
+		}
+
+		bh := b.Headers[name]
+		if bh.Header != nil && hh.Header != nil {
+			if isTrue(bh.Header.Required) && !isTrue(hh.Header.Required) {
+				c.add(hptr+"/required", true, "response header became optional: "+name)
+			}
+
+			if bh.Header.Schema != nil && hh.Header.Schema != nil {
+				c.compareSchema(hptr+"/schema", bh.Header.Schema, hh.Header.Schema, false)
+			}
+		}
+	}
+}
+
+func resolveParameter(s *openapi31.Spec, ref string) *openapi31.Parameter {
+	name, ok := componentName(ref, "parameters")
+	if !ok || s.Components == nil {
+		return nil
+	}
+
+	return s.Components.Parameters[name].Parameter
+}
+
+func resolveRequestBody(s *openapi31.Spec, rb openapi31.RequestBodyOrReference) *openapi31.RequestBody {
+	if rb.Reference == nil {
+		return rb.RequestBody
+	}
+
+	name, ok := componentName(rb.Reference.Ref, "requestBodies")
+	if !ok || s.Components == nil {
+		return nil
+	}
+
+	return s.Components.RequestBodies[name].RequestBody
+}
+
+func resolveResponse(s *openapi31.Spec, r openapi31.ResponseOrReference) *openapi31.Response {
+	if r.Reference == nil {
+		return r.Response
+	}
+
+	name, ok := componentName(r.Reference.Ref, "responses")
+	if !ok || s.Components == nil {
+		return nil
+	}
+
+	return s.Components.Responses[name].Response
+}
+
+func componentName(ref, section string) (string, bool) {
+	prefix := "#/components/" + section + "/"
+
+	if !strings.HasPrefix(ref, prefix) {
+		return "", false
+	}
+
+	return internal.UnescapeJSONPointer(strings.TrimPrefix(ref, prefix)), true
+}
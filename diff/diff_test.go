@@ -0,0 +1,199 @@
+package diff_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go/diff"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestCompare(t *testing.T) {
+	base := openapi31.Spec{}
+	require.NoError(t, base.UnmarshalYAML([]byte(`
+openapi: 3.1.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, maximum: 100}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "201": {description: Created}
+  /pets/{id}:
+    delete:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "204": {description: Deleted}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        kind: {type: string, enum: [cat, dog, fish]}
+        tag: {type: string}
+`)))
+
+	head := openapi31.Spec{}
+	require.NoError(t, head.UnmarshalYAML([]byte(`
+openapi: 3.1.0
+info: {title: Pets, version: 2.0.0}
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: limit, in: query, required: true, schema: {type: integer, maximum: 50}}
+        - {name: offset, in: query, schema: {type: integer}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "201": {description: Created}
+  /pets/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "200": {description: OK}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        kind: {type: string, enum: [cat, dog]}
+`)))
+
+	r, err := diff.Compare(&base, &head)
+	require.NoError(t, err)
+
+	var changes []string
+	for _, c := range r.Changes {
+		changes = append(changes, c.String())
+	}
+
+	assert.Equal(t, []string{
+		"[breaking] /paths/~1pets/get/parameters/0/required: request parameter became required: query.limit",
+		"[breaking] /paths/~1pets/get/parameters/0/schema/maximum: maximum changed: 100 -> 50",
+		"/paths/~1pets/get/parameters/1: optional request parameter added: query.offset",
+		"[breaking] /components/schemas/Pet/properties/kind/enum: enum value removed: \"fish\"",
+		"[breaking] /components/schemas/Pet/properties/tag: property removed: tag",
+		"[breaking] /paths/~1pets~1{id}/delete: operation removed: DELETE /pets/{id}",
+		"/paths/~1pets~1{id}/get: operation added: GET /pets/{id}",
+	}, changes)
+	assert.True(t, r.HasBreaking())
+	assert.Len(t, r.Breaking(), 5)
+}
+
+func TestCompare_openapi3(t *testing.T) {
+	type req struct {
+		Name string `query:"name"`
+	}
+
+	type resp struct {
+		ID *int `json:"id"`
+	}
+
+	base := openapi3.NewReflector()
+	head := openapi3.NewReflector()
+
+	for i, r := range []*openapi3.Reflector{base, head} {
+		oc, err := r.NewOperationContext(http.MethodGet, "/things")
+		require.NoError(t, err)
+
+		if i == 0 {
+			oc.AddReqStructure(new(req))
+			oc.AddRespStructure(new(struct {
+				ID int `json:"id"`
+			}))
+		} else {
+			oc.AddReqStructure(new(struct {
+				Name string `query:"name" required:"true"`
+			}))
+			oc.AddRespStructure(new(resp))
+		}
+
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	rep, err := diff.Compare(base.Spec, head.Spec)
+	require.NoError(t, err)
+
+	var changes []string
+	for _, c := range rep.Changes {
+		changes = append(changes, c.String())
+	}
+
+	assert.Equal(t, []string{
+		"[breaking] /paths/~1things/get/parameters/0/required: request parameter became required: query.name",
+		"[breaking] /paths/~1things/get/responses/200/content/application~1json/schema/properties/id/type: schema became nullable",
+		"/components/schemas/DiffTestResp: component schema added: DiffTestResp",
+	}, changes)
+}
+
+func TestCompare_renamedPathParameter(t *testing.T) {
+	base := openapi31.Spec{}
+	require.NoError(t, base.UnmarshalYAML([]byte(`
+openapi: 3.1.0
+info: {title: Items, version: 1.0.0}
+paths:
+  /items/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "200": {description: OK}
+`)))
+
+	head := openapi31.Spec{}
+	require.NoError(t, head.UnmarshalYAML([]byte(`
+openapi: 3.1.0
+info: {title: Items, version: 1.1.0}
+paths:
+  /items/{itemId}:
+    get:
+      parameters:
+        - {name: itemId, in: path, required: true, schema: {type: string}}
+      responses:
+        "200": {description: OK}
+`)))
+
+	r, err := diff.Compare(&base, &head)
+	require.NoError(t, err)
+
+	var changes []string
+	for _, c := range r.Changes {
+		changes = append(changes, c.String())
+	}
+
+	assert.Equal(t, []string{
+		"/paths/~1items~1{id}: path parameter renamed: id to itemId in /items/{id}",
+	}, changes)
+	assert.False(t, r.HasBreaking())
+}
@@ -0,0 +1,391 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// refPair identifies comparison of referenced component schemas in a particular direction.
+type refPair struct {
+	base, head string
+	isRequest  bool
+}
+
+// narrowed reports a change that reduces the set of valid values,
+// it breaks clients that send data, but not clients that receive it.
+func (c *comparator) narrowed(ptr string, isRequest bool, msg string) {
+	c.add(ptr, isRequest, msg)
+}
+
+// widened reports a change that extends the set of valid values,
+// it breaks clients that receive data, but not clients that send it.
+func (c *comparator) widened(ptr string, isRequest bool, msg string) {
+	c.add(ptr, !isRequest, msg)
+}
+
+// resolveSchema follows local component references.
+func resolveSchema(s *openapi31.Spec, v interface{}) (interface{}, string) {
+	ref := ""
+
+	for i := 0; i < 32; i++ {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v, ref
+		}
+
+		r, ok := m["$ref"].(string)
+		if !ok {
+			return v, ref
+		}
+
+		name, ok := componentName(r, "schemas")
+		if !ok || s.Components == nil {
+			return v, ref
+		}
+
+		cs, ok := s.Components.Schemas[name]
+		if !ok {
+			return v, ref
+		}
+
+		ref = name
+		v = cs
+	}
+
+	return v, ref
+}
+
+func (c *comparator) compareSchema(ptr string, b, h interface{}, isRequest bool) {
+	b, bRef := resolveSchema(c.base, b)
+	h, hRef := resolveSchema(c.head, h)
+
+	if bRef != "" {
+		ptr = "/components/schemas/" + internal.EscapeJSONPointer(bRef)
+	}
+
+	if bRef != "" && hRef != "" {
+		k := refPair{base: bRef, head: hRef, isRequest: isRequest}
+		if c.visited[k] {
+			return
+		}
+
+		c.visited[k] = true
+	}
+
+	bs, bOk := b.(map[string]interface{})
+	hs, hOk := h.(map[string]interface{})
+
+	if !bOk || !hOk {
+		if !bOk && !hOk && b == h {
+			return
+		}
+
+		if b == true || (!bOk && hOk) {
+			c.narrowed(ptr, isRequest, "schema restricted")
+		} else {
+			c.widened(ptr, isRequest, "schema relaxed")
+		}
+
+		return
+	}
+
+	c.compareTypes(ptr, bs, hs, isRequest)
+	c.compareEnum(ptr, bs, hs, isRequest)
+	c.compareLimits(ptr, bs, hs, isRequest)
+	c.compareRequired(ptr, bs, hs, isRequest)
+	c.compareProperties(ptr, bs, hs, isRequest)
+	c.compareVariants(ptr, bs, hs, isRequest)
+
+	for _, k := range []string{"items", "additionalProperties"} {
+		bv, bFound := bs[k]
+		hv, hFound := hs[k]
+
+		if bFound && hFound {
+			c.compareSchema(ptr+"/"+k, bv, hv, isRequest)
+		}
+	}
+}
+
+func typeSet(s map[string]interface{}) map[string]bool {
+	res := map[string]bool{}
+
+	switch t := s["type"].(type) {
+	case string:
+		res[t] = true
+	case []interface{}:
+		for _, v := range t {
+			if ts, ok := v.(string); ok {
+				res[ts] = true
+			}
+		}
+	}
+
+	// Integer is a subset of number.
+	if res["number"] {
+		res["integer"] = true
+	}
+
+	return res
+}
+
+func sortedSet(m map[string]bool) []string {
+	res := make([]string, 0, len(m))
+
+	for k := range m {
+		res = append(res, k)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func (c *comparator) compareTypes(ptr string, b, h map[string]interface{}, isRequest bool) {
+	bt := typeSet(b)
+	ht := typeSet(h)
+
+	if len(bt) > 0 && len(ht) > 0 {
+		for _, t := range sortedSet(bt) {
+			if ht[t] {
+				continue
+			}
+
+			if t == "null" {
+				c.narrowed(ptr+"/type", isRequest, "schema became non-nullable")
+			} else {
+				c.narrowed(ptr+"/type", isRequest, "type removed: "+t)
+			}
+		}
+
+		for _, t := range sortedSet(ht) {
+			if bt[t] {
+				continue
+			}
+
+			if t == "null" {
+				c.widened(ptr+"/type", isRequest, "schema became nullable")
+			} else {
+				c.widened(ptr+"/type", isRequest, "type added: "+t)
+			}
+		}
+	}
+
+	bf, _ := b["format"].(string) //nolint:errcheck // Empty by default.
+	hf, _ := h["format"].(string) //nolint:errcheck // Empty by default.
+
+	if bf != hf {
+		c.add(ptr+"/format", true, "format changed: "+strconv.Quote(bf)+" -> "+strconv.Quote(hf))
+	}
+}
+
+// enumValues returns JSON representations of allowed values, const is treated as a single value enum.
+func enumValues(s map[string]interface{}) ([]string, bool) {
+	var values []interface{}
+
+	if v, ok := s["const"]; ok {
+		values = []interface{}{v}
+	} else if e, ok := s["enum"].([]interface{}); ok {
+		values = e
+	} else {
+		return nil, false
+	}
+
+	res := make([]string, 0, len(values))
+
+	for _, v := range values {
+		j, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+
+		res = append(res, string(j))
+	}
+
+	return res, true
+}
+
+func (c *comparator) compareEnum(ptr string, b, h map[string]interface{}, isRequest bool) {
+	be, bFound := enumValues(b)
+	he, hFound := enumValues(h)
+
+	switch {
+	case !bFound && !hFound:
+		return
+	case !bFound:
+		c.narrowed(ptr+"/enum", isRequest, "enum added")
+
+		return
+	case !hFound:
+		c.widened(ptr+"/enum", isRequest, "enum removed")
+
+		return
+	}
+
+	hm := map[string]bool{}
+	for _, v := range he {
+		hm[v] = true
+	}
+
+	bm := map[string]bool{}
+	for _, v := range be {
+		bm[v] = true
+
+		if !hm[v] {
+			c.narrowed(ptr+"/enum", isRequest, "enum value removed: "+v)
+		}
+	}
+
+	for _, v := range he {
+		if !bm[v] {
+			c.widened(ptr+"/enum", isRequest, "enum value added: "+v)
+		}
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+var (
+	upperLimits = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"}
+	lowerLimits = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"}
+)
+
+func (c *comparator) compareLimits(ptr string, b, h map[string]interface{}, isRequest bool) {
+	for _, k := range append(upperLimits, lowerLimits...) {
+		upper := strings.HasPrefix(k, "max") || strings.HasPrefix(k, "exclusiveMax")
+		bv, bFound := toFloat(b[k])
+		hv, hFound := toFloat(h[k])
+		kp := ptr + "/" + k
+
+		switch {
+		case !bFound && !hFound:
+		case !bFound:
+			c.narrowed(kp, isRequest, k+" added: "+fmt.Sprint(hv))
+		case !hFound:
+			c.widened(kp, isRequest, k+" removed: "+fmt.Sprint(bv))
+		case bv == hv:
+		case (hv < bv) == upper:
+			c.narrowed(kp, isRequest, k+" changed: "+fmt.Sprint(bv)+" -> "+fmt.Sprint(hv))
+		default:
+			c.widened(kp, isRequest, k+" changed: "+fmt.Sprint(bv)+" -> "+fmt.Sprint(hv))
+		}
+	}
+
+	bp, _ := b["pattern"].(string) //nolint:errcheck // Empty by default.
+	hp, _ := h["pattern"].(string) //nolint:errcheck // Empty by default.
+
+	switch {
+	case bp == hp:
+	case bp == "":
+		c.narrowed(ptr+"/pattern", isRequest, "pattern added: "+hp)
+	case hp == "":
+		c.widened(ptr+"/pattern", isRequest, "pattern removed: "+bp)
+	default:
+		c.add(ptr+"/pattern", true, "pattern changed: "+bp+" -> "+hp)
+	}
+}
+
+func stringSet(v interface{}) map[string]bool {
+	res := map[string]bool{}
+
+	items, _ := v.([]interface{}) //nolint:errcheck // Nil is a valid result.
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			res[s] = true
+		}
+	}
+
+	return res
+}
+
+func (c *comparator) compareRequired(ptr string, b, h map[string]interface{}, isRequest bool) {
+	br := stringSet(b["required"])
+	hr := stringSet(h["required"])
+
+	for _, name := range sortedSet(hr) {
+		if !br[name] {
+			c.narrowed(ptr+"/required", isRequest, "property became required: "+name)
+		}
+	}
+
+	for _, name := range sortedSet(br) {
+		if !hr[name] {
+			c.widened(ptr+"/required", isRequest, "property became optional: "+name)
+		}
+	}
+}
+
+func (c *comparator) compareProperties(ptr string, b, h map[string]interface{}, isRequest bool) {
+	bp, _ := b["properties"].(map[string]interface{}) //nolint:errcheck // Nil is a valid result.
+	hp, _ := h["properties"].(map[string]interface{}) //nolint:errcheck // Nil is a valid result.
+
+	for _, name := range internal.SortedKeys(bp) {
+		pp := ptr + "/properties/" + internal.EscapeJSONPointer(name)
+
+		hv, ok := hp[name]
+		if !ok {
+			// Clients may rely on presence of a property in response.
+			c.add(pp, !isRequest, "property removed: "+name)
+
+			continue
+		}
+
+		c.compareSchema(pp, bp[name], hv, isRequest)
+	}
+
+	for _, name := range internal.SortedKeys(hp) {
+		if _, ok := bp[name]; !ok {
+			c.add(ptr+"/properties/"+internal.EscapeJSONPointer(name), false, "property added: "+name)
+		}
+	}
+}
+
+func (c *comparator) compareVariants(ptr string, b, h map[string]interface{}, isRequest bool) {
+	for _, k := range []string{"allOf", "anyOf", "oneOf"} {
+		bv, _ := b[k].([]interface{}) //nolint:errcheck // Nil is a valid result.
+		hv, _ := h[k].([]interface{}) //nolint:errcheck // Nil is a valid result.
+
+		for i := 0; i < len(bv) && i < len(hv); i++ {
+			c.compareSchema(ptr+"/"+k+"/"+strconv.Itoa(i), bv[i], hv[i], isRequest)
+		}
+
+		// Additional allOf member restricts the schema, additional anyOf/oneOf member relaxes it.
+		narrowing := k == "allOf"
+
+		for i := len(bv); i < len(hv); i++ {
+			if narrowing {
+				c.narrowed(ptr+"/"+k+"/"+strconv.Itoa(i), isRequest, k+" schema added")
+			} else {
+				c.widened(ptr+"/"+k+"/"+strconv.Itoa(i), isRequest, k+" schema added")
+			}
+		}
+
+		for i := len(hv); i < len(bv); i++ {
+			if narrowing {
+				c.widened(ptr+"/"+k+"/"+strconv.Itoa(i), isRequest, k+" schema removed")
+			} else {
+				c.narrowed(ptr+"/"+k+"/"+strconv.Itoa(i), isRequest, k+" schema removed")
+			}
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package openapi
+
+// Discriminator describes a polymorphic type with variants distinguished by a value of a property.
+type Discriminator struct {
+	// PropertyName is a name of property that holds variant name, e.g. "type".
+	PropertyName string
+
+	// Mapping maps property values to instances of variant types, e.g. "created" => EventCreated{}.
+	Mapping map[string]interface{}
+}
+
+// DiscriminatorExposer declares discriminator of a polymorphic type.
+//
+// Schema of such type receives `oneOf` with references to variant components and `discriminator`,
+// discriminator property of each variant is restricted to mapped value(s).
+type DiscriminatorExposer interface {
+	OpenAPIDiscriminator() Discriminator
+}
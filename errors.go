@@ -0,0 +1,12 @@
+package openapi
+
+import "errors"
+
+// ErrReferenceNotFound is returned for a reference that does not point to a value in the document.
+var ErrReferenceNotFound = errors.New("reference not found")
+
+// ErrNonLocalReference is returned for a reference to another document where only local references are supported.
+var ErrNonLocalReference = errors.New("non-local reference")
+
+// ErrCircularReference is returned for a reference that resolves to itself through a chain of references.
+var ErrCircularReference = errors.New("circular reference")
@@ -0,0 +1,249 @@
+// Package examplegen synthesizes example payloads from schemas of OpenAPI specs.
+package examplegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/convert"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// Options configures generation of examples.
+type Options struct {
+	// MaxDepth limits nesting of objects and arrays, 8 by default.
+	// Deeper optional properties are omitted and arrays only have required minimum of items.
+	MaxDepth int
+
+	// MaxRecursion limits how many times a reference is expanded within itself, 1 by default.
+	// Optional properties and array items beyond the limit are omitted, required ones are null.
+	MaxRecursion int
+
+	// RequiredOnly omits optional properties.
+	RequiredOnly bool
+
+	// Request omits read-only properties, otherwise write-only properties are omitted.
+	Request bool
+}
+
+// Generate synthesizes a deterministic example that is valid against JSON schema.
+//
+// Schema can be a result of ToJSONSchema of openapi3 or openapi31 packages, references are resolved
+// as JSON pointers within the schema, e.g. "#/components/schemas/Pet" or "#/definitions/Pet".
+//
+// Example honors const, examples, default and enum values, type, format, numeric and length bounds,
+// a subset of pattern syntax, required properties, allOf members and first suitable anyOf variant.
+// Value of oneOf is taken from first variant that it does not match others by type, const, enum and
+// required properties, ambiguity by other keywords is not detected.
+func Generate(s jsonschema.SchemaOrBool, options Options) (interface{}, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var root interface{}
+
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	return newGenerator(root, options).generate(root)
+}
+
+// Response synthesizes an example body of operation response.
+//
+// Status can be a status code, e.g. "404", that falls back to a range, e.g. "4XX", and then to "default".
+// Example is synthesized from schema of JSON media type, or first media type with schema if there is no JSON.
+func Response(s openapi.SpecSchema, method, path, status string, options Options) (interface{}, error) {
+	converted, err := convert.AsOpenAPI31(s)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := internal.ToDocument(converted)
+	if err != nil {
+		return nil, err
+	}
+
+	ptr := "#/paths/" + internal.EscapeJSONPointer(path) + "/" + strings.ToLower(method)
+
+	if _, err := internal.ResolvePointer(root, ptr); err != nil {
+		return nil, fmt.Errorf("operation %s %s: %w", method, path, err)
+	}
+
+	resp, err := response(root, ptr+"/responses", status)
+	if err != nil {
+		return nil, fmt.Errorf("operation %s %s: %w", method, path, err)
+	}
+
+	content, _ := resp["content"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	schema, ok := contentSchema(content)
+	if !ok {
+		return nil, fmt.Errorf("response %s of operation %s %s has no schema", status, method, path)
+	}
+
+	options.Request = false
+
+	return newGenerator(root, options).generate(schema)
+}
+
+// response finds response of status in responses of operation.
+func response(root interface{}, ptr, status string) (map[string]interface{}, error) {
+	keys := []string{status}
+
+	if len(status) == 3 {
+		keys = append(keys, status[:1]+"XX")
+	}
+
+	keys = append(keys, "default")
+
+	for _, key := range keys {
+		v, err := internal.ResolvePointer(root, ptr+"/"+key)
+		if err != nil {
+			continue
+		}
+
+		if m, ok := v.(map[string]interface{}); ok {
+			if ref, ok := m["$ref"].(string); ok {
+				if v, err = internal.ResolveRef(root, ref); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if m, ok := v.(map[string]interface{}); ok {
+			return m, nil
+		}
+	}
+
+	return nil, fmt.Errorf("response %s: %w", status, openapi.ErrReferenceNotFound)
+}
+
+// contentSchema returns schema of JSON media type, or of first media type with schema.
+func contentSchema(content map[string]interface{}) (interface{}, bool) {
+	var (
+		res   interface{}
+		found bool
+	)
+
+	for _, ct := range internal.SortedKeys(content) {
+		mt, _ := content[ct].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+		schema, ok := mt["schema"]
+		if !ok {
+			continue
+		}
+
+		if isJSON(ct) {
+			return schema, true
+		}
+
+		if !found {
+			res, found = schema, true
+		}
+	}
+
+	return res, found
+}
+
+func isJSON(ct string) bool {
+	ct = strings.ToLower(strings.TrimSpace(strings.Split(ct, ";")[0]))
+
+	return ct == "application/json" || strings.HasSuffix(ct, "+json")
+}
+
+// Fill sets synthesized examples to media types of request bodies, responses, parameters and headers
+// that have schema and no example or examples.
+//
+// Spec should be a pointer to a spec value, e.g. *openapi3.Spec or *openapi31.Spec. Read-only properties
+// are omitted in examples of requests and write-only properties are omitted in examples of responses.
+func Fill(s openapi.SpecSchema, options Options) error {
+	rv := reflect.ValueOf(s)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("spec must be a non-nil pointer, %T given", s)
+	}
+
+	converted, err := convert.AsOpenAPI31(s)
+	if err != nil {
+		return err
+	}
+
+	root, err := internal.ToDocument(converted)
+	if err != nil {
+		return err
+	}
+
+	doc, err := internal.ToDocument(s)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+
+	internal.WalkDocument(doc, internal.DocumentVisitor{
+		Object: func(ptr string, kind internal.ObjectKind, obj map[string]interface{}) {
+			if kind != internal.KindMediaType {
+				return
+			}
+
+			_, hasExample := obj["example"]
+			_, hasExamples := obj["examples"]
+
+			if _, ok := obj["schema"]; !ok || hasExample || hasExamples {
+				return
+			}
+
+			schema, err := internal.ResolvePointer(root, "#"+ptr+"/schema")
+			if err != nil {
+				errs = append(errs, err.Error())
+
+				return
+			}
+
+			o := options
+			o.Request = isRequest(ptr)
+
+			v, err := newGenerator(root, o).generate(schema)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", ptr, err))
+
+				return
+			}
+
+			obj["example"] = v
+		},
+	})
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+
+		return fmt.Errorf("generating examples: %s", strings.Join(errs, ", "))
+	}
+
+	rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+
+	if err := internal.FromDocument(doc, s); err != nil {
+		return fmt.Errorf("loading document with examples: %w", err)
+	}
+
+	return nil
+}
+
+// isRequest checks if media type pointer belongs to request body or parameter rather than response or header.
+func isRequest(ptr string) bool {
+	req := strings.LastIndex(ptr, "/requestBody/")
+
+	for _, marker := range []string{"/requestBodies/", "/parameters/"} {
+		if i := strings.LastIndex(ptr, marker); i > req {
+			req = i
+		}
+	}
+
+	return req > strings.LastIndex(ptr, "/responses/")
+}
@@ -0,0 +1,242 @@
+package examplegen_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	jsonschemago "github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go/examplegen"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+const petstore = `
+openapi: 3.1.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "201":
+          description: Created.
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+        4XX:
+          description: Error.
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Error'}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name, tags]
+      properties:
+        id: {type: integer, format: int64, readOnly: true, exclusiveMinimum: 0}
+        name: {type: string, minLength: 12}
+        code: {type: string, pattern: '^[A-Z]{2}-[0-9]+$'}
+        status: {$ref: '#/components/schemas/Status'}
+        born: {type: string, format: date}
+        weight: {type: number, minimum: 0.5, maximum: 0.75}
+        secret: {type: string, writeOnly: true}
+        tags:
+          type: array
+          minItems: 2
+          uniqueItems: true
+          items: {type: string, maxLength: 7}
+        labels:
+          type: object
+          additionalProperties: {type: integer, multipleOf: 5, minimum: 3}
+        owner:
+          oneOf:
+            - {type: 'null'}
+            - $ref: '#/components/schemas/Owner'
+        parent: {$ref: '#/components/schemas/Pet'}
+    Owner:
+      allOf:
+        - type: object
+          required: [email]
+          properties:
+            email: {type: string, format: email}
+        - properties:
+            name: {type: string, examples: [Jane]}
+            since: {type: string, format: date-time}
+    Status:
+      type: string
+      enum: [available, sold]
+    Error:
+      type: object
+      required: [code]
+      properties:
+        code: {type: integer, minimum: 400, maximum: 599, default: 418}
+`
+
+func loadPetstore(t *testing.T) *openapi31.Spec {
+	t.Helper()
+
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(petstore)))
+
+	return &s
+}
+
+// assertValid checks value against JSON schema with formats.
+func assertValid(t *testing.T, s jsonschemago.SchemaOrBool, v interface{}) {
+	t.Helper()
+
+	schema, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	c := jsonschema.NewCompiler()
+	c.AssertFormat = true
+	require.NoError(t, c.AddResource("schema.json", strings.NewReader(string(schema))))
+
+	compiled, err := c.Compile("schema.json")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	var doc interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.NoError(t, compiled.Validate(doc))
+}
+
+func TestGenerate(t *testing.T) {
+	s := loadPetstore(t)
+	js := openapi31.ToJSONSchema(map[string]interface{}{"$ref": "#/components/schemas/Pet"}, s)
+
+	v, err := examplegen.Generate(js, examplegen.Options{})
+	require.NoError(t, err)
+
+	assertjson.EqualMarshal(t, []byte(`{
+	  "born":"2024-01-02","code":"AA-0","id":1,"labels":{"key":5},"name":"stringxxxxxx",
+	  "owner":{"email":"user@example.com","name":"Jane","since":"2024-01-02T15:04:05Z"},
+	  "status":"available","tags":["string","string1"],"weight":0.5
+	}`), v)
+	assertValid(t, js, v)
+
+	// Recursive property is omitted, write-only property is omitted in response.
+	assert.NotContains(t, v, "parent")
+	assert.NotContains(t, v, "secret")
+
+	v, err = examplegen.Generate(js, examplegen.Options{MaxRecursion: 2, Request: true, RequiredOnly: true})
+	require.NoError(t, err)
+
+	assertjson.EqualMarshal(t, []byte(`{"name":"stringxxxxxx","tags":["string","string1"]}`), v)
+}
+
+func TestGenerate_schemas(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		schema   string
+		expected string
+	}{
+		{name: "const", schema: `{"type":"string","const":"fixed","enum":["a","fixed"]}`, expected: `"fixed"`},
+		{name: "default", schema: `{"type":"integer","default":7,"enum":[3,7]}`, expected: `7`},
+		{name: "nullable", schema: `{"type":["null","boolean"]}`, expected: `true`},
+		{name: "null", schema: `{"type":"null"}`, expected: `null`},
+		{name: "any", schema: `true`, expected: `null`},
+		{name: "exclusive", schema: `{"type":"number","exclusiveMaximum":-3}`, expected: `-4`},
+		{name: "multiple", schema: `{"type":"integer","minimum":7,"multipleOf":3}`, expected: `9`},
+		{name: "maxLength", schema: `{"type":"string","maxLength":3}`, expected: `"str"`},
+		{name: "pattern", schema: `{"type":"string","pattern":"^(ab|cd)[^a-z]{3}\\d*x?$"}`, expected: `"abAAA0"`},
+		{name: "pattern length", schema: `{"pattern":"^[a-f]+$","minLength":5}`, expected: `"aaaaa"`},
+		{name: "tuple", schema: `{"type":"array","prefixItems":[{"type":"integer"},{"type":"boolean"}],"minItems":2}`, expected: `[0,true]`},
+		{name: "min properties", schema: `{"type":"object","minProperties":2,"additionalProperties":{"type":"boolean"}}`, expected: `{"key1":true,"key2":true}`},
+		{name: "definitions", schema: `{"$ref":"#/definitions/A","definitions":{"A":{"type":"string","format":"uuid"}}}`, expected: `"123e4567-e89b-12d3-a456-426614174000"`},
+		{name: "one of", schema: `{"oneOf":[{"type":"integer"},{"type":"number"}]}`, expected: `0.5`},
+		{name: "one of enum", schema: `{"oneOf":[{"type":"string","enum":["a"]},{"type":"string"}]}`, expected: `"string"`},
+		{name: "depth", schema: `{"type":"array","items":{"type":"array","items":{"type":"integer"}}}`, expected: `[[0]]`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var js jsonschemago.SchemaOrBool
+			require.NoError(t, json.Unmarshal([]byte(tc.schema), &js))
+
+			v, err := examplegen.Generate(js, examplegen.Options{})
+			require.NoError(t, err)
+
+			assertjson.EqualMarshal(t, []byte(tc.expected), v)
+			assertValid(t, js, v)
+		})
+	}
+
+	var js jsonschemago.SchemaOrBool
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"array","items":{"type":"array","items":{"type":"integer"}}}`), &js))
+
+	v, err := examplegen.Generate(js, examplegen.Options{MaxDepth: 1})
+	require.NoError(t, err)
+	assertjson.EqualMarshal(t, []byte(`[[]]`), v)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"$ref":"#/definitions/Missing"}`), &js))
+
+	_, err = examplegen.Generate(js, examplegen.Options{})
+	assert.EqualError(t, err, "reference not found: #/definitions/Missing")
+}
+
+func TestResponse(t *testing.T) {
+	s := loadPetstore(t)
+
+	v, err := examplegen.Response(s, "POST", "/pets", "404", examplegen.Options{})
+	require.NoError(t, err)
+	assertjson.EqualMarshal(t, []byte(`{"code":418}`), v)
+
+	_, err = examplegen.Response(s, "GET", "/pets", "200", examplegen.Options{})
+	assert.EqualError(t, err, "operation GET /pets: reference not found: #/paths/~1pets/get")
+
+	_, err = examplegen.Response(s, "POST", "/pets", "500", examplegen.Options{})
+	assert.EqualError(t, err, "operation POST /pets: response 500: reference not found")
+}
+
+func TestFill(t *testing.T) {
+	s := openapi3.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.0.3
+info: {title: Items, version: 1.0.0}
+paths:
+  /items:
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Item'}
+      responses:
+        "200":
+          description: OK.
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Item'}
+            text/plain:
+              schema: {type: string}
+              example: Item.
+components:
+  schemas:
+    Item:
+      type: object
+      properties:
+        id: {type: integer, readOnly: true, minimum: 10, exclusiveMinimum: true}
+        name: {type: string, nullable: true}
+`)))
+
+	require.NoError(t, examplegen.Fill(&s, examplegen.Options{}))
+
+	op := s.Paths.MapOfPathItemValues["/items"].MapOfOperationValues["put"]
+	assertjson.EqualMarshal(t, []byte(`{"name":"string"}`), op.RequestBody.RequestBody.Content["application/json"].Example)
+
+	content := op.Responses.MapOfResponseOrRefValues["200"].Response.Content
+	assertjson.EqualMarshal(t, []byte(`{"id":11,"name":"string"}`), content["application/json"].Example)
+	assert.Equal(t, "Item.", *content["text/plain"].Example)
+
+	assert.EqualError(t, examplegen.Fill((*openapi3.Spec)(nil), examplegen.Options{}), "spec must be a non-nil pointer, *openapi3.Spec given")
+}
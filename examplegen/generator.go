@@ -0,0 +1,698 @@
+package examplegen
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+type generator struct {
+	root    interface{}
+	options Options
+
+	// expanding counts expansions of references in progress.
+	expanding map[string]int
+
+	// variant selects enum and examples values for distinct items of arrays.
+	variant int
+
+	// fraction prefers non-integer values of number schemas to tell them apart from integer schemas.
+	fraction bool
+}
+
+func newGenerator(root interface{}, options Options) *generator {
+	if options.MaxDepth <= 0 {
+		options.MaxDepth = 8
+	}
+
+	if options.MaxRecursion <= 0 {
+		options.MaxRecursion = 1
+	}
+
+	return &generator{
+		root:      root,
+		options:   options,
+		expanding: map[string]int{},
+	}
+}
+
+func (g *generator) generate(schema interface{}) (interface{}, error) {
+	v, _, err := g.value(schema, 0)
+
+	return v, err
+}
+
+// value synthesizes a value of schema, it returns false if value is not available within limits.
+func (g *generator) value(schema interface{}, depth int) (interface{}, bool, error) {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		// Boolean schema true accepts any value and false accepts none.
+		b, _ := schema.(bool) //nolint:errcheck // False is a valid result.
+
+		return nil, b, nil
+	}
+
+	if ref, ok := s["$ref"].(string); ok {
+		if g.expanding[ref] >= g.options.MaxRecursion {
+			return nil, false, nil
+		}
+
+		target, err := g.resolve(ref)
+		if err != nil {
+			return nil, false, err
+		}
+
+		g.expanding[ref]++
+		defer func() { g.expanding[ref]-- }()
+
+		return g.value(merge(without(s, "$ref"), target), depth)
+	}
+
+	s, err := g.flatten(s, map[string]bool{})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if v, ok := g.hint(s); ok {
+		return v, true, nil
+	}
+
+	if variants, ok := s["oneOf"].([]interface{}); ok && len(variants) > 0 {
+		return g.oneOf(without(s, "oneOf", "anyOf"), variants, depth)
+	}
+
+	if variants, ok := s["anyOf"].([]interface{}); ok && len(variants) > 0 {
+		return g.value(merge(without(s, "oneOf", "anyOf"), variants[g.order(variants)[0]]), depth)
+	}
+
+	switch schemaType(s) {
+	case "object":
+		return g.object(s, depth)
+	case "array":
+		return g.array(s, depth)
+	case "string":
+		return g.string(s), true, nil
+	case "integer":
+		return int64(g.number(s, true)), true, nil
+	case "number":
+		return g.number(s, false), true, nil
+	case "boolean":
+		return g.variant%2 == 0, true, nil
+	default:
+		return nil, true, nil
+	}
+}
+
+func (g *generator) resolve(ref string) (interface{}, error) {
+	target, err := internal.ResolvePointer(g.root, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	switch target.(type) {
+	case map[string]interface{}, bool:
+		return target, nil
+	default:
+		return nil, fmt.Errorf("%w: %s is not a schema", openapi.ErrReferenceNotFound, ref)
+	}
+}
+
+// flatten merges allOf members into schema.
+func (g *generator) flatten(s map[string]interface{}, seen map[string]bool) (map[string]interface{}, error) {
+	members, ok := s["allOf"].([]interface{})
+	if !ok {
+		return s, nil
+	}
+
+	res := without(s, "allOf")
+
+	for _, m := range members {
+		ms, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if ref, ok := ms["$ref"].(string); ok {
+			if seen[ref] {
+				continue
+			}
+
+			seen[ref] = true
+
+			target, err := g.resolve(ref)
+			if err != nil {
+				return nil, err
+			}
+
+			ts, ok := target.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			ms = merge(without(ms, "$ref"), ts)
+		}
+
+		ms, err := g.flatten(ms, seen)
+		if err != nil {
+			return nil, err
+		}
+
+		res = merge(res, ms)
+	}
+
+	return res, nil
+}
+
+// hint returns a value declared in schema, it prefers const, examples, example, default and enum values.
+func (g *generator) hint(s map[string]interface{}) (interface{}, bool) {
+	if v, ok := s["const"]; ok {
+		return v, true
+	}
+
+	if values, ok := s["examples"].([]interface{}); ok && len(values) > 0 {
+		return values[g.variant%len(values)], true
+	}
+
+	if v, ok := s["example"]; ok {
+		return v, true
+	}
+
+	if v, ok := s["default"]; ok && g.variant == 0 {
+		return v, true
+	}
+
+	if values, ok := s["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[g.variant%len(values)], true
+	}
+
+	return nil, false
+}
+
+// order returns indexes of variants, null schemas go last.
+func (g *generator) order(variants []interface{}) []int {
+	res := make([]int, 0, len(variants))
+	nulls := make([]int, 0)
+
+	for i, v := range variants {
+		s, ok := v.(map[string]interface{})
+		if !ok {
+			nulls = append(nulls, i)
+
+			continue
+		}
+
+		if ref, ok := s["$ref"].(string); ok {
+			if target, err := g.resolve(ref); err == nil {
+				if ts, ok := target.(map[string]interface{}); ok && schemaType(ts) == "null" {
+					nulls = append(nulls, i)
+
+					continue
+				}
+			}
+
+			res = append(res, i)
+
+			continue
+		}
+
+		if schemaType(s) == "null" {
+			nulls = append(nulls, i)
+
+			continue
+		}
+
+		res = append(res, i)
+	}
+
+	return append(res, nulls...)
+}
+
+// oneOf synthesizes a value of first variant that does not match other variants,
+// or a value of first suitable variant if all values are ambiguous.
+func (g *generator) oneOf(s map[string]interface{}, variants []interface{}, depth int) (interface{}, bool, error) {
+	var (
+		fallback   interface{}
+		fallbackOK bool
+		found      bool
+	)
+
+	order := g.order(variants)
+	fraction := g.fraction
+
+	defer func() { g.fraction = fraction }()
+
+	for _, g.fraction = range []bool{fraction, true} {
+		for _, i := range order {
+			v, ok, err := g.value(merge(s, variants[i]), depth)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if !found {
+				fallback, fallbackOK, found = v, ok, true
+			}
+
+			if ok && !g.matchesOther(v, variants, i) {
+				return v, true, nil
+			}
+		}
+	}
+
+	return fallback, fallbackOK, nil
+}
+
+// skip checks if property is omitted because it is read-only in request or write-only in response.
+func (g *generator) skip(schema interface{}) bool {
+	key := "writeOnly"
+	if g.options.Request {
+		key = "readOnly"
+	}
+
+	for i := 0; i < 32; i++ {
+		s, ok := schema.(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		if b, _ := s[key].(bool); b { //nolint:errcheck // False is a valid result.
+			return true
+		}
+
+		ref, ok := s["$ref"].(string)
+		if !ok {
+			return false
+		}
+
+		target, err := g.resolve(ref)
+		if err != nil {
+			return false
+		}
+
+		schema = target
+	}
+
+	return false
+}
+
+func (g *generator) object(s map[string]interface{}, depth int) (interface{}, bool, error) {
+	res := map[string]interface{}{}
+	props, _ := s["properties"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+	required := map[string]bool{}
+
+	if names, ok := s["required"].([]interface{}); ok {
+		for _, n := range names {
+			if name, ok := n.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	optional := !g.options.RequiredOnly && depth < g.options.MaxDepth
+
+	for _, name := range internal.SortedKeys(props) {
+		if (!required[name] && !optional) || g.skip(props[name]) {
+			continue
+		}
+
+		v, ok, err := g.value(props[name], depth+1)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if ok || required[name] {
+			res[name] = v
+		}
+	}
+
+	additional, hasAdditional := s["additionalProperties"]
+	if !hasAdditional {
+		additional = true
+	}
+
+	for _, name := range internal.SortedMapKeys(required) {
+		if _, ok := res[name]; ok || props[name] != nil {
+			continue
+		}
+
+		v, _, err := g.value(additional, depth+1)
+		if err != nil {
+			return nil, false, err
+		}
+
+		res[name] = v
+	}
+
+	minProperties := int(number(s, "minProperties"))
+
+	// Map of additional properties has an entry for illustration.
+	if _, ok := additional.(map[string]interface{}); ok && len(props) == 0 && len(res) == 0 && optional && minProperties < 1 {
+		minProperties = 1
+	}
+
+	for i := 1; len(res) < minProperties && additional != false; i++ {
+		key := "key" + strconv.Itoa(i)
+		if minProperties == 1 {
+			key = "key"
+		}
+
+		if _, ok := res[key]; ok {
+			continue
+		}
+
+		v, _, err := g.value(additional, depth+1)
+		if err != nil {
+			return nil, false, err
+		}
+
+		res[key] = v
+	}
+
+	return res, true, nil
+}
+
+func (g *generator) array(s map[string]interface{}, depth int) (interface{}, bool, error) {
+	prefix, _ := s["prefixItems"].([]interface{}) //nolint:errcheck // Nil is valid.
+	items := s["items"]
+
+	if tuple, ok := items.([]interface{}); ok {
+		prefix = tuple
+		items = s["additionalItems"]
+	}
+
+	minItems := int(number(s, "minItems"))
+	count := minItems
+
+	if count == 0 && depth < g.options.MaxDepth {
+		count = 1
+	}
+
+	if maxItems, ok := s["maxItems"]; ok && count > int(toFloat(maxItems)) {
+		count = int(toFloat(maxItems))
+	}
+
+	unique, _ := s["uniqueItems"].(bool) //nolint:errcheck // False is a valid result.
+	res := make([]interface{}, 0, count)
+	variant := g.variant
+
+	defer func() { g.variant = variant }()
+
+	for i := 0; i < count; i++ {
+		item := items
+		if i < len(prefix) {
+			item = prefix[i]
+		} else if item == nil {
+			item = true
+		}
+
+		if unique {
+			g.variant = i
+		}
+
+		v, ok, err := g.value(item, depth+1)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if !ok && i >= minItems {
+			break
+		}
+
+		if unique && i > 0 {
+			v = distinct(v, res, i)
+		}
+
+		res = append(res, v)
+	}
+
+	return res, true, nil
+}
+
+// distinct changes a scalar value that is already present in items.
+func distinct(v interface{}, items []interface{}, i int) interface{} {
+	for _, item := range items {
+		if item != v {
+			continue
+		}
+
+		switch t := v.(type) {
+		case string:
+			return t + strconv.Itoa(i)
+		case int64:
+			return t + int64(i)
+		case float64:
+			return t + float64(i)
+		}
+	}
+
+	return v
+}
+
+func (g *generator) number(s map[string]interface{}, integer bool) float64 {
+	var (
+		lo, hi         float64
+		hasLo, hasHi   bool
+		exclLo, exclHi bool
+	)
+
+	if v, ok := s["minimum"]; ok {
+		lo, hasLo = toFloat(v), true
+	}
+
+	if v, ok := s["maximum"]; ok {
+		hi, hasHi = toFloat(v), true
+	}
+
+	// Boolean exclusive bounds of OpenAPI 3.0 and numeric bounds of JSON Schema.
+	switch v := s["exclusiveMinimum"].(type) {
+	case bool:
+		exclLo = v && hasLo
+	case float64:
+		if !hasLo || v >= lo {
+			lo, hasLo, exclLo = v, true, true
+		}
+	}
+
+	switch v := s["exclusiveMaximum"].(type) {
+	case bool:
+		exclHi = v && hasHi
+	case float64:
+		if !hasHi || v <= hi {
+			hi, hasHi, exclHi = v, true, true
+		}
+	}
+
+	below := func(v float64) bool { return hasLo && (v < lo || exclLo && v == lo) }
+	above := func(v float64) bool { return hasHi && (v > hi || exclHi && v == hi) }
+
+	step := number(s, "multipleOf")
+	if step <= 0 {
+		step = 1
+	}
+
+	v := 0.0
+
+	switch {
+	case below(v):
+		v = lo
+		if exclLo {
+			v += step
+		}
+	case above(v):
+		v = hi
+		if exclHi {
+			v -= step
+		}
+	}
+
+	if integer {
+		v = math.Ceil(v)
+	}
+
+	if m := number(s, "multipleOf"); m > 0 {
+		v = math.Ceil(v/m) * m
+	}
+
+	if hasLo && hasHi && (above(v) || below(v)) {
+		v = (lo + hi) / 2
+		if integer {
+			v = math.Floor(v)
+		}
+	}
+
+	if g.fraction && !integer && number(s, "multipleOf") <= 0 {
+		switch {
+		case !above(v + 0.5):
+			v += 0.5
+		case !below(v - 0.5):
+			v -= 0.5
+		}
+	}
+
+	return v
+}
+
+// schemaType returns first non-null type of schema, or type inferred from keywords.
+func schemaType(s map[string]interface{}) string {
+	var types []string
+
+	switch t := s["type"].(type) {
+	case string:
+		types = append(types, t)
+	case []interface{}:
+		for _, tt := range t {
+			if ts, ok := tt.(string); ok {
+				types = append(types, ts)
+			}
+		}
+	}
+
+	for _, t := range types {
+		if t != "null" {
+			return t
+		}
+	}
+
+	if len(types) > 0 {
+		return "null"
+	}
+
+	for _, inferred := range []struct {
+		typ  string
+		keys []string
+	}{
+		{typ: "object", keys: []string{"properties", "required", "additionalProperties", "minProperties"}},
+		{typ: "array", keys: []string{"items", "prefixItems", "minItems", "maxItems"}},
+		{typ: "string", keys: []string{"pattern", "format", "minLength", "maxLength"}},
+		{typ: "number", keys: []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"}},
+	} {
+		for _, k := range inferred.keys {
+			if _, ok := s[k]; ok {
+				return inferred.typ
+			}
+		}
+	}
+
+	return ""
+}
+
+// merge returns a copy of schema a with keywords of schema b, bounds are narrowed and properties are combined.
+func merge(a map[string]interface{}, b interface{}) map[string]interface{} {
+	bs, ok := b.(map[string]interface{})
+	if !ok {
+		return a
+	}
+
+	res := without(a)
+
+	for k, v := range bs {
+		cur, ok := res[k]
+		if !ok {
+			res[k] = v
+
+			continue
+		}
+
+		switch k {
+		case "properties":
+			res[k] = mergeProperties(cur, v)
+		case "required", "allOf":
+			cs, _ := cur.([]interface{}) //nolint:errcheck // Nil is valid.
+			vs, _ := v.([]interface{})   //nolint:errcheck // Nil is valid.
+			res[k] = append(append([]interface{}{}, cs...), vs...)
+		case "minimum", "minLength", "minItems", "minProperties":
+			res[k] = math.Max(toFloat(cur), toFloat(v))
+		case "maximum", "maxLength", "maxItems", "maxProperties":
+			res[k] = math.Min(toFloat(cur), toFloat(v))
+		case "type":
+			res[k] = mergeType(cur, v)
+		}
+	}
+
+	return res
+}
+
+func mergeProperties(a, b interface{}) interface{} {
+	as, _ := a.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+	bs, _ := b.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+	res := make(map[string]interface{}, len(as)+len(bs))
+
+	for k, v := range as {
+		res[k] = v
+	}
+
+	for k, v := range bs {
+		if cur, ok := res[k]; ok {
+			v = map[string]interface{}{"allOf": []interface{}{cur, v}}
+		}
+
+		res[k] = v
+	}
+
+	return res
+}
+
+// mergeType returns types allowed by both schemas, or the second type if there are none.
+func mergeType(a, b interface{}) interface{} {
+	allowed := map[string]bool{}
+
+	for _, t := range toSlice(b) {
+		allowed[fmt.Sprint(t)] = true
+	}
+
+	var res []interface{}
+
+	for _, t := range toSlice(a) {
+		if allowed[fmt.Sprint(t)] || t == "integer" && allowed["number"] {
+			res = append(res, t)
+		}
+	}
+
+	if len(res) == 0 {
+		return b
+	}
+
+	return res
+}
+
+func toSlice(v interface{}) []interface{} {
+	if s, ok := v.([]interface{}); ok {
+		return s
+	}
+
+	return []interface{}{v}
+}
+
+// without returns a shallow copy of schema without keywords.
+func without(s map[string]interface{}, keys ...string) map[string]interface{} {
+	res := make(map[string]interface{}, len(s))
+
+	for k, v := range s {
+		res[k] = v
+	}
+
+	for _, k := range keys {
+		delete(res, k)
+	}
+
+	return res
+}
+
+func number(s map[string]interface{}, key string) float64 {
+	return toFloat(s[key])
+}
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	default:
+		return 0
+	}
+}
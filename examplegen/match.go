@@ -0,0 +1,166 @@
+package examplegen
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// matchesOther checks if value may be valid against any variant except i.
+func (g *generator) matchesOther(v interface{}, variants []interface{}, i int) bool {
+	for j, variant := range variants {
+		if j != i && g.matches(v, variant, 0) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches checks value against type, const, enum, required and properties keywords of schema.
+//
+// Other keywords are not checked, so a value may be reported as matching while it is not valid.
+func (g *generator) matches(v interface{}, schema interface{}, depth int) bool {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		b, _ := schema.(bool) //nolint:errcheck // False is a valid result.
+
+		return b
+	}
+
+	if depth > 32 {
+		return true
+	}
+
+	if ref, ok := s["$ref"].(string); ok {
+		if target, err := g.resolve(ref); err == nil && !g.matches(v, target, depth+1) {
+			return false
+		}
+	}
+
+	if c, ok := s["const"]; ok && !equal(v, c) {
+		return false
+	}
+
+	if values, ok := s["enum"].([]interface{}); ok && !contains(values, v) {
+		return false
+	}
+
+	if !matchesType(v, s["type"]) {
+		return false
+	}
+
+	if members, ok := s["allOf"].([]interface{}); ok {
+		for _, m := range members {
+			if !g.matches(v, m, depth+1) {
+				return false
+			}
+		}
+	}
+
+	for _, k := range []string{"oneOf", "anyOf"} {
+		if variants, ok := s[k].([]interface{}); ok && len(variants) > 0 && !g.matchesAny(v, variants, depth+1) {
+			return false
+		}
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return true
+	}
+
+	if names, ok := s["required"].([]interface{}); ok {
+		for _, n := range names {
+			if name, ok := n.(string); ok {
+				if _, ok := obj[name]; !ok {
+					return false
+				}
+			}
+		}
+	}
+
+	props, _ := s["properties"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+	for name, pv := range obj {
+		if ps, ok := props[name]; ok && !g.matches(pv, ps, depth+1) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (g *generator) matchesAny(v interface{}, variants []interface{}, depth int) bool {
+	for _, variant := range variants {
+		if g.matches(v, variant, depth) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesType checks value against type keyword, missing type matches any value.
+func matchesType(v interface{}, t interface{}) bool {
+	if t == nil {
+		return true
+	}
+
+	vt := jsonType(v)
+
+	for _, tt := range toSlice(t) {
+		if tt == vt || (tt == "number" && vt == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsonType(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case int64, int:
+		return "integer"
+	case float64:
+		if t == math.Trunc(t) {
+			return "integer"
+		}
+
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return ""
+	}
+}
+
+func contains(values []interface{}, v interface{}) bool {
+	for _, value := range values {
+		if equal(value, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equal compares JSON representations of values.
+func equal(a, b interface{}) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return string(ja) == string(jb)
+}
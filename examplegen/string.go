@@ -0,0 +1,203 @@
+package examplegen
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// formats maps string formats to example values.
+var formats = map[string]string{
+	"date-time":             "2024-01-02T15:04:05Z",
+	"date":                  "2024-01-02",
+	"time":                  "15:04:05Z",
+	"duration":              "P1D",
+	"email":                 "user@example.com",
+	"idn-email":             "user@example.com",
+	"hostname":              "example.com",
+	"idn-hostname":          "example.com",
+	"ipv4":                  "192.0.2.1",
+	"ipv6":                  "2001:db8::1",
+	"uri":                   "https://example.com/",
+	"iri":                   "https://example.com/",
+	"uri-reference":         "https://example.com/",
+	"iri-reference":         "https://example.com/",
+	"uri-template":          "https://example.com/{id}",
+	"url":                   "https://example.com/",
+	"uuid":                  "123e4567-e89b-12d3-a456-426614174000",
+	"json-pointer":          "/example",
+	"relative-json-pointer": "0",
+	"regex":                 "^example$",
+	"byte":                  "ZXhhbXBsZQ==",
+	"binary":                "example",
+	"password":              "password",
+}
+
+func (g *generator) string(s map[string]interface{}) string {
+	minLength, maxLength := -1, -1
+
+	if _, ok := s["minLength"]; ok {
+		minLength = int(number(s, "minLength"))
+	}
+
+	if _, ok := s["maxLength"]; ok {
+		maxLength = int(number(s, "maxLength"))
+	}
+
+	if pattern, ok := s["pattern"].(string); ok {
+		if v, ok := fromPattern(pattern, minLength, maxLength); ok {
+			return v
+		}
+	}
+
+	format, _ := s["format"].(string) //nolint:errcheck // Empty format is valid.
+
+	if v, ok := formats[format]; ok {
+		return v
+	}
+
+	v := "string"
+	if g.variant > 0 {
+		v += strconv.Itoa(g.variant)
+	}
+
+	if n := utf8.RuneCountInString(v); minLength > n {
+		v += strings.Repeat("x", minLength-n)
+	}
+
+	if maxLength >= 0 && utf8.RuneCountInString(v) > maxLength {
+		v = string([]rune(v)[:maxLength])
+	}
+
+	return v
+}
+
+// fromPattern makes a string that matches regular expression and length bounds.
+//
+// Regular expression is parsed with Go syntax, repetitions are expanded to the least number
+// of items that satisfies minimal length.
+func fromPattern(pattern string, minLength, maxLength int) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+
+	matcher, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false
+	}
+
+	limit := minLength
+	if limit < 1 {
+		limit = 1
+	}
+
+	for reps := 1; reps <= limit; reps++ {
+		var b strings.Builder
+
+		if !writePattern(&b, re, reps) {
+			return "", false
+		}
+
+		v := b.String()
+		n := utf8.RuneCountInString(v)
+
+		if maxLength >= 0 && n > maxLength {
+			return "", false
+		}
+
+		if n >= minLength && matcher.MatchString(v) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// writePattern writes an example of regular expression with reps items of unbounded repetitions.
+func writePattern(b *strings.Builder, re *syntax.Regexp, reps int) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		r, ok := classRune(re.Rune)
+		if !ok {
+			return false
+		}
+
+		b.WriteRune(r)
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte('a')
+	case syntax.OpCapture:
+		return writePattern(b, re.Sub[0], reps)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !writePattern(b, sub, reps) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		return writePattern(b, re.Sub[0], reps)
+	case syntax.OpStar, syntax.OpPlus:
+		return repeat(b, re.Sub[0], reps, reps)
+	case syntax.OpQuest:
+		return true
+	case syntax.OpRepeat:
+		n := re.Min
+		if reps > n && (re.Max < 0 || re.Max > n) {
+			n = reps
+		}
+
+		if re.Max >= 0 && n > re.Max {
+			n = re.Max
+		}
+
+		return repeat(b, re.Sub[0], n, reps)
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return true
+	default:
+		return false
+	}
+
+	return true
+}
+
+func repeat(b *strings.Builder, re *syntax.Regexp, n, reps int) bool {
+	for i := 0; i < n; i++ {
+		if !writePattern(b, re, reps) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// classRune returns a readable rune of character class, classes are pairs of rune ranges.
+func classRune(ranges []rune) (rune, bool) {
+	in := func(r rune) bool {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if r >= ranges[i] && r <= ranges[i+1] {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, bounds := range [][2]rune{{'a', 'z'}, {'A', 'Z'}, {'0', '9'}, {'!', '~'}} {
+		for r := bounds[0]; r <= bounds[1]; r++ {
+			if in(r) {
+				return r, true
+			}
+		}
+	}
+
+	if len(ranges) > 0 && ranges[0] > ' ' {
+		return ranges[0], true
+	}
+
+	return 0, false
+}
@@ -4,6 +4,7 @@ go 1.18
 
 require (
 	github.com/bool64/dev v0.2.43
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.0
 	github.com/stretchr/testify v1.8.2
 	github.com/swaggest/assertjson v1.9.0
 	github.com/swaggest/jsonschema-go v0.3.78
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/refl"
+)
+
+// DiscriminatorVariantReflector reflects variant of a polymorphic type into a component schema,
+// restricts discriminator property to values and returns reference to component.
+type DiscriminatorVariantReflector func(variant interface{}, propertyName string, values []string) (ref string, err error)
+
+// InterceptDiscriminator populates `oneOf` and `discriminator` for polymorphic types,
+// that implement openapi.DiscriminatorExposer or are registered in discriminators.
+func InterceptDiscriminator(
+	discriminators map[reflect.Type]openapi.Discriminator,
+	reflectVariant DiscriminatorVariantReflector,
+) jsonschema.InterceptSchemaFunc {
+	return func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+		if !params.Processed || !params.Value.IsValid() {
+			return false, nil
+		}
+
+		d, found := findDiscriminator(params.Value, discriminators)
+		if !found {
+			return false, nil
+		}
+
+		if d.PropertyName == "" {
+			return false, fmt.Errorf("missing discriminator property name for %s", params.Value.Type())
+		}
+
+		values := make([]string, 0, len(d.Mapping))
+		for v := range d.Mapping {
+			values = append(values, v)
+		}
+
+		sort.Strings(values)
+
+		// Grouping values by variant type to have a single reference for each variant.
+		var (
+			variants     []interface{}
+			variantTypes []reflect.Type
+			typeValues   = map[reflect.Type][]string{}
+		)
+
+		for _, v := range values {
+			variant := d.Mapping[v]
+			t := refl.DeepIndirect(reflect.TypeOf(variant))
+
+			if _, ok := typeValues[t]; !ok {
+				variants = append(variants, variant)
+				variantTypes = append(variantTypes, t)
+			}
+
+			typeValues[t] = append(typeValues[t], v)
+		}
+
+		mapping := make(map[string]string, len(values))
+		oneOf := make([]jsonschema.SchemaOrBool, 0, len(variants))
+
+		for i, variant := range variants {
+			ref, err := reflectVariant(variant, d.PropertyName, typeValues[variantTypes[i]])
+			if err != nil {
+				return false, fmt.Errorf("reflecting discriminator variant %T: %w", variant, err)
+			}
+
+			for _, v := range typeValues[variantTypes[i]] {
+				mapping[v] = ref
+			}
+
+			oneOf = append(oneOf, (&jsonschema.Schema{}).WithRef(ref).ToSchemaOrBool())
+		}
+
+		params.Schema.OneOf = oneOf
+		params.Schema.WithExtraPropertiesItem("discriminator", map[string]interface{}{
+			"propertyName": d.PropertyName,
+			"mapping":      mapping,
+		})
+
+		return false, nil
+	}
+}
+
+func findDiscriminator(v reflect.Value, discriminators map[reflect.Type]openapi.Discriminator) (openapi.Discriminator, bool) {
+	if d, ok := discriminators[refl.DeepIndirect(v.Type())]; ok {
+		return d, true
+	}
+
+	if !v.CanInterface() {
+		return openapi.Discriminator{}, false
+	}
+
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		v = reflect.New(v.Type().Elem())
+	}
+
+	if e, ok := v.Interface().(openapi.DiscriminatorExposer); ok {
+		return e.OpenAPIDiscriminator(), true
+	}
+
+	if v.Kind() != reflect.Ptr {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+
+		if e, ok := p.Interface().(openapi.DiscriminatorExposer); ok {
+			return e.OpenAPIDiscriminator(), true
+		}
+	}
+
+	return openapi.Discriminator{}, false
+}
@@ -0,0 +1,349 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ToDocument converts OpenAPI spec (or any of its parts) to a generic JSON value.
+func ToDocument(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// FromDocument loads generic JSON value into OpenAPI spec (or any of its parts).
+func FromDocument(doc interface{}, v interface{}) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// EscapeJSONPointer escapes reference token according to RFC 6901.
+func EscapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// UnescapeJSONPointer unescapes reference token according to RFC 6901.
+func UnescapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// SortedKeys returns sorted keys of a generic JSON object.
+func SortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// SortedMapKeys returns sorted keys of a map with string keys, e.g. map[string]PathItem.
+func SortedMapKeys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map {
+		return nil
+	}
+
+	keys := make([]string, 0, v.Len())
+
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// DocumentVisitor defines callbacks for WalkDocument.
+type DocumentVisitor struct {
+	// Schema is called for every top-level schema position (e.g. parameter schema or component schema),
+	// nested schemas are not visited. Returned value replaces the original schema.
+	Schema func(ptr string, schema interface{}) interface{}
+
+	// Reference is called for every non-schema reference object, can be nil.
+	Reference func(ptr string, ref map[string]interface{})
+
+	// Object is called for every non-reference object of a known kind, can be nil.
+	Object func(ptr string, kind ObjectKind, obj map[string]interface{})
+}
+
+// ObjectKind names a type of OpenAPI object.
+type ObjectKind string
+
+// ObjectKind values enumeration.
+const (
+	KindPathItem       = ObjectKind("pathItem")
+	KindOperation      = ObjectKind("operation")
+	KindParameter      = ObjectKind("parameter")
+	KindHeader         = ObjectKind("header")
+	KindRequestBody    = ObjectKind("requestBody")
+	KindResponse       = ObjectKind("response")
+	KindMediaType      = ObjectKind("mediaType")
+	KindExample        = ObjectKind("example")
+	KindLink           = ObjectKind("link")
+	KindCallback       = ObjectKind("callback")
+	KindSecurityScheme = ObjectKind("securityScheme")
+)
+
+// WalkDocument visits schemas and references in generic OpenAPI 3.x document.
+func WalkDocument(doc map[string]interface{}, v DocumentVisitor) {
+	w := docWalker{v: v}
+
+	if paths, ok := doc["paths"].(map[string]interface{}); ok {
+		for _, k := range SortedKeys(paths) {
+			w.pathItem("/paths/"+EscapeJSONPointer(k), paths[k])
+		}
+	}
+
+	if webhooks, ok := doc["webhooks"].(map[string]interface{}); ok {
+		for _, k := range SortedKeys(webhooks) {
+			w.pathItem("/webhooks/"+EscapeJSONPointer(k), webhooks[k])
+		}
+	}
+
+	if components, ok := doc["components"].(map[string]interface{}); ok {
+		w.components("/components", components)
+	}
+}
+
+type docWalker struct {
+	v DocumentVisitor
+}
+
+func (w docWalker) each(ptr string, v interface{}, f func(ptr string, v interface{})) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for _, k := range SortedKeys(m) {
+		f(ptr+"/"+EscapeJSONPointer(k), m[k])
+	}
+}
+
+func (w docWalker) object(ptr string, kind ObjectKind, v interface{}) {
+	if w.isRef(ptr, v) {
+		return
+	}
+
+	if w.v.Object != nil {
+		w.v.Object(ptr, kind, v.(map[string]interface{})) //nolint:errcheck // Checked in isRef.
+	}
+}
+
+func (w docWalker) objectFunc(kind ObjectKind) func(ptr string, v interface{}) {
+	return func(ptr string, v interface{}) {
+		w.object(ptr, kind, v)
+	}
+}
+
+func (w docWalker) isRef(ptr string, v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return true
+	}
+
+	if _, ok := m["$ref"]; ok {
+		if w.v.Reference != nil {
+			w.v.Reference(ptr, m)
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func (w docWalker) schema(ptr string, parent map[string]interface{}, key string) {
+	s, ok := parent[key]
+	if !ok || w.v.Schema == nil {
+		return
+	}
+
+	parent[key] = w.v.Schema(ptr+"/"+key, s)
+}
+
+func (w docWalker) components(ptr string, c map[string]interface{}) {
+	if schemas, ok := c["schemas"].(map[string]interface{}); ok && w.v.Schema != nil {
+		for _, k := range SortedKeys(schemas) {
+			schemas[k] = w.v.Schema(ptr+"/schemas/"+EscapeJSONPointer(k), schemas[k])
+		}
+	}
+
+	w.each(ptr+"/responses", c["responses"], w.response)
+	w.each(ptr+"/parameters", c["parameters"], w.parameter)
+	w.each(ptr+"/examples", c["examples"], w.objectFunc(KindExample))
+	w.each(ptr+"/requestBodies", c["requestBodies"], w.requestBody)
+	w.each(ptr+"/headers", c["headers"], w.header)
+	w.each(ptr+"/securitySchemes", c["securitySchemes"], w.objectFunc(KindSecurityScheme))
+	w.each(ptr+"/links", c["links"], w.objectFunc(KindLink))
+	w.each(ptr+"/callbacks", c["callbacks"], w.callback)
+	w.each(ptr+"/pathItems", c["pathItems"], w.pathItem)
+}
+
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+func (w docWalker) pathItem(ptr string, v interface{}) {
+	if w.isRef(ptr, v) {
+		return
+	}
+
+	pi := v.(map[string]interface{}) //nolint:errcheck // Checked in isRef.
+
+	if w.v.Object != nil {
+		w.v.Object(ptr, KindPathItem, pi)
+	}
+
+	w.parameters(ptr+"/parameters", pi["parameters"])
+
+	for _, method := range httpMethods {
+		if op, ok := pi[method].(map[string]interface{}); ok {
+			w.operation(ptr+"/"+method, op)
+		}
+	}
+}
+
+func (w docWalker) parameters(ptr string, v interface{}) {
+	params, ok := v.([]interface{})
+	if !ok {
+		return
+	}
+
+	for i, p := range params {
+		w.parameter(ptr+"/"+strconv.Itoa(i), p)
+	}
+}
+
+func (w docWalker) operation(ptr string, op map[string]interface{}) {
+	if w.v.Object != nil {
+		w.v.Object(ptr, KindOperation, op)
+	}
+
+	w.parameters(ptr+"/parameters", op["parameters"])
+
+	if rb, ok := op["requestBody"]; ok {
+		w.requestBody(ptr+"/requestBody", rb)
+	}
+
+	if responses, ok := op["responses"].(map[string]interface{}); ok {
+		for _, k := range SortedKeys(responses) {
+			if !strings.HasPrefix(k, "x-") {
+				w.response(ptr+"/responses/"+EscapeJSONPointer(k), responses[k])
+			}
+		}
+	}
+
+	w.each(ptr+"/callbacks", op["callbacks"], w.callback)
+}
+
+func (w docWalker) parameter(ptr string, v interface{}) {
+	w.parameterOrHeader(ptr, KindParameter, v)
+}
+
+func (w docWalker) header(ptr string, v interface{}) {
+	w.parameterOrHeader(ptr, KindHeader, v)
+}
+
+func (w docWalker) parameterOrHeader(ptr string, kind ObjectKind, v interface{}) {
+	if w.isRef(ptr, v) {
+		return
+	}
+
+	p := v.(map[string]interface{}) //nolint:errcheck // Checked in isRef.
+
+	if w.v.Object != nil {
+		w.v.Object(ptr, kind, p)
+	}
+
+	w.schema(ptr, p, "schema")
+	w.content(ptr+"/content", p["content"])
+	w.each(ptr+"/examples", p["examples"], w.objectFunc(KindExample))
+}
+
+func (w docWalker) content(ptr string, v interface{}) {
+	w.each(ptr, v, func(ptr string, v interface{}) {
+		mt, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		if w.v.Object != nil {
+			w.v.Object(ptr, KindMediaType, mt)
+		}
+
+		w.schema(ptr, mt, "schema")
+		w.each(ptr+"/examples", mt["examples"], w.objectFunc(KindExample))
+		w.each(ptr+"/encoding", mt["encoding"], func(ptr string, v interface{}) {
+			if enc, ok := v.(map[string]interface{}); ok {
+				w.each(ptr+"/headers", enc["headers"], w.header)
+			}
+		})
+	})
+}
+
+func (w docWalker) requestBody(ptr string, v interface{}) {
+	if w.isRef(ptr, v) {
+		return
+	}
+
+	rb := v.(map[string]interface{}) //nolint:errcheck // Checked in isRef.
+
+	if w.v.Object != nil {
+		w.v.Object(ptr, KindRequestBody, rb)
+	}
+
+	w.content(ptr+"/content", rb["content"])
+}
+
+func (w docWalker) response(ptr string, v interface{}) {
+	if w.isRef(ptr, v) {
+		return
+	}
+
+	resp := v.(map[string]interface{}) //nolint:errcheck // Checked in isRef.
+
+	if w.v.Object != nil {
+		w.v.Object(ptr, KindResponse, resp)
+	}
+
+	w.each(ptr+"/headers", resp["headers"], w.header)
+	w.content(ptr+"/content", resp["content"])
+	w.each(ptr+"/links", resp["links"], w.objectFunc(KindLink))
+}
+
+func (w docWalker) callback(ptr string, v interface{}) {
+	if w.isRef(ptr, v) {
+		return
+	}
+
+	if w.v.Object != nil {
+		w.v.Object(ptr, KindCallback, v.(map[string]interface{})) //nolint:errcheck // Checked in isRef.
+	}
+
+	w.each(ptr, v, func(ptr string, v interface{}) {
+		if !strings.HasPrefix(ptr[strings.LastIndex(ptr, "/")+1:], "x-") {
+			w.pathItem(ptr, v)
+		}
+	})
+}
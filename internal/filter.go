@@ -0,0 +1,334 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// OperationFilter selects operations of a generic document, operation is kept if it matches all non-empty criteria.
+type OperationFilter struct {
+	Tags      []string
+	Paths     []string
+	Operation func(method, path string, op map[string]interface{}) (bool, error)
+}
+
+// FilterDocument removes operations that do not match filter from paths and webhooks of a generic document
+// and prunes components and tags that are no longer used.
+//
+// Path globs are not applied to webhooks.
+func FilterDocument(doc map[string]interface{}, f OperationFilter) error {
+	globs := make([]*regexp.Regexp, 0, len(f.Paths))
+
+	for _, p := range f.Paths {
+		globs = append(globs, globRegexp(p))
+	}
+
+	usedTags := operationTags(doc)
+
+	for _, section := range []string{"paths", "webhooks"} {
+		items, ok := doc[section].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, path := range SortedKeys(items) {
+			if strings.HasPrefix(path, "x-") {
+				continue
+			}
+
+			var pathGlobs []*regexp.Regexp
+			if section == "paths" {
+				pathGlobs = globs
+			}
+
+			keep, err := filterPathItem(doc, path, items[path], f, pathGlobs)
+			if err != nil {
+				return err
+			}
+
+			if !keep {
+				delete(items, path)
+			}
+		}
+	}
+
+	pruneTags(doc, usedTags)
+	PruneComponents(doc)
+
+	return nil
+}
+
+// filterPathItem removes operations of a path item, it returns false if path item has no operations left.
+func filterPathItem(
+	doc map[string]interface{},
+	path string,
+	v interface{},
+	f OperationFilter,
+	globs []*regexp.Regexp,
+) (bool, error) {
+	pi, ok := v.(map[string]interface{})
+	if !ok {
+		return false, nil
+	}
+
+	if len(globs) > 0 && !matchesAny(globs, path) {
+		return false, nil
+	}
+
+	ops := pi
+
+	// Operations of referenced path item are checked without changing the component,
+	// reference is kept if any of them matches.
+	if ref, ok := pi["$ref"].(string); ok {
+		res, err := ResolveRef(doc, ref)
+		if err != nil {
+			return false, err
+		}
+
+		if ops, ok = res.(map[string]interface{}); !ok {
+			return false, nil
+		}
+	}
+
+	kept := 0
+
+	for _, method := range httpMethods {
+		op, ok := ops[method].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		keep, err := f.matches(method, path, op)
+		if err != nil {
+			return false, err
+		}
+
+		if keep {
+			kept++
+		} else if _, isRef := pi["$ref"]; !isRef {
+			delete(pi, method)
+		}
+	}
+
+	return kept > 0, nil
+}
+
+func (f OperationFilter) matches(method, path string, op map[string]interface{}) (bool, error) {
+	if len(f.Tags) > 0 {
+		found := false
+
+		tags, _ := op["tags"].([]interface{}) //nolint:errcheck // Nil is valid.
+		for _, t := range tags {
+			for _, tag := range f.Tags {
+				if t == tag {
+					found = true
+				}
+			}
+		}
+
+		if !found {
+			return false, nil
+		}
+	}
+
+	if f.Operation != nil {
+		return f.Operation(method, path, op)
+	}
+
+	return true, nil
+}
+
+// globRegexp makes a regular expression of path glob, "*" matches any characters except "/"
+// and "**" matches any characters.
+func globRegexp(glob string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(glob)
+	expr = strings.ReplaceAll(expr, `\*\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\*`, "[^/]*")
+
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+func matchesAny(globs []*regexp.Regexp, path string) bool {
+	for _, g := range globs {
+		if g.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// operationTags collects tags of operations of a document.
+func operationTags(doc map[string]interface{}) map[string]bool {
+	tags := map[string]bool{}
+
+	WalkDocument(doc, DocumentVisitor{
+		Object: func(_ string, kind ObjectKind, obj map[string]interface{}) {
+			if kind != KindOperation {
+				return
+			}
+
+			opTags, _ := obj["tags"].([]interface{}) //nolint:errcheck // Nil is valid.
+			for _, t := range opTags {
+				if s, ok := t.(string); ok {
+					tags[s] = true
+				}
+			}
+		},
+	})
+
+	return tags
+}
+
+// pruneTags removes tags that were used by operations before filtering and are not used anymore.
+func pruneTags(doc map[string]interface{}, usedBefore map[string]bool) {
+	tags, ok := doc["tags"].([]interface{})
+	if !ok {
+		return
+	}
+
+	used := operationTags(doc)
+	res := make([]interface{}, 0, len(tags))
+
+	for _, t := range tags {
+		tag, _ := t.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+		name, _ := tag["name"].(string)      //nolint:errcheck // Empty is valid.
+
+		if usedBefore[name] && !used[name] {
+			continue
+		}
+
+		res = append(res, t)
+	}
+
+	if len(res) == 0 {
+		delete(doc, "tags")
+	} else {
+		doc["tags"] = res
+	}
+}
+
+// PruneComponents removes components that are not referenced (directly or transitively) from paths,
+// webhooks or security requirements of a generic document.
+func PruneComponents(doc map[string]interface{}) {
+	components, ok := doc["components"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	roots := make(map[string]interface{}, len(doc))
+
+	for k, v := range doc {
+		if k != "components" {
+			roots[k] = v
+		}
+	}
+
+	used := map[string]map[string]bool{}
+
+	var queue []string
+
+	use := func(section, name string) {
+		if used[section] == nil {
+			used[section] = map[string]bool{}
+		}
+
+		if !used[section][name] {
+			used[section][name] = true
+			queue = append(queue, section+"/"+EscapeJSONPointer(name))
+		}
+	}
+
+	collect := func(v map[string]interface{}) {
+		RewriteRefs(v, func(ref string) string {
+			if section, name, ok := componentOfRef(ref); ok {
+				use(section, name)
+			}
+
+			return ref
+		})
+
+		for _, name := range securityNames(v) {
+			use("securitySchemes", name)
+		}
+	}
+
+	collect(roots)
+
+	for len(queue) > 0 {
+		ptr := queue[0]
+		queue = queue[1:]
+
+		section := ptr[:strings.Index(ptr, "/")]
+		name := UnescapeJSONPointer(ptr[len(section)+1:])
+
+		items, _ := components[section].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+		if v, ok := items[name]; ok {
+			collect(map[string]interface{}{"components": map[string]interface{}{
+				section: map[string]interface{}{name: v},
+			}})
+		}
+	}
+
+	for section, v := range components {
+		items, ok := v.(map[string]interface{})
+		if !ok || strings.HasPrefix(section, "x-") {
+			continue
+		}
+
+		for name := range items {
+			if !used[section][name] {
+				delete(items, name)
+			}
+		}
+
+		if len(items) == 0 {
+			delete(components, section)
+		}
+	}
+
+	if len(components) == 0 {
+		delete(doc, "components")
+	}
+}
+
+// componentOfRef returns section and name of component that contains local reference target.
+func componentOfRef(ref string) (section, name string, ok bool) {
+	if !strings.HasPrefix(ref, "#/components/") {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(ref, "#/components/"), "/", 3)
+	if len(parts) < 2 {
+		return "", "", false
+	}
+
+	return parts[0], UnescapeJSONPointer(parts[1]), true
+}
+
+// securityNames returns names of security schemes used in top-level and operation security requirements.
+func securityNames(doc map[string]interface{}) []string {
+	var names []string
+
+	add := func(v interface{}) {
+		reqs, _ := v.([]interface{}) //nolint:errcheck // Nil is valid.
+
+		for _, r := range reqs {
+			if req, ok := r.(map[string]interface{}); ok {
+				names = append(names, SortedKeys(req)...)
+			}
+		}
+	}
+
+	add(doc["security"])
+
+	WalkDocument(doc, DocumentVisitor{
+		Object: func(_ string, kind ObjectKind, obj map[string]interface{}) {
+			if kind == KindOperation {
+				add(obj["security"])
+			}
+		},
+	})
+
+	return names
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/refl"
+)
+
+// ResponseLink is a parameter of a link declared with `link` field tag of response structure.
+type ResponseLink struct {
+	OperationID string
+	Parameter   string
+	Expression  string
+}
+
+// ResponseLinks collects links declared with `link` field tags of response structure.
+//
+// Tag value is a comma-separated list of `operationId[:parameter]` targets, parameter defaults to
+// the name of field in response. For example, field `json:"id" link:"getUser:userId"` results in
+// link "getUser" with parameter "userId" being "$response.body#/id".
+//
+// Body fields are identified by `json` tag, top-level header fields are identified by `header` tag
+// or headerMapping (field name to header name).
+func ResponseLinks(structure interface{}, headerMapping map[string]string) []ResponseLink {
+	if structure == nil {
+		return nil
+	}
+
+	t := refl.DeepIndirect(reflect.TypeOf(structure))
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var links []ResponseLink
+
+	collectLinks(t, "", headerMapping, &links, map[reflect.Type]bool{})
+
+	return links
+}
+
+func collectLinks(t reflect.Type, ptr string, headerMapping map[string]string, links *[]ResponseLink, visited map[reflect.Type]bool) {
+	if visited[t] {
+		return
+	}
+
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+
+		ft := refl.DeepIndirect(sf.Type)
+
+		if ptr == "" {
+			header := sf.Tag.Get("header")
+			if h, ok := headerMapping[sf.Name]; ok {
+				header = h
+			}
+
+			if header != "" && header != "-" {
+				appendLinks(links, sf.Tag.Get("link"), header, "$response.header."+header)
+
+				continue
+			}
+		}
+
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			if sf.Anonymous && ft.Kind() == reflect.Struct {
+				collectLinks(ft, ptr, headerMapping, links, visited)
+			}
+
+			continue
+		}
+
+		fieldPtr := ptr + "/" + EscapeJSONPointer(name)
+
+		appendLinks(links, sf.Tag.Get("link"), name, "$response.body#"+fieldPtr)
+
+		if ft.Kind() == reflect.Struct {
+			collectLinks(ft, fieldPtr, nil, links, visited)
+		}
+	}
+}
+
+func appendLinks(links *[]ResponseLink, tag, name, expression string) {
+	if tag == "" {
+		return
+	}
+
+	for _, target := range strings.Split(tag, ",") {
+		operationID, param := strings.TrimSpace(target), ""
+		if i := strings.Index(operationID, ":"); i != -1 {
+			operationID, param = operationID[:i], operationID[i+1:]
+		}
+
+		if operationID == "" {
+			continue
+		}
+
+		if param == "" {
+			param = name
+		}
+
+		*links = append(*links, ResponseLink{
+			OperationID: operationID,
+			Parameter:   param,
+			Expression:  expression,
+		})
+	}
+}
@@ -1,7 +1,11 @@
 // Package internal keeps reusable internal code.
 package internal
 
-import "github.com/swaggest/openapi-go"
+import (
+	"fmt"
+
+	"github.com/swaggest/openapi-go"
+)
 
 // NewOperationContext creates OperationContext.
 func NewOperationContext(method, pathPattern string) *OperationContext {
@@ -18,6 +22,8 @@ type OperationContext struct {
 	req         []openapi.ContentUnit
 	resp        []openapi.ContentUnit
 
+	callbacks []openapi.Callback
+
 	isProcessingResponse bool
 	processingIn         openapi.In
 }
@@ -105,3 +111,21 @@ func (o *OperationContext) AddRespStructure(s interface{}, options ...openapi.Co
 
 	o.resp = append(o.resp, c)
 }
+
+// AppendCallback adds callback operation.
+func (o *OperationContext) AppendCallback(cb openapi.Callback) error {
+	for _, c := range o.callbacks {
+		if c.Name == cb.Name && c.Expression == cb.Expression && c.Operation.Method() == cb.Operation.Method() {
+			return fmt.Errorf("callback already exists: %s %s %s", cb.Name, cb.Operation.Method(), cb.Expression)
+		}
+	}
+
+	o.callbacks = append(o.callbacks, cb)
+
+	return nil
+}
+
+// Callbacks returns callbacks of an operation.
+func (o *OperationContext) Callbacks() []openapi.Callback {
+	return o.callbacks
+}
@@ -0,0 +1,307 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// ResolvePointer finds a value of generic JSON document by a local reference, e.g. "#/components/parameters/limit".
+func ResolvePointer(doc interface{}, ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("%w: %s", openapi.ErrNonLocalReference, ref)
+	}
+
+	cur := doc
+
+	ptr := strings.TrimPrefix(ref, "#")
+	if ptr == "" {
+		return cur, nil
+	}
+
+	for _, tok := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		tok = UnescapeJSONPointer(tok)
+
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			v, ok := c[tok]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", openapi.ErrReferenceNotFound, ref)
+			}
+
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, fmt.Errorf("%w: %s", openapi.ErrReferenceNotFound, ref)
+			}
+
+			cur = c[i]
+		default:
+			return nil, fmt.Errorf("%w: %s", openapi.ErrReferenceNotFound, ref)
+		}
+	}
+
+	return cur, nil
+}
+
+// ResolveRef finds a value by a local reference and follows chained references,
+// e.g. a component parameter that refers to another component parameter.
+func ResolveRef(doc interface{}, ref string) (interface{}, error) {
+	seen := map[string]bool{}
+
+	for {
+		if seen[ref] {
+			return nil, fmt.Errorf("%w: %s", openapi.ErrCircularReference, ref)
+		}
+
+		seen[ref] = true
+
+		v, err := ResolvePointer(doc, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v, nil
+		}
+
+		next, ok := m["$ref"].(string)
+		if !ok {
+			return v, nil
+		}
+
+		ref = next
+	}
+}
+
+// DereferenceOptions controls Dereference.
+type DereferenceOptions struct {
+	// KeepRefSiblings applies properties defined next to "$ref" (e.g. "description") on top of referenced value,
+	// siblings are ignored otherwise.
+	KeepRefSiblings bool
+}
+
+// Dereference returns a copy of generic OpenAPI document with local references replaced by referenced values.
+//
+// References that would lead to infinite expansion (e.g. recursive schemas) are left intact, so components
+// are preserved in the result. Non-local references are left intact too.
+func Dereference(doc map[string]interface{}, options DereferenceOptions) (map[string]interface{}, error) {
+	d := dereferencer{
+		doc:     doc,
+		options: options,
+		stack:   map[string]bool{},
+	}
+
+	res := make(map[string]interface{}, len(doc))
+
+	for _, k := range SortedKeys(doc) {
+		v := doc[k]
+
+		if c, ok := v.(map[string]interface{}); ok && k == "components" {
+			rc, err := d.components(c)
+			if err != nil {
+				return nil, err
+			}
+
+			res[k] = rc
+
+			continue
+		}
+
+		rv, err := d.value(v, k, false)
+		if err != nil {
+			return nil, err
+		}
+
+		res[k] = rv
+	}
+
+	return res, nil
+}
+
+type dereferencer struct {
+	doc     map[string]interface{}
+	options DereferenceOptions
+
+	// stack contains references that are being expanded.
+	stack map[string]bool
+}
+
+// components dereferences values of components while keeping each component on expansion stack,
+// so that a recursive component refers to itself instead of being expanded one more time.
+func (d *dereferencer) components(c map[string]interface{}) (map[string]interface{}, error) {
+	res := make(map[string]interface{}, len(c))
+
+	for _, section := range SortedKeys(c) {
+		items, ok := c[section].(map[string]interface{})
+		if !ok || strings.HasPrefix(section, "x-") {
+			res[section] = c[section]
+
+			continue
+		}
+
+		ritems := make(map[string]interface{}, len(items))
+
+		for _, name := range SortedKeys(items) {
+			self := "#/components/" + EscapeJSONPointer(section) + "/" + EscapeJSONPointer(name)
+
+			d.stack[self] = true
+			v, err := d.value(items[name], name, true)
+			delete(d.stack, self)
+
+			if err != nil {
+				return nil, err
+			}
+
+			ritems[name] = v
+		}
+
+		res[section] = ritems
+	}
+
+	return res, nil
+}
+
+// nameMaps are keys of objects that map arbitrary names to values.
+var nameMaps = map[string]bool{
+	"paths": true, "webhooks": true, "callbacks": true, "pathItems": true, "schemas": true, "responses": true,
+	"parameters": true, "examples": true, "requestBodies": true, "headers": true, "securitySchemes": true,
+	"links": true, "content": true, "encoding": true, "variables": true, "mapping": true,
+	"properties": true, "patternProperties": true, "$defs": true, "definitions": true, "dependentSchemas": true,
+}
+
+// literals are keys of values that are not a part of document structure and must not be dereferenced.
+var literals = map[string]bool{
+	"example": true, "default": true, "const": true, "enum": true, "value": true,
+}
+
+func (d *dereferencer) value(v interface{}, key string, inNameMap bool) (interface{}, error) {
+	if !inNameMap && (literals[key] || strings.HasPrefix(key, "x-")) {
+		return v, nil
+	}
+
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := vv["$ref"].(string); ok {
+			return d.ref(ref, vv, key, inNameMap)
+		}
+
+		res := make(map[string]interface{}, len(vv))
+		isNameMap := !inNameMap && nameMaps[key]
+
+		for _, k := range SortedKeys(vv) {
+			rv, err := d.value(vv[k], k, isNameMap)
+			if err != nil {
+				return nil, err
+			}
+
+			res[k] = rv
+		}
+
+		return res, nil
+	case []interface{}:
+		if key == "examples" {
+			return v, nil // JSON Schema examples are literal values.
+		}
+
+		res := make([]interface{}, len(vv))
+
+		for i, item := range vv {
+			rv, err := d.value(item, "", false)
+			if err != nil {
+				return nil, err
+			}
+
+			res[i] = rv
+		}
+
+		return res, nil
+	default:
+		return v, nil
+	}
+}
+
+func (d *dereferencer) ref(ref string, obj map[string]interface{}, key string, inNameMap bool) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") || d.stack[ref] {
+		return obj, nil
+	}
+
+	target, err := ResolvePointer(d.doc, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	d.stack[ref] = true
+	rv, err := d.value(target, key, inNameMap)
+	delete(d.stack, ref)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !d.options.KeepRefSiblings || len(obj) == 1 {
+		return rv, nil
+	}
+
+	m, ok := rv.(map[string]interface{})
+	if !ok {
+		return rv, nil
+	}
+
+	res := make(map[string]interface{}, len(m)+len(obj))
+
+	for k, v := range m {
+		res[k] = v
+	}
+
+	for k, v := range obj {
+		if k != "$ref" {
+			res[k] = v
+		}
+	}
+
+	return res, nil
+}
+
+// RewriteRefs replaces local references of a generic JSON document in place, including
+// discriminator mapping values.
+func RewriteRefs(v interface{}, rewrite func(ref string) string) {
+	rewriteRefs(v, "", false, rewrite)
+}
+
+func rewriteRefs(v interface{}, key string, inNameMap bool, rewrite func(ref string) string) {
+	if !inNameMap && (literals[key] || strings.HasPrefix(key, "x-")) {
+		return
+	}
+
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := vv["$ref"].(string); ok {
+			vv["$ref"] = rewrite(ref)
+		}
+
+		isNameMap := !inNameMap && nameMaps[key]
+
+		for k, item := range vv {
+			if s, ok := item.(string); ok && isNameMap && key == "mapping" {
+				vv[k] = rewrite(s)
+
+				continue
+			}
+
+			rewriteRefs(item, k, isNameMap, rewrite)
+		}
+	case []interface{}:
+		if key == "examples" {
+			return // JSON Schema examples are literal values.
+		}
+
+		for _, item := range vv {
+			rewriteRefs(item, "", false, rewrite)
+		}
+	}
+}
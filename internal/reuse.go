@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// ComponentsReuse deduplicates identical entities of a kind into reusable components.
+type ComponentsReuse struct {
+	kind   openapi.ComponentKind
+	config openapi.ReuseComponents
+
+	names    map[string]bool   // Names of existing components.
+	existing map[string]string // Hash to name of existing components.
+	groups   []*reuseGroup
+	index    map[string]*reuseGroup
+}
+
+type reuseGroup struct {
+	hash    string
+	name    string
+	entity  interface{}
+	setRefs []func(ref string)
+}
+
+// NewComponentsReuse creates deduplicator of entities with existing components (name to entity).
+func NewComponentsReuse(
+	kind openapi.ComponentKind,
+	config openapi.ReuseComponents,
+	existing map[string]interface{},
+) (*ComponentsReuse, error) {
+	c := &ComponentsReuse{
+		kind:     kind,
+		config:   config,
+		names:    make(map[string]bool, len(existing)),
+		existing: make(map[string]string, len(existing)),
+		index:    map[string]*reuseGroup{},
+	}
+
+	for _, name := range SortedKeys(existing) {
+		c.names[name] = true
+
+		h, err := hash(existing[name])
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := c.existing[h]; !ok {
+			c.existing[h] = name
+		}
+	}
+
+	return c, nil
+}
+
+// Add registers an inline entity occurrence with default component name and reference setter.
+func (c *ComponentsReuse) Add(name string, entity interface{}, setRef func(ref string)) error {
+	h, err := hash(entity)
+	if err != nil {
+		return err
+	}
+
+	g, ok := c.index[h]
+	if !ok {
+		g = &reuseGroup{hash: h, name: name, entity: entity}
+		c.index[h] = g
+		c.groups = append(c.groups, g)
+	}
+
+	g.setRefs = append(g.setRefs, setRef)
+
+	return nil
+}
+
+// Apply replaces duplicated entities with references, new components are stored with add.
+func (c *ComponentsReuse) Apply(add func(name string, entity interface{})) {
+	for _, g := range c.groups {
+		name, ok := c.existing[g.hash]
+
+		if !ok {
+			if len(g.setRefs) < 2 {
+				continue
+			}
+
+			name = g.name
+			if c.config.Name != nil {
+				name = c.config.Name(c.kind, name, g.entity)
+			}
+
+			name = c.uniqueName(SanitizeComponentName(name))
+			c.names[name] = true
+			c.existing[g.hash] = name
+
+			add(name, g.entity)
+		}
+
+		for _, setRef := range g.setRefs {
+			setRef("#/components/" + string(c.kind) + "/" + EscapeJSONPointer(name))
+		}
+	}
+}
+
+func (c *ComponentsReuse) uniqueName(name string) string {
+	if !c.names[name] {
+		return name
+	}
+
+	for i := 2; ; i++ {
+		n := name + strconv.Itoa(i)
+		if !c.names[n] {
+			return n
+		}
+	}
+}
+
+func hash(entity interface{}) (string, error) {
+	j, err := json.Marshal(entity)
+	if err != nil {
+		return "", err
+	}
+
+	return string(j), nil
+}
+
+var regexComponentName = regexp.MustCompile(`[^a-zA-Z0-9.\-_]+`)
+
+// SanitizeComponentName replaces characters that are not allowed in component names.
+func SanitizeComponentName(name string) string {
+	name = regexComponentName.ReplaceAllString(name, "_")
+	if name == "" {
+		name = "_"
+	}
+
+	return name
+}
+
+// ResponseComponentName returns default component name of a response by its status code.
+func ResponseComponentName(status string) string {
+	if status == "default" {
+		return "Default"
+	}
+
+	code, err := strconv.Atoi(status)
+	if err != nil || http.StatusText(code) == "" {
+		return status
+	}
+
+	words := strings.Fields(strings.ReplaceAll(http.StatusText(code), "'", ""))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+
+	return strings.Join(words, "")
+}
+
+// RequestBodyComponentName returns default component name of a request body by references of its content schemas.
+func RequestBodyComponentName(schemaRefs ...string) string {
+	for _, ref := range schemaRefs {
+		if ref != "" {
+			return ref[strings.LastIndex(ref, "/")+1:]
+		}
+	}
+
+	return "RequestBody"
+}
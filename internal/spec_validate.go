@@ -0,0 +1,363 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/swaggest/openapi-go"
+)
+
+var (
+	metaSchemasMu sync.Mutex
+	metaSchemas   = map[string]*jsonschema.Schema{}
+)
+
+func metaSchema(url string, data []byte, draft *jsonschema.Draft) (*jsonschema.Schema, error) {
+	metaSchemasMu.Lock()
+	defer metaSchemasMu.Unlock()
+
+	if s, ok := metaSchemas[url]; ok {
+		return s, nil
+	}
+
+	c := jsonschema.NewCompiler()
+	c.Draft = draft
+
+	if err := c.AddResource(url, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	s, err := c.Compile(url)
+	if err != nil {
+		return nil, err
+	}
+
+	metaSchemas[url] = s
+
+	return s, nil
+}
+
+// ValidateSpec checks OpenAPI document against meta-schema and semantic rules:
+// unique operation IDs, resolvable local references, path templates matching path parameters and
+// security requirements referencing defined schemes.
+//
+// It returns *openapi.SpecError with all found problems.
+func ValidateSpec(spec interface{}, metaSchemaURL string, metaSchemaData []byte, draft *jsonschema.Draft) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	var doc interface{}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	if err := d.Decode(&doc); err != nil {
+		return err
+	}
+
+	ms, err := metaSchema(metaSchemaURL, metaSchemaData, draft)
+	if err != nil {
+		return err
+	}
+
+	v := specValidator{}
+
+	if err := ms.Validate(doc); err != nil {
+		var ve *jsonschema.ValidationError
+		if !errors.As(err, &ve) {
+			return err
+		}
+
+		for _, l := range LeafErrors(ve) {
+			ptr := l.InstanceLocation
+
+			// Instance location is percent-encoded by validator, it is decoded for consistency with other problems.
+			if p, err := url.PathUnescape(ptr); err == nil {
+				ptr = p
+			}
+
+			v.add(ptr, l.Message)
+		}
+	}
+
+	if d, ok := doc.(map[string]interface{}); ok {
+		v.doc = d
+		v.checkSemantics()
+	}
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		if v.problems[i].Pointer != v.problems[j].Pointer {
+			return v.problems[i].Pointer < v.problems[j].Pointer
+		}
+
+		return v.problems[i].Message < v.problems[j].Message
+	})
+
+	problems := v.problems[:0]
+
+	for i, p := range v.problems {
+		if i == 0 || p != v.problems[i-1] {
+			problems = append(problems, p)
+		}
+	}
+
+	return &openapi.SpecError{Problems: problems}
+}
+
+// LeafErrors returns innermost causes of a validation error, they point to exact locations of violations.
+func LeafErrors(ve *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(ve.Causes) == 0 {
+		return []*jsonschema.ValidationError{ve}
+	}
+
+	var res []*jsonschema.ValidationError
+
+	for _, c := range ve.Causes {
+		res = append(res, LeafErrors(c)...)
+	}
+
+	return res
+}
+
+type specValidator struct {
+	doc      map[string]interface{}
+	problems []openapi.SpecProblem
+}
+
+func (v *specValidator) add(ptr, msg string) {
+	v.problems = append(v.problems, openapi.SpecProblem{Pointer: ptr, Message: msg})
+}
+
+func (v *specValidator) checkSemantics() {
+	operationIDs := map[string]string{}
+
+	WalkDocument(v.doc, DocumentVisitor{
+		Schema: func(ptr string, schema interface{}) interface{} {
+			v.checkSchemaRefs(ptr, schema)
+
+			return schema
+		},
+		Reference: func(ptr string, ref map[string]interface{}) {
+			v.checkRef(ptr, ref["$ref"])
+		},
+		Object: func(ptr string, kind ObjectKind, obj map[string]interface{}) {
+			if kind != KindOperation {
+				return
+			}
+
+			if id, ok := obj["operationId"].(string); ok {
+				if first, ok := operationIDs[id]; ok {
+					v.add(ptr+"/operationId", "duplicate operationId "+strconv.Quote(id)+", first used at "+first)
+				} else {
+					operationIDs[id] = ptr
+				}
+			}
+
+			v.checkSecurity(ptr+"/security", obj["security"])
+		},
+	})
+
+	v.checkSecurity("/security", v.doc["security"])
+	v.checkPathTemplates()
+}
+
+// resolve finds value by local reference.
+func (v *specValidator) resolve(ref string) (interface{}, bool) {
+	r, err := ResolvePointer(v.doc, ref)
+
+	return r, err == nil
+}
+
+func (v *specValidator) checkRef(ptr string, ref interface{}) {
+	r, ok := ref.(string)
+	if !ok || !strings.HasPrefix(r, "#") {
+		return // Non-local references are not resolved.
+	}
+
+	if _, ok := v.resolve(r); !ok {
+		v.add(ptr+"/$ref", "unresolved reference "+strconv.Quote(r))
+	}
+}
+
+var (
+	schemaMapKeywords   = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"}
+	schemaSliceKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+	schemaKeywords      = []string{
+		"items", "additionalItems", "additionalProperties", "not", "contains", "if", "then", "else",
+		"propertyNames", "unevaluatedItems", "unevaluatedProperties",
+	}
+)
+
+func (v *specValidator) checkSchemaRefs(ptr string, schema interface{}) {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if ref, ok := s["$ref"]; ok {
+		v.checkRef(ptr, ref)
+	}
+
+	for _, k := range schemaMapKeywords {
+		if m, ok := s[k].(map[string]interface{}); ok {
+			for _, name := range SortedKeys(m) {
+				v.checkSchemaRefs(ptr+"/"+k+"/"+EscapeJSONPointer(name), m[name])
+			}
+		}
+	}
+
+	for _, k := range schemaSliceKeywords {
+		if items, ok := s[k].([]interface{}); ok {
+			for i, item := range items {
+				v.checkSchemaRefs(ptr+"/"+k+"/"+strconv.Itoa(i), item)
+			}
+		}
+	}
+
+	for _, k := range schemaKeywords {
+		switch sub := s[k].(type) {
+		case map[string]interface{}:
+			v.checkSchemaRefs(ptr+"/"+k, sub)
+		case []interface{}: // Draft 4 tuple items.
+			for i, item := range sub {
+				v.checkSchemaRefs(ptr+"/"+k+"/"+strconv.Itoa(i), item)
+			}
+		}
+	}
+}
+
+func (v *specValidator) checkSecurity(ptr string, security interface{}) {
+	requirements, ok := security.([]interface{})
+	if !ok {
+		return
+	}
+
+	var schemes map[string]interface{}
+	if c, ok := v.doc["components"].(map[string]interface{}); ok {
+		schemes, _ = c["securitySchemes"].(map[string]interface{}) //nolint:errcheck // Nil is a valid result.
+	}
+
+	for i, req := range requirements {
+		req, ok := req.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, name := range SortedKeys(req) {
+			if _, ok := schemes[name]; !ok {
+				v.add(ptr+"/"+strconv.Itoa(i)+"/"+EscapeJSONPointer(name), "undefined security scheme "+strconv.Quote(name))
+			}
+		}
+	}
+}
+
+var regexPathTemplate = regexp.MustCompile(`{([^}]+)}`)
+
+type pathParam struct {
+	ptr  string
+	name string
+}
+
+func (v *specValidator) pathParams(ptr string, params interface{}) []pathParam {
+	list, ok := params.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var res []pathParam
+
+	for i, p := range list {
+		pm, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if ref, ok := pm["$ref"].(string); ok {
+			r, ok := v.resolve(ref)
+			if !ok {
+				continue
+			}
+
+			if pm, ok = r.(map[string]interface{}); !ok {
+				continue
+			}
+		}
+
+		if in, _ := pm["in"].(string); in == "path" { //nolint:errcheck // Empty is a valid result.
+			name, _ := pm["name"].(string) //nolint:errcheck // Empty is a valid result.
+			res = append(res, pathParam{ptr: ptr + "/" + strconv.Itoa(i), name: name})
+		}
+	}
+
+	return res
+}
+
+func (v *specValidator) checkPathTemplates() {
+	paths, ok := v.doc["paths"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for _, path := range SortedKeys(paths) {
+		pi, ok := paths[path].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		ptr := "/paths/" + EscapeJSONPointer(path)
+		template := map[string]bool{}
+
+		for _, m := range regexPathTemplate.FindAllStringSubmatch(path, -1) {
+			template[m[1]] = true
+		}
+
+		common := v.pathParams(ptr+"/parameters", pi["parameters"])
+
+		for _, p := range common {
+			if !template[p.name] {
+				v.add(p.ptr, "path parameter "+strconv.Quote(p.name)+" is not in path template")
+			}
+		}
+
+		for _, method := range httpMethods {
+			op, ok := pi[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			declared := map[string]bool{}
+
+			for _, p := range common {
+				declared[p.name] = true
+			}
+
+			for _, p := range v.pathParams(ptr+"/"+method+"/parameters", op["parameters"]) {
+				declared[p.name] = true
+
+				if !template[p.name] {
+					v.add(p.ptr, "path parameter "+strconv.Quote(p.name)+" is not in path template")
+				}
+			}
+
+			for _, m := range regexPathTemplate.FindAllStringSubmatch(path, -1) {
+				if !declared[m[1]] {
+					v.add(ptr+"/"+method, "missing path parameter "+strconv.Quote(m[1]))
+				}
+			}
+		}
+	}
+}
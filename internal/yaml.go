@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DecodeDocument reads YAML or JSON bytes into a generic JSON value.
+func DecodeDocument(data []byte) (interface{}, error) {
+	var v interface{}
+
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	return toJSONValue(v), nil
+}
+
+// toJSONValue recursively converts YAML maps with interface{} keys to maps with string keys.
+func toJSONValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(x))
+
+		for k, v := range x {
+			if ks, ok := k.(string); ok {
+				m[ks] = toJSONValue(v)
+			} else {
+				m[fmt.Sprint(k)] = toJSONValue(v)
+			}
+		}
+
+		return m
+	case []interface{}:
+		for i, v := range x {
+			x[i] = toJSONValue(v)
+		}
+	case map[string]interface{}:
+		for k, v := range x {
+			x[k] = toJSONValue(v)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,233 @@
+// Package lint checks OpenAPI documents against style rules.
+package lint
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/convert"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Severity describes importance of a finding.
+type Severity string
+
+// Severity values enumeration.
+const (
+	SeverityError   = Severity("error")
+	SeverityWarning = Severity("warning")
+	SeverityInfo    = Severity("info")
+)
+
+// Finding describes a rule violation.
+type Finding struct {
+	// Rule is a name of violated rule.
+	Rule string
+
+	Severity Severity
+
+	// Pointer is a JSON Pointer to the violating location in the document.
+	Pointer string
+
+	// Message describes the violation.
+	Message string
+}
+
+// String implements fmt.Stringer.
+func (f Finding) String() string {
+	return "[" + string(f.Severity) + "] " + f.Pointer + ": " + f.Message + " (" + f.Rule + ")"
+}
+
+// Report contains findings of a linter.
+type Report struct {
+	Findings []Finding
+}
+
+// HasErrors is true if there are findings with error severity.
+func (r Report) HasErrors() bool {
+	return len(r.BySeverity(SeverityError)) > 0
+}
+
+// BySeverity returns findings of a severity.
+func (r Report) BySeverity(severity Severity) []Finding {
+	var res []Finding
+
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			res = append(res, f)
+		}
+	}
+
+	return res
+}
+
+// Rule checks a document and reports findings.
+type Rule struct {
+	// Name identifies the rule in findings, e.g. "operation-tags".
+	Name string
+
+	// Severity is assigned to findings of the rule.
+	Severity Severity
+
+	// Description explains the rule.
+	Description string
+
+	// Check inspects document and reports violations with Context.Report.
+	Check func(c *Context)
+}
+
+// Operation is an operation of a document with its location.
+type Operation struct {
+	// Pointer is a JSON Pointer to the operation, e.g. "/paths/~1users/get".
+	Pointer string
+
+	// Method is an HTTP method in upper case.
+	Method string
+
+	// Path is a URL path pattern or a webhook name.
+	Path string
+
+	// IsWebhook is true for webhook operations.
+	IsWebhook bool
+
+	PathItem  *openapi31.PathItem
+	Operation *openapi31.Operation
+}
+
+// Context provides a rule with a document and collects findings.
+type Context struct {
+	// Spec is a linted document, OpenAPI 3.0 documents are checked in their OpenAPI 3.1 representation.
+	Spec *openapi31.Spec
+
+	rule     Rule
+	findings []Finding
+}
+
+// Report adds a finding of current rule.
+func (c *Context) Report(ptr, message string) {
+	c.findings = append(c.findings, Finding{
+		Rule:     c.rule.Name,
+		Severity: c.rule.Severity,
+		Pointer:  ptr,
+		Message:  message,
+	})
+}
+
+var methods = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+	http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace,
+}
+
+// Operations returns operations of paths and webhooks in a deterministic order.
+func (c *Context) Operations() []Operation {
+	var ops []Operation
+
+	add := func(prefix, path string, pi *openapi31.PathItem, isWebhook bool) {
+		for _, method := range methods {
+			op, _ := pi.Operation(method) //nolint:errcheck // Method is valid.
+			if op == nil {
+				continue
+			}
+
+			ops = append(ops, Operation{
+				Pointer:   prefix + internal.EscapeJSONPointer(path) + "/" + strings.ToLower(method),
+				Method:    method,
+				Path:      path,
+				IsWebhook: isWebhook,
+				PathItem:  pi,
+				Operation: op,
+			})
+		}
+	}
+
+	if c.Spec.Paths != nil {
+		paths := c.Spec.Paths.MapOfPathItemValues
+		for _, path := range internal.SortedMapKeys(paths) {
+			pi := paths[path]
+			add("/paths/", path, &pi, false)
+		}
+	}
+
+	for _, name := range internal.SortedMapKeys(c.Spec.Webhooks) {
+		if pi := c.Spec.Webhooks[name].PathItem; pi != nil {
+			add("/webhooks/", name, pi, true)
+		}
+	}
+
+	return ops
+}
+
+// Linter checks documents with a set of rules.
+type Linter struct {
+	Rules []Rule
+}
+
+// New creates linter with default rules.
+func New() *Linter {
+	return &Linter{Rules: DefaultRules()}
+}
+
+// Register adds custom rules.
+func (l *Linter) Register(rules ...Rule) {
+	l.Rules = append(l.Rules, rules...)
+}
+
+// Disable removes rules by names.
+func (l *Linter) Disable(names ...string) {
+	rules := l.Rules[:0]
+
+	for _, r := range l.Rules {
+		disabled := false
+
+		for _, n := range names {
+			if r.Name == n {
+				disabled = true
+
+				break
+			}
+		}
+
+		if !disabled {
+			rules = append(rules, r)
+		}
+	}
+
+	l.Rules = rules
+}
+
+// Lint checks a spec, it can be *openapi3.Spec or *openapi31.Spec.
+//
+// Findings are sorted by pointer and rule name.
+func (l *Linter) Lint(s openapi.SpecSchema) (Report, error) {
+	spec, err := convert.AsOpenAPI31(s)
+	if err != nil {
+		return Report{}, err
+	}
+
+	var findings []Finding
+
+	for _, r := range l.Rules {
+		c := Context{Spec: spec, rule: r}
+		r.Check(&c)
+
+		findings = append(findings, c.findings...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Pointer != findings[j].Pointer {
+			return findings[i].Pointer < findings[j].Pointer
+		}
+
+		return findings[i].Rule < findings[j].Rule
+	})
+
+	return Report{Findings: findings}, nil
+}
+
+// Lint checks a spec with default rules.
+func Lint(s openapi.SpecSchema) (Report, error) {
+	return New().Lint(s)
+}
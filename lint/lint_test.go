@@ -0,0 +1,132 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go/lint"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func findings(r lint.Report) []string {
+	res := make([]string, 0, len(r.Findings))
+
+	for _, f := range r.Findings {
+		res = append(res, f.String())
+	}
+
+	return res
+}
+
+func TestLint(t *testing.T) {
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.1.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      parameters:
+        - {name: limit, in: query, description: Max items., schema: {type: integer}}
+        - {$ref: '#/components/parameters/cursor'}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {type: object, properties: {name: {type: string}}}}
+        "400": {description: Bad Request}
+    post:
+      operationId: create_pet
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        "404": {description: Not Found}
+        default:
+          description: Error
+          content:
+            application/json:
+              schema: {type: object}
+webhooks:
+  petAdded:
+    post:
+      tags: [pets]
+      responses:
+        "4XX": {description: Rejected}
+components:
+  parameters:
+    cursor: {name: cursor, in: query, schema: {type: string}}
+  schemas:
+    Pet: {type: object}
+`)))
+
+	r, err := lint.Lint(&s)
+	require.NoError(t, err)
+
+	assert.True(t, r.HasErrors())
+	assert.Equal(t, []string{
+		"[warning] /components/parameters/cursor: missing description of query parameter \"cursor\" (parameter-description)",
+		"[warning] /paths/~1pets/get/responses/200/content/application~1json/schema/items: inline object schema, " +
+			"use a reference to components (no-inline-response-object)",
+		"[warning] /paths/~1pets/post: missing tags (operation-tags)",
+		"[warning] /paths/~1pets/post/operationId: operationId \"create_pet\" is not in camelCase (operation-id-camel-case)",
+		"[warning] /paths/~1pets/post/responses: missing 4xx response (operation-4xx-response)",
+		"[error] /paths/~1pets~1{id}/get/operationId: duplicate operationId \"listPets\", " +
+			"first used at /paths/~1pets/get (operation-id-unique)",
+		"[warning] /paths/~1pets~1{id}/get/responses/default/content/application~1json/schema: inline object schema, " +
+			"use a reference to components (no-inline-response-object)",
+		"[warning] /paths/~1pets~1{id}/parameters/0: missing description of path parameter \"id\" (parameter-description)",
+		"[error] /webhooks/petAdded/post: missing operationId (operation-id)",
+	}, findings(r))
+}
+
+func TestLinter_Register(t *testing.T) {
+	s := openapi3.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200": {description: OK}
+`)))
+
+	l := lint.New()
+	l.Disable(lint.RuleOperationTags, lint.RuleOperation4xxResponse)
+	l.Register(lint.Rule{
+		Name:     "summary",
+		Severity: lint.SeverityInfo,
+		Check: func(c *lint.Context) {
+			for _, op := range c.Operations() {
+				if op.Operation.Summary == nil {
+					c.Report(op.Pointer, "missing summary of "+op.Method+" "+op.Path)
+				}
+			}
+		},
+	})
+
+	r, err := l.Lint(&s)
+	require.NoError(t, err)
+
+	assert.False(t, r.HasErrors())
+	assert.Equal(t, []string{
+		"[info] /paths/~1pets/get: missing summary of GET /pets (summary)",
+	}, findings(r))
+	assert.Len(t, r.BySeverity(lint.SeverityInfo), 1)
+}
@@ -0,0 +1,238 @@
+package lint
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Names of default rules.
+const (
+	RuleOperationID            = "operation-id"
+	RuleOperationIDUnique      = "operation-id-unique"
+	RuleOperationIDCamelCase   = "operation-id-camel-case"
+	RuleOperationTags          = "operation-tags"
+	RuleOperation4xxResponse   = "operation-4xx-response"
+	RuleNoInlineResponseObject = "no-inline-response-object"
+	RuleParameterDescription   = "parameter-description"
+)
+
+// DefaultRules returns a default rule set.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Name:        RuleOperationID,
+			Severity:    SeverityError,
+			Description: "Operation must have operationId.",
+			Check:       checkOperationID,
+		},
+		{
+			Name:        RuleOperationIDUnique,
+			Severity:    SeverityError,
+			Description: "Operation ID must be unique.",
+			Check:       checkOperationIDUnique,
+		},
+		{
+			Name:        RuleOperationIDCamelCase,
+			Severity:    SeverityWarning,
+			Description: "Operation ID must be in camelCase.",
+			Check:       checkOperationIDCamelCase,
+		},
+		{
+			Name:        RuleOperationTags,
+			Severity:    SeverityWarning,
+			Description: "Operation must have at least one tag.",
+			Check:       checkOperationTags,
+		},
+		{
+			Name:        RuleOperation4xxResponse,
+			Severity:    SeverityWarning,
+			Description: "Operation must document at least one 4xx response.",
+			Check:       checkOperation4xxResponse,
+		},
+		{
+			Name:        RuleNoInlineResponseObject,
+			Severity:    SeverityWarning,
+			Description: "Response object schemas must be defined in components.",
+			Check:       checkNoInlineResponseObject,
+		},
+		{
+			Name:        RuleParameterDescription,
+			Severity:    SeverityWarning,
+			Description: "Parameter must have description.",
+			Check:       checkParameterDescription,
+		},
+	}
+}
+
+func checkOperationID(c *Context) {
+	for _, op := range c.Operations() {
+		if op.Operation.ID == nil || *op.Operation.ID == "" {
+			c.Report(op.Pointer, "missing operationId")
+		}
+	}
+}
+
+func checkOperationIDUnique(c *Context) {
+	seen := map[string]string{}
+
+	for _, op := range c.Operations() {
+		if op.Operation.ID == nil || *op.Operation.ID == "" {
+			continue
+		}
+
+		id := *op.Operation.ID
+		if first, ok := seen[id]; ok {
+			c.Report(op.Pointer+"/operationId", "duplicate operationId "+strconv.Quote(id)+", first used at "+first)
+
+			continue
+		}
+
+		seen[id] = op.Pointer
+	}
+}
+
+var camelCase = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+func checkOperationIDCamelCase(c *Context) {
+	for _, op := range c.Operations() {
+		if op.Operation.ID == nil || *op.Operation.ID == "" {
+			continue
+		}
+
+		if !camelCase.MatchString(*op.Operation.ID) {
+			c.Report(op.Pointer+"/operationId", "operationId "+strconv.Quote(*op.Operation.ID)+" is not in camelCase")
+		}
+	}
+}
+
+func checkOperationTags(c *Context) {
+	for _, op := range c.Operations() {
+		if len(op.Operation.Tags) == 0 {
+			c.Report(op.Pointer, "missing tags")
+		}
+	}
+}
+
+func checkOperation4xxResponse(c *Context) {
+	for _, op := range c.Operations() {
+		found := false
+
+		if op.Operation.Responses != nil {
+			for status := range op.Operation.Responses.MapOfResponseOrReferenceValues {
+				if strings.HasPrefix(status, "4") {
+					found = true
+
+					break
+				}
+			}
+		}
+
+		if !found {
+			c.Report(op.Pointer+"/responses", "missing 4xx response")
+		}
+	}
+}
+
+func checkNoInlineResponseObject(c *Context) {
+	for _, op := range c.Operations() {
+		if op.Operation.Responses == nil {
+			continue
+		}
+
+		responses := op.Operation.Responses.MapOfResponseOrReferenceValues
+
+		for _, status := range internal.SortedMapKeys(responses) {
+			checkInlineResponseObject(c, op.Pointer+"/responses/"+status, responses[status].Response)
+		}
+
+		if d := op.Operation.Responses.Default; d != nil {
+			checkInlineResponseObject(c, op.Pointer+"/responses/default", d.Response)
+		}
+	}
+}
+
+func checkInlineResponseObject(c *Context, ptr string, resp *openapi31.Response) {
+	if resp == nil {
+		return
+	}
+
+	for _, ct := range internal.SortedMapKeys(resp.Content) {
+		sptr := ptr + "/content/" + internal.EscapeJSONPointer(ct) + "/schema"
+		s := resp.Content[ct].Schema
+
+		if items, ok := s["items"].(map[string]interface{}); ok && hasType(s, "array") {
+			s = items
+			sptr += "/items"
+		}
+
+		if isInlineObject(s) {
+			c.Report(sptr, "inline object schema, use a reference to components")
+		}
+	}
+}
+
+func isInlineObject(s map[string]interface{}) bool {
+	if s == nil || s["$ref"] != nil {
+		return false
+	}
+
+	return hasType(s, "object") || s["properties"] != nil
+}
+
+func hasType(s map[string]interface{}, t string) bool {
+	switch st := s["type"].(type) {
+	case string:
+		return st == t
+	case []interface{}:
+		for _, v := range st {
+			if v == t {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func checkParameterDescription(c *Context) {
+	check := func(ptr string, params []openapi31.ParameterOrReference) {
+		for i, pr := range params {
+			p := pr.Parameter
+			if p == nil || (p.Description != nil && *p.Description != "") {
+				continue
+			}
+
+			c.Report(ptr+"/parameters/"+strconv.Itoa(i), "missing description of "+string(p.In)+" parameter "+strconv.Quote(p.Name))
+		}
+	}
+
+	seen := map[*openapi31.PathItem]bool{}
+
+	for _, op := range c.Operations() {
+		if !seen[op.PathItem] {
+			seen[op.PathItem] = true
+
+			check(op.Pointer[:strings.LastIndex(op.Pointer, "/")], op.PathItem.Parameters)
+		}
+
+		check(op.Pointer, op.Operation.Parameters)
+	}
+
+	if c.Spec.Components == nil {
+		return
+	}
+
+	for _, name := range internal.SortedMapKeys(c.Spec.Components.Parameters) {
+		p := c.Spec.Components.Parameters[name].Parameter
+		if p == nil || (p.Description != nil && *p.Description != "") {
+			continue
+		}
+
+		c.Report("/components/parameters/"+internal.EscapeJSONPointer(name),
+			"missing description of "+string(p.In)+" parameter "+strconv.Quote(p.Name))
+	}
+}
@@ -0,0 +1,579 @@
+// Package merge combines several OpenAPI 3.1 specs into a single spec, e.g. to publish an API gateway spec
+// of multiple services.
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// ErrOperationConflict is returned when merged specs define the same operation or the same operationId.
+var ErrOperationConflict = errors.New("conflicting operation")
+
+// ErrComponentCollision is returned for components with the same name and different values
+// when CollisionPolicy is FailOnCollision.
+var ErrComponentCollision = errors.New("component name collision")
+
+// CollisionPolicy defines how to merge components that have the same name and different values.
+type CollisionPolicy int
+
+const (
+	// FailOnCollision makes Merge fail with ErrComponentCollision.
+	FailOnCollision CollisionPolicy = iota
+
+	// RenameOnCollision renames colliding component of a later source by prefixing it with source name,
+	// references to the component are updated.
+	RenameOnCollision
+)
+
+// Source is a spec to merge.
+type Source struct {
+	// Spec is required.
+	Spec *openapi31.Spec
+
+	// Name identifies the source in errors and prefixes renamed components, e.g. "orders".
+	Name string
+
+	// PathPrefix is prepended to paths of the spec, e.g. "/orders".
+	PathPrefix string
+
+	// TagPrefix is prepended to tags of the spec and tags of its operations, e.g. "Orders: ".
+	TagPrefix string
+}
+
+// Options configures Merge.
+type Options struct {
+	// Info of the merged spec, info of the first source is used if nil.
+	Info *openapi31.Info
+
+	// Collisions defines handling of component name collisions, components with equal values are always shared.
+	Collisions CollisionPolicy
+}
+
+// Merge combines sources into a single spec.
+//
+// Paths and webhooks of all sources are combined, sources may contribute different operations to the same path,
+// but the same operation (or operationId) in more than one source fails with ErrOperationConflict.
+// Components (including security schemes) are combined by name according to Options.Collisions.
+// Servers and tags are combined without duplicates, top-level security requirements that are not common
+// to all sources are moved to operations of their sources.
+func Merge(options Options, sources ...Source) (*openapi31.Spec, error) {
+	m := merger{
+		options:      options,
+		doc:          map[string]interface{}{},
+		operationIDs: map[string]string{},
+	}
+
+	docs := make([]map[string]interface{}, 0, len(sources))
+
+	for i, src := range sources {
+		if src.Spec == nil {
+			return nil, fmt.Errorf("source %d: missing spec", i)
+		}
+
+		doc, err := internal.ToDocument(src.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sourceName(src, i), err)
+		}
+
+		docs = append(docs, doc)
+	}
+
+	commonSecurity := sharedSecurity(docs)
+
+	for i, doc := range docs {
+		src := sources[i]
+		name := sourceName(src, i)
+
+		if i == 0 {
+			m.first(doc)
+		}
+
+		if err := m.components(name, src, doc); err != nil {
+			return nil, err
+		}
+
+		if _, ok := doc["security"]; ok && !commonSecurity {
+			pushSecurity(doc)
+		}
+
+		namespaceTags(doc, src.TagPrefix)
+
+		if err := m.paths("paths", name, src.PathPrefix, doc); err != nil {
+			return nil, err
+		}
+
+		if err := m.paths("webhooks", name, "", doc); err != nil {
+			return nil, err
+		}
+
+		m.list("servers", doc)
+		m.tags(doc)
+	}
+
+	if commonSecurity && len(docs) > 0 {
+		if sec, ok := docs[0]["security"]; ok {
+			m.doc["security"] = sec
+		}
+	}
+
+	if options.Info != nil {
+		m.doc["info"] = options.Info
+	}
+
+	s := &openapi31.Spec{}
+
+	if err := internal.FromDocument(m.doc, s); err != nil {
+		return nil, fmt.Errorf("loading merged document: %w", err)
+	}
+
+	return s, nil
+}
+
+type merger struct {
+	options Options
+	doc     map[string]interface{}
+
+	// operationIDs maps operationId to a name of source that defines it.
+	operationIDs map[string]string
+}
+
+func sourceName(src Source, i int) string {
+	if src.Name != "" {
+		return src.Name
+	}
+
+	return "source " + strconv.Itoa(i)
+}
+
+// first copies document properties of the first source.
+func (m *merger) first(doc map[string]interface{}) {
+	for _, k := range []string{"openapi", "info", "jsonSchemaDialect", "externalDocs"} {
+		if v, ok := doc[k]; ok {
+			m.doc[k] = v
+		}
+	}
+
+	for k, v := range doc {
+		if strings.HasPrefix(k, "x-") {
+			m.doc[k] = v
+		}
+	}
+}
+
+// components adds components of a source, it renames colliding components and updates references in doc.
+func (m *merger) components(name string, src Source, doc map[string]interface{}) error {
+	sc, _ := doc["components"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+	if len(sc) == 0 {
+		return nil
+	}
+
+	mc, _ := m.doc["components"].(map[string]interface{}) //nolint:errcheck // Nil is replaced.
+	if mc == nil {
+		mc = map[string]interface{}{}
+		m.doc["components"] = mc
+	}
+
+	refs := map[string]string{}
+	schemes := map[string]string{}
+	added := map[string]map[string]interface{}{}
+
+	for _, section := range internal.SortedKeys(sc) {
+		items, ok := sc[section].(map[string]interface{})
+		if !ok || strings.HasPrefix(section, "x-") {
+			if _, exists := mc[section]; !exists {
+				mc[section] = sc[section]
+			}
+
+			continue
+		}
+
+		mitems, _ := mc[section].(map[string]interface{}) //nolint:errcheck // Nil is replaced.
+		if mitems == nil {
+			mitems = map[string]interface{}{}
+			mc[section] = mitems
+		}
+
+		added[section] = map[string]interface{}{}
+
+		for _, item := range internal.SortedKeys(items) {
+			existing, exists := mitems[item]
+			if !exists {
+				added[section][item] = items[item]
+
+				continue
+			}
+
+			if reflect.DeepEqual(existing, items[item]) {
+				continue
+			}
+
+			if m.options.Collisions != RenameOnCollision {
+				return fmt.Errorf("%s: %w: components/%s/%s", name, ErrComponentCollision, section, item)
+			}
+
+			renamed := m.uniqueName(mitems, items, prefixedName(src.Name, item))
+			refs["#/components/"+section+"/"+internal.EscapeJSONPointer(item)] =
+				"#/components/" + section + "/" + internal.EscapeJSONPointer(renamed)
+			added[section][renamed] = items[item]
+
+			if section == "securitySchemes" {
+				schemes[item] = renamed
+			}
+		}
+	}
+
+	if len(refs) > 0 {
+		internal.RewriteRefs(doc, func(ref string) string {
+			if r, ok := refs[ref]; ok {
+				return r
+			}
+
+			return ref
+		})
+	}
+
+	if len(schemes) > 0 {
+		renameSecurity(doc, schemes)
+	}
+
+	for section, items := range added {
+		mitems, _ := mc[section].(map[string]interface{}) //nolint:errcheck // Created above.
+
+		for item, v := range items {
+			mitems[item] = v
+		}
+	}
+
+	return nil
+}
+
+// uniqueName returns a name that is not used in merged and source components.
+func (m *merger) uniqueName(merged, source map[string]interface{}, name string) string {
+	name = internal.SanitizeComponentName(name)
+	res := name
+
+	for i := 2; merged[res] != nil || source[res] != nil; i++ {
+		res = name + strconv.Itoa(i)
+	}
+
+	return res
+}
+
+// renamePrefix makes a component name prefix of a source name, e.g. "OrdersApi" for "orders-api".
+func renamePrefix(name string) string {
+	res := ""
+
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		res += strings.ToUpper(part[:1]) + part[1:]
+	}
+
+	return res
+}
+
+// prefixedName prefixes component name with source name, e.g. "OrdersApiItem" for "orders-api" and "item".
+func prefixedName(source, item string) string {
+	prefix := renamePrefix(source)
+	if prefix == "" || item == "" {
+		return item
+	}
+
+	return prefix + strings.ToUpper(item[:1]) + item[1:]
+}
+
+// renameSecurity renames security schemes in security requirements of document and operations.
+func renameSecurity(doc map[string]interface{}, schemes map[string]string) {
+	renameRequirements(doc["security"], schemes)
+
+	for _, section := range []string{"paths", "webhooks"} {
+		items, _ := doc[section].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+		for _, pi := range items {
+			renameOperationsSecurity(pi, schemes)
+		}
+	}
+
+	components, _ := doc["components"].(map[string]interface{})      //nolint:errcheck // Nil is valid.
+	pathItems, _ := components["pathItems"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	for _, pi := range pathItems {
+		renameOperationsSecurity(pi, schemes)
+	}
+
+	callbacks, _ := components["callbacks"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	for _, cb := range callbacks {
+		renameCallbacksSecurity(cb, schemes)
+	}
+}
+
+func renameOperationsSecurity(pi interface{}, schemes map[string]string) {
+	eachOperation(pi, func(op map[string]interface{}) {
+		renameRequirements(op["security"], schemes)
+
+		callbacks, _ := op["callbacks"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+		for _, cb := range callbacks {
+			renameCallbacksSecurity(cb, schemes)
+		}
+	})
+}
+
+func renameCallbacksSecurity(cb interface{}, schemes map[string]string) {
+	items, _ := cb.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	for _, pi := range items {
+		renameOperationsSecurity(pi, schemes)
+	}
+}
+
+func renameRequirements(v interface{}, schemes map[string]string) {
+	reqs, _ := v.([]interface{}) //nolint:errcheck // Nil is valid.
+
+	for _, req := range reqs {
+		r, ok := req.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for old, renamed := range schemes {
+			if scopes, ok := r[old]; ok {
+				delete(r, old)
+				r[renamed] = scopes
+			}
+		}
+	}
+}
+
+// sharedSecurity is true if all documents have the same top-level security requirements.
+func sharedSecurity(docs []map[string]interface{}) bool {
+	if len(docs) == 0 {
+		return true
+	}
+
+	for _, doc := range docs[1:] {
+		if !reflect.DeepEqual(doc["security"], docs[0]["security"]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pushSecurity moves top-level security requirements to operations that do not define their own.
+func pushSecurity(doc map[string]interface{}) {
+	sec := doc["security"]
+	delete(doc, "security")
+
+	for _, section := range []string{"paths", "webhooks"} {
+		items, _ := doc[section].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+		for _, pi := range items {
+			eachOperation(pi, func(op map[string]interface{}) {
+				if _, ok := op["security"]; !ok {
+					op["security"] = sec
+				}
+			})
+		}
+	}
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+func eachOperation(pi interface{}, f func(op map[string]interface{})) {
+	item, _ := pi.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	for _, method := range methods {
+		if op, ok := item[method].(map[string]interface{}); ok {
+			f(op)
+		}
+	}
+}
+
+// namespaceTags prefixes tags of document and its operations.
+func namespaceTags(doc map[string]interface{}, prefix string) {
+	if prefix == "" {
+		return
+	}
+
+	tags, _ := doc["tags"].([]interface{}) //nolint:errcheck // Nil is valid.
+	for _, t := range tags {
+		if tag, ok := t.(map[string]interface{}); ok {
+			if name, ok := tag["name"].(string); ok {
+				tag["name"] = prefix + name
+			}
+		}
+	}
+
+	for _, section := range []string{"paths", "webhooks"} {
+		items, _ := doc[section].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+		for _, pi := range items {
+			eachOperation(pi, func(op map[string]interface{}) {
+				opTags, _ := op["tags"].([]interface{}) //nolint:errcheck // Nil is valid.
+				for i, t := range opTags {
+					if name, ok := t.(string); ok {
+						opTags[i] = prefix + name
+					}
+				}
+			})
+		}
+	}
+}
+
+// paths adds path items of a section (paths or webhooks) with a prefix.
+func (m *merger) paths(section, name, prefix string, doc map[string]interface{}) error {
+	items, _ := doc[section].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+	if len(items) == 0 {
+		return nil
+	}
+
+	merged, _ := m.doc[section].(map[string]interface{}) //nolint:errcheck // Nil is replaced.
+	if merged == nil {
+		merged = map[string]interface{}{}
+		m.doc[section] = merged
+	}
+
+	for _, path := range internal.SortedKeys(items) {
+		pi, ok := items[path].(map[string]interface{})
+		key := path
+
+		if !strings.HasPrefix(path, "x-") {
+			key = strings.TrimSuffix(prefix, "/") + path
+		}
+
+		if !ok {
+			merged[key] = items[path]
+
+			continue
+		}
+
+		if err := m.operationIDsOf(name, key, pi); err != nil {
+			return err
+		}
+
+		existing, ok := merged[key].(map[string]interface{})
+		if !ok {
+			merged[key] = pi
+
+			continue
+		}
+
+		for _, k := range internal.SortedKeys(pi) {
+			ev, exists := existing[k]
+
+			switch {
+			case !exists:
+				existing[k] = pi[k]
+			case isMethod(k):
+				return fmt.Errorf("%s: %w: %s %s", name, ErrOperationConflict, strings.ToUpper(k), key)
+			case !reflect.DeepEqual(ev, pi[k]):
+				return fmt.Errorf("%s: %w: %s of %s differs", name, ErrOperationConflict, k, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *merger) operationIDsOf(name, path string, pi map[string]interface{}) error {
+	var err error
+
+	eachOperation(pi, func(op map[string]interface{}) {
+		id, ok := op["operationId"].(string)
+		if !ok || err != nil {
+			return
+		}
+
+		if other, exists := m.operationIDs[id]; exists {
+			err = fmt.Errorf("%s: %w: operationId %q of %s is already used in %s", name, ErrOperationConflict, id, path, other)
+
+			return
+		}
+
+		m.operationIDs[id] = name
+	})
+
+	return err
+}
+
+func isMethod(k string) bool {
+	for _, method := range methods {
+		if k == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// list adds items of a list (e.g. servers) that are not yet in merged list.
+func (m *merger) list(key string, doc map[string]interface{}) {
+	items, _ := doc[key].([]interface{})    //nolint:errcheck // Nil is valid.
+	merged, _ := m.doc[key].([]interface{}) //nolint:errcheck // Nil is valid.
+
+	for _, item := range items {
+		if !contains(merged, item) {
+			merged = append(merged, item)
+		}
+	}
+
+	if len(merged) > 0 {
+		m.doc[key] = merged
+	}
+}
+
+// tags adds tags with new names, description and external docs are filled from later sources if missing.
+func (m *merger) tags(doc map[string]interface{}) {
+	items, _ := doc["tags"].([]interface{})    //nolint:errcheck // Nil is valid.
+	merged, _ := m.doc["tags"].([]interface{}) //nolint:errcheck // Nil is valid.
+
+	for _, item := range items {
+		tag, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		found := false
+
+		for _, mi := range merged {
+			mt, _ := mi.(map[string]interface{}) //nolint:errcheck // Tags are objects.
+			if mt["name"] != tag["name"] {
+				continue
+			}
+
+			found = true
+
+			for k, v := range tag {
+				if _, ok := mt[k]; !ok {
+					mt[k] = v
+				}
+			}
+		}
+
+		if !found {
+			merged = append(merged, tag)
+		}
+	}
+
+	if len(merged) > 0 {
+		m.doc["tags"] = merged
+	}
+}
+
+func contains(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,161 @@
+package merge_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/merge"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func loadSpec(t *testing.T, yaml string) *openapi31.Spec {
+	t.Helper()
+
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(yaml)))
+
+	return &s
+}
+
+const ordersSpec = `
+openapi: 3.1.0
+info: {title: Orders, version: 1.0.0}
+servers: [{url: 'https://api.example.com'}]
+tags: [{name: Orders, description: Order management.}]
+security: [{apiKey: []}]
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      tags: [Orders]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Item'}}
+components:
+  schemas:
+    Item: {type: object, properties: {orderId: {type: string}}}
+    Error: {type: object, properties: {message: {type: string}}}
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-Api-Key}
+`
+
+const catalogSpec = `
+openapi: 3.1.0
+info: {title: Catalog, version: 2.0.0}
+servers: [{url: 'https://api.example.com'}, {url: 'https://catalog.example.com'}]
+tags: [{name: Items}]
+paths:
+  /items:
+    get:
+      operationId: listItems
+      tags: [Items]
+      security: [{apiKey: []}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Item'}}
+        default:
+          description: Error
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Error'}
+components:
+  schemas:
+    Item: {type: object, properties: {sku: {type: string}}}
+    Error: {type: object, properties: {message: {type: string}}}
+  securitySchemes:
+    apiKey: {type: apiKey, in: query, name: key}
+`
+
+func TestMerge(t *testing.T) {
+	s, err := merge.Merge(merge.Options{
+		Info:       &openapi31.Info{Title: "Gateway", Version: "1.0.0"},
+		Collisions: merge.RenameOnCollision,
+	},
+		merge.Source{Name: "orders", Spec: loadSpec(t, ordersSpec), PathPrefix: "/orders-api", TagPrefix: "orders/"},
+		merge.Source{Name: "catalog", Spec: loadSpec(t, catalogSpec), PathPrefix: "/catalog-api/"},
+	)
+	require.NoError(t, err)
+
+	assertjson.EqualMarshal(t, []byte(`{
+	  "openapi":"3.1.0","info":{"title":"Gateway","version":"1.0.0"},
+	  "servers":[{"url":"https://api.example.com"},{"url":"https://catalog.example.com"}],
+	  "tags":[{"name":"orders/Orders","description":"Order management."},{"name":"Items"}],
+	  "paths":{
+		"/catalog-api/items":{
+		  "get":{
+			"tags":["Items"],"operationId":"listItems","security":[{"CatalogApiKey":[]}],
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/json":{"schema":{"items":{"$ref":"#/components/schemas/CatalogItem"},"type":"array"}}
+				}
+			  },
+			  "default":{
+				"description":"Error",
+				"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Error"}}}
+			  }
+			}
+		  }
+		},
+		"/orders-api/orders":{
+		  "get":{
+			"tags":["orders/Orders"],"operationId":"listOrders","security":[{"apiKey":[]}],
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/json":{"schema":{"items":{"$ref":"#/components/schemas/Item"},"type":"array"}}
+				}
+			  }
+			}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "CatalogItem":{"properties":{"sku":{"type":"string"}},"type":"object"},
+		  "Error":{"properties":{"message":{"type":"string"}},"type":"object"},
+		  "Item":{"properties":{"orderId":{"type":"string"}},"type":"object"}
+		},
+		"securitySchemes":{
+		  "CatalogApiKey":{"type":"apiKey","name":"key","in":"query"},
+		  "apiKey":{"type":"apiKey","name":"X-Api-Key","in":"header"}
+		}
+	  }
+	}`), s)
+}
+
+func TestMerge_errors(t *testing.T) {
+	_, err := merge.Merge(merge.Options{},
+		merge.Source{Name: "orders", Spec: loadSpec(t, ordersSpec)},
+		merge.Source{Name: "catalog", Spec: loadSpec(t, catalogSpec)},
+	)
+	assert.True(t, errors.Is(err, merge.ErrComponentCollision))
+	assert.EqualError(t, err, "catalog: component name collision: components/schemas/Item")
+
+	_, err = merge.Merge(merge.Options{},
+		merge.Source{Name: "orders", Spec: loadSpec(t, ordersSpec)},
+		merge.Source{Name: "orders-v2", Spec: loadSpec(t, ordersSpec), PathPrefix: "/v2"},
+	)
+	assert.True(t, errors.Is(err, merge.ErrOperationConflict))
+	assert.EqualError(t, err, `orders-v2: conflicting operation: operationId "listOrders" of /v2/orders is already used in orders`)
+
+	other := loadSpec(t, ordersSpec)
+	other.Paths.MapOfPathItemValues["/orders"].Get.ID = nil
+
+	_, err = merge.Merge(merge.Options{},
+		merge.Source{Name: "orders", Spec: loadSpec(t, ordersSpec)},
+		merge.Source{Name: "copy", Spec: other},
+	)
+	assert.EqualError(t, err, "copy: conflicting operation: GET /orders")
+}
@@ -0,0 +1,459 @@
+// Package mock serves OpenAPI spec as a mock HTTP API.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/examplegen"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/validator"
+)
+
+// Options configures mock handler.
+type Options struct {
+	// Examples configures synthesis of examples for media types and headers without explicit examples.
+	Examples examplegen.Options
+
+	// SkipValidation disables validation of requests against the spec.
+	SkipValidation bool
+}
+
+// New creates a handler that serves responses of spec operations.
+//
+// Requests are routed by path templates of spec and validated with validator package, invalid requests
+// receive problem details (RFC 9457). Response is the first 2XX response of operation, or the default one.
+// Its body is an explicit example of media type negotiated with Accept header (the first of "examples"
+// by name if there is no "example"), or an example synthesized from schema with examplegen package.
+//
+// Client can select response status and named example with Prefer header, e.g. "Prefer: code=404, example=notFound".
+// Status falls back to a range response (e.g. "4XX") and to the default response.
+//
+// Spec must be a *openapi3.Spec or *openapi31.Spec, it is captured at construction.
+func New(s openapi.SpecSchema, options Options) (http.Handler, error) {
+	v, err := validator.New(s)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := internal.ToDocument(v.Spec())
+	if err != nil {
+		return nil, err
+	}
+
+	h := &handler{
+		v:           v,
+		doc:         doc,
+		options:     options,
+		synthesized: map[string]interface{}{},
+	}
+
+	if options.SkipValidation {
+		return h, nil
+	}
+
+	return v.Middleware(h), nil
+}
+
+type handler struct {
+	v       *validator.Validator
+	doc     map[string]interface{}
+	options Options
+
+	mu sync.Mutex
+
+	// synthesized contains examples by JSON Pointers of schemas.
+	synthesized map[string]interface{}
+}
+
+var methods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions, http.MethodTrace,
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	rt, found := h.route(r.Method, r.URL.EscapedPath())
+	if !found {
+		h.notFound(rw, r)
+
+		return
+	}
+
+	prefer := preferences(r.Header)
+
+	resp, ptr, status, err := h.response(rt.Pointer, prefer["code"])
+	if err != nil {
+		problem := http.StatusBadRequest
+		if prefer["code"] == "" {
+			problem = http.StatusNotImplemented
+		}
+
+		writeProblem(rw, r, problem, err.Error())
+
+		return
+	}
+
+	var (
+		ct   string
+		body []byte
+	)
+
+	if content, ok := resp["content"].(map[string]interface{}); ok && len(content) > 0 {
+		if ct, ok = negotiate(content, r.Header.Get("Accept")); !ok {
+			writeProblem(rw, r, http.StatusNotAcceptable, "no response content matches Accept header")
+
+			return
+		}
+
+		mt, _ := content[ct].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+		mtPtr := ptr + "/content/" + internal.EscapeJSONPointer(ct)
+
+		value, found, err := h.example(mtPtr, mt, prefer["example"])
+		if err != nil {
+			writeProblem(rw, r, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		if found {
+			if ct, body, err = encode(ct, value); err != nil {
+				writeProblem(rw, r, http.StatusInternalServerError, err.Error())
+
+				return
+			}
+		}
+	}
+
+	if err := h.headers(rw.Header(), ptr, resp); err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	if applied := appliedPreferences(prefer); applied != "" {
+		rw.Header().Set("Preference-Applied", applied)
+	}
+
+	if body != nil {
+		rw.Header().Set("Content-Type", ct)
+		rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	rw.WriteHeader(status)
+
+	if r.Method != http.MethodHead {
+		_, _ = rw.Write(body) //nolint:errcheck // Nothing to do with write error.
+	}
+}
+
+// route finds an operation, HEAD requests are served by GET operations if there is no HEAD operation.
+func (h *handler) route(method, path string) (validator.Route, bool) {
+	rt, found := h.v.FindRoute(method, path)
+	if !found && method == http.MethodHead {
+		rt, found = h.v.FindRoute(http.MethodGet, path)
+	}
+
+	return rt, found
+}
+
+// notFound responds with 405 Method Not Allowed if path has operations of other methods, or with 404 Not Found.
+func (h *handler) notFound(rw http.ResponseWriter, r *http.Request) {
+	var allowed []string
+
+	for _, m := range methods {
+		if _, found := h.route(m, r.URL.EscapedPath()); found {
+			allowed = append(allowed, m)
+		}
+	}
+
+	if len(allowed) == 0 {
+		writeProblem(rw, r, http.StatusNotFound, "no operation for "+r.Method+" "+r.URL.Path)
+
+		return
+	}
+
+	rw.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeProblem(rw, r, http.StatusMethodNotAllowed, "method "+r.Method+" is not allowed for "+r.URL.Path)
+}
+
+// response finds a response of operation and its pointer, preferred code selects response by status.
+func (h *handler) response(opPtr, code string) (map[string]interface{}, string, int, error) {
+	responses, _ := h.resolve(opPtr + "/responses") //nolint:errcheck // Nil is valid.
+	keys := internal.SortedKeys(responses)
+
+	var (
+		candidates []string
+		status     int
+	)
+
+	if code != "" {
+		c, err := strconv.Atoi(code)
+		if err != nil || c < 100 || c > 599 {
+			return nil, "", 0, fmt.Errorf("invalid preferred code %q", code)
+		}
+
+		status = c
+		candidates = []string{code, code[:1] + "XX", "default"}
+	} else {
+		for _, k := range keys {
+			if strings.HasPrefix(k, "2") {
+				candidates = append(candidates, k)
+			}
+		}
+
+		candidates = append(candidates, "default")
+		candidates = append(candidates, keys...)
+	}
+
+	for _, k := range candidates {
+		if _, ok := responses[k]; !ok {
+			continue
+		}
+
+		ptr := opPtr + "/responses/" + internal.EscapeJSONPointer(k)
+
+		resp, ptr, err := h.follow(ptr)
+		if err != nil {
+			return nil, "", 0, err
+		}
+
+		if status == 0 {
+			if status, err = strconv.Atoi(k); err != nil {
+				status = http.StatusOK
+
+				if strings.HasSuffix(k, "XX") {
+					status, _ = strconv.Atoi(k[:1] + "00") //nolint:errcheck // Valid range of spec.
+				}
+			}
+		}
+
+		return resp, ptr, status, nil
+	}
+
+	if code != "" {
+		return nil, "", 0, fmt.Errorf("response %s is not defined", code)
+	}
+
+	return nil, "", 0, fmt.Errorf("operation %s has no responses", internal.UnescapeJSONPointer(opPtr))
+}
+
+// resolve returns an object by JSON Pointer.
+func (h *handler) resolve(ptr string) (map[string]interface{}, error) {
+	v, err := internal.ResolvePointer(h.doc, "#"+ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	m, _ := v.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	return m, nil
+}
+
+// follow returns an object by JSON Pointer and the pointer of referenced component if it is a reference.
+func (h *handler) follow(ptr string) (map[string]interface{}, string, error) {
+	for i := 0; i < 32; i++ {
+		m, err := h.resolve(ptr)
+		if err != nil {
+			return nil, "", err
+		}
+
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m, ptr, nil
+		}
+
+		if !strings.HasPrefix(ref, "#") {
+			return nil, "", fmt.Errorf("%w: %s", openapi.ErrNonLocalReference, ref)
+		}
+
+		ptr = strings.TrimPrefix(ref, "#")
+	}
+
+	return nil, "", fmt.Errorf("%w: %s", openapi.ErrCircularReference, ptr)
+}
+
+// example returns a value for media type or header object, preferred name selects one of named examples.
+func (h *handler) example(ptr string, obj map[string]interface{}, name string) (interface{}, bool, error) {
+	examples, _ := obj["examples"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	if name != "" {
+		if _, ok := examples[name]; !ok {
+			return nil, false, fmt.Errorf("example %q is not defined", name)
+		}
+
+		ex, _, err := h.follow(ptr + "/examples/" + internal.EscapeJSONPointer(name))
+		if err != nil {
+			return nil, false, err
+		}
+
+		v, ok := ex["value"]
+		if !ok {
+			return nil, false, fmt.Errorf("example %q has no value", name)
+		}
+
+		return v, true, nil
+	}
+
+	if v, ok := obj["example"]; ok {
+		return v, true, nil
+	}
+
+	for _, k := range internal.SortedKeys(examples) {
+		ex, _, err := h.follow(ptr + "/examples/" + internal.EscapeJSONPointer(k))
+		if err != nil {
+			return nil, false, err
+		}
+
+		if v, ok := ex["value"]; ok {
+			return v, true, nil
+		}
+	}
+
+	schema, ok := obj["schema"].(map[string]interface{})
+	if !ok {
+		return nil, false, nil
+	}
+
+	v, err := h.synthesize(ptr+"/schema", schema)
+
+	return v, err == nil, err
+}
+
+// synthesize generates and caches an example of schema.
+func (h *handler) synthesize(ptr string, schema map[string]interface{}) (v interface{}, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if v, ok := h.synthesized[ptr]; ok {
+		return v, nil
+	}
+
+	// ToJSONSchema panics on references that are not found.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("schema %s: %v", ptr, r)
+		}
+	}()
+
+	options := h.options.Examples
+	options.Request = false
+
+	if v, err = examplegen.Generate(openapi31.ToJSONSchema(schema, h.v.Spec()), options); err != nil {
+		return nil, fmt.Errorf("schema %s: %w", ptr, err)
+	}
+
+	h.synthesized[ptr] = v
+
+	return v, nil
+}
+
+// headers sets example values of response headers.
+func (h *handler) headers(header http.Header, ptr string, resp map[string]interface{}) error {
+	headers, _ := resp["headers"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	for _, name := range internal.SortedKeys(headers) {
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+
+		hdr, hptr, err := h.follow(ptr + "/headers/" + internal.EscapeJSONPointer(name))
+		if err != nil {
+			return err
+		}
+
+		v, found, err := h.example(hptr, hdr, "")
+		if err != nil {
+			return err
+		}
+
+		if found && v != nil {
+			header.Set(name, headerValue(v))
+		}
+	}
+
+	return nil
+}
+
+// headerValue formats value in simple style, arrays are comma-separated.
+func headerValue(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []interface{}:
+		items := make([]string, 0, len(t))
+		for _, item := range t {
+			items = append(items, headerValue(item))
+		}
+
+		return strings.Join(items, ",")
+	case map[string]interface{}:
+		items := make([]string, 0, 2*len(t))
+		for _, k := range internal.SortedKeys(t) {
+			items = append(items, k, headerValue(t[k]))
+		}
+
+		return strings.Join(items, ",")
+	default:
+		data, _ := json.Marshal(v) //nolint:errcheck // Scalars are always marshaled.
+
+		return string(data)
+	}
+}
+
+// encode marshals value for content type, strings of non-JSON media types are written as is.
+func encode(ct string, v interface{}) (string, []byte, error) {
+	s, isString := v.(string)
+
+	if strings.Contains(ct, "*") {
+		ct = "application/json"
+		if isString {
+			ct = "text/plain; charset=utf-8"
+		}
+	}
+
+	if isString && !isJSON(ct) {
+		return ct, []byte(s), nil
+	}
+
+	data, err := json.Marshal(v)
+
+	return ct, data, err
+}
+
+func writeProblem(rw http.ResponseWriter, r *http.Request, status int, detail string) {
+	data, err := json.Marshal(validator.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.RequestURI(),
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/problem+json")
+	rw.WriteHeader(status)
+	_, _ = rw.Write(data) //nolint:errcheck // Nothing to do with write error.
+}
+
+// appliedPreferences returns value of Preference-Applied header for used preferences.
+func appliedPreferences(prefer map[string]string) string {
+	var applied []string
+
+	for _, k := range []string{"code", "example"} {
+		if v, ok := prefer[k]; ok {
+			applied = append(applied, k+"="+v)
+		}
+	}
+
+	return strings.Join(applied, ", ")
+}
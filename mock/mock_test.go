@@ -0,0 +1,228 @@
+package mock_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/mock"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+const petstore = `
+openapi: 3.1.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, maximum: 100}}
+      responses:
+        "200":
+          description: Pets.
+          headers:
+            X-Total: {schema: {type: integer, minimum: 1}}
+            X-Next: {$ref: '#/components/headers/Next'}
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Pet'}
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "201":
+          description: Created.
+        default: {$ref: '#/components/responses/Error'}
+  /pets/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        "200":
+          description: Pet.
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+              examples:
+                tom: {value: {id: 2, name: Tom}}
+                rex: {$ref: '#/components/examples/Rex'}
+            text/plain:
+              schema: {type: string}
+              example: Rex.
+        4XX:
+          $ref: '#/components/responses/Error'
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer, readOnly: true}
+        name: {type: string}
+  examples:
+    Rex: {value: {id: 1, name: Rex}}
+  headers:
+    Next: {schema: {type: string}, example: '/pets?page=2'}
+  responses:
+    Error:
+      description: Error.
+      content:
+        application/json:
+          schema:
+            type: object
+            required: [message]
+            properties:
+              message: {type: string}
+          examples:
+            notFound: {value: {message: 'Not found.'}}
+`
+
+func newHandler(t *testing.T) http.Handler {
+	t.Helper()
+
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(petstore)))
+
+	h, err := mock.New(&s, mock.Options{})
+	require.NoError(t, err)
+
+	return h
+}
+
+func serve(h http.Handler, method, target string, header http.Header, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestNew(t *testing.T) {
+	h := newHandler(t)
+
+	// Synthesized body and headers.
+	rec := serve(h, http.MethodGet, "/pets?limit=10", nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "1", rec.Header().Get("X-Total"))
+	assert.Equal(t, "/pets?page=2", rec.Header().Get("X-Next"))
+	assertjson.Equal(t, []byte(`[{"id":0,"name":"string"}]`), rec.Body.Bytes())
+
+	// Explicit examples, first by name.
+	rec = serve(h, http.MethodGet, "/pets/1", nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assertjson.Equal(t, []byte(`{"id":1,"name":"Rex"}`), rec.Body.Bytes())
+
+	rec = serve(h, http.MethodGet, "/pets/1", http.Header{"Prefer": {"example=tom"}}, "")
+	assertjson.Equal(t, []byte(`{"id":2,"name":"Tom"}`), rec.Body.Bytes())
+	assert.Equal(t, "example=tom", rec.Header().Get("Preference-Applied"))
+
+	// Negotiated content.
+	rec = serve(h, http.MethodGet, "/pets/1", http.Header{"Accept": {"text/*, application/json;q=0.5"}}, "")
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "Rex.", rec.Body.String())
+
+	rec = serve(h, http.MethodGet, "/pets/1", http.Header{"Accept": {"image/png"}}, "")
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+
+	// Response selected by status falls back to range.
+	rec = serve(h, http.MethodGet, "/pets/1", http.Header{"Prefer": {"code=404, example=notFound"}}, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assertjson.Equal(t, []byte(`{"message":"Not found."}`), rec.Body.Bytes())
+	assert.Equal(t, "code=404, example=notFound", rec.Header().Get("Preference-Applied"))
+
+	rec = serve(h, http.MethodGet, "/pets/1", http.Header{"Prefer": {"code=500"}}, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assertjson.Equal(t, []byte(`{
+	  "type":"about:blank","title":"Bad Request","status":400,
+	  "detail":"response 500 is not defined","instance":"/pets/1"
+	}`), rec.Body.Bytes())
+
+	rec = serve(h, http.MethodGet, "/pets/1", http.Header{"Prefer": {"example=unknown"}}, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"detail":"example \"unknown\" is not defined"`)
+
+	// Response without content.
+	rec = serve(h, http.MethodPost, "/pets", http.Header{"Content-Type": {"application/json"}}, `{"id":1,"name":"Rex"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	// Default response is served with preferred code.
+	rec = serve(h, http.MethodPost, "/pets", http.Header{"Content-Type": {"application/json"}, "Prefer": {"code=503"}}, `{"id":1,"name":"Rex"}`)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assertjson.Equal(t, []byte(`{"message":"Not found."}`), rec.Body.Bytes())
+
+	// HEAD is served by GET operation.
+	rec = serve(h, http.MethodHead, "/pets/1", nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	assert.Equal(t, "21", rec.Header().Get("Content-Length"))
+}
+
+func TestNew_invalid(t *testing.T) {
+	h := newHandler(t)
+
+	rec := serve(h, http.MethodGet, "/pets?limit=1000", nil, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"name":"limit"`)
+
+	rec = serve(h, http.MethodPost, "/pets", nil, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "missing required request body")
+
+	rec = serve(h, http.MethodDelete, "/pets", nil, "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD, POST", rec.Header().Get("Allow"))
+
+	rec = serve(h, http.MethodGet, "/owners", nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"detail":"no operation for GET /owners"`)
+}
+
+func TestNew_openapi3(t *testing.T) {
+	s := openapi3.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.0.3
+info: {title: Items, version: 1.0.0}
+paths:
+  /items/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string, format: uuid}}
+      responses:
+        "200":
+          description: Item.
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id: {type: string, format: uuid}
+                  price: {type: number, minimum: 0, exclusiveMinimum: true}
+                  secret: {type: string, writeOnly: true}
+`)))
+
+	h, err := mock.New(&s, mock.Options{SkipValidation: true})
+	require.NoError(t, err)
+
+	rec := serve(h, http.MethodGet, "/items/abc", nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assertjson.Equal(t, []byte(`{"id":"123e4567-e89b-12d3-a456-426614174000","price":1}`), rec.Body.Bytes())
+}
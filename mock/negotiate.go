@@ -0,0 +1,114 @@
+package mock
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// preferences parses Prefer headers, e.g. "code=404, example=notFound".
+func preferences(header http.Header) map[string]string {
+	res := map[string]string{}
+
+	for _, h := range header.Values("Prefer") {
+		for _, pref := range strings.FieldsFunc(h, func(r rune) bool { return r == ',' || r == ';' }) {
+			kv := strings.SplitN(strings.TrimSpace(pref), "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+
+			k := strings.ToLower(strings.TrimSpace(kv[0]))
+			if k == "code" || k == "example" {
+				res[k] = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+			}
+		}
+	}
+
+	return res
+}
+
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+// negotiate selects content type by Accept header, JSON media types are preferred for wildcards.
+func negotiate(content map[string]interface{}, accept string) (string, bool) {
+	keys := internal.SortedKeys(content)
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return isJSON(keys[i]) && !isJSON(keys[j])
+	})
+
+	if strings.TrimSpace(accept) == "" {
+		return keys[0], true
+	}
+
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mr := mediaRange{mediaType: mediaType(params[0]), q: 1}
+
+		for _, p := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(p), "=", 2)
+			if len(kv) == 2 && strings.EqualFold(kv[0], "q") {
+				if q, err := strconv.ParseFloat(kv[1], 64); err == nil {
+					mr.q = q
+				}
+			}
+		}
+
+		if mr.mediaType != "" && mr.q > 0 {
+			ranges = append(ranges, mr)
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	for _, mr := range ranges {
+		for _, k := range keys {
+			if matches(mr.mediaType, mediaType(k)) {
+				return k, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// matches checks if media types match, both can be wildcards, e.g. "application/*".
+func matches(a, b string) bool {
+	if a == "*/*" || b == "*/*" || a == b {
+		return true
+	}
+
+	at, as := split(a)
+	bt, bs := split(b)
+
+	return at == bt && (as == "*" || bs == "*")
+}
+
+func split(mt string) (string, string) {
+	parts := strings.SplitN(mt, "/", 2)
+	if len(parts) != 2 {
+		return mt, ""
+	}
+
+	return parts[0], parts[1]
+}
+
+func mediaType(ct string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(ct, ";")[0]))
+}
+
+func isJSON(ct string) bool {
+	ct = mediaType(ct)
+
+	return ct == "application/json" || strings.HasSuffix(ct, "+json")
+}
@@ -0,0 +1,41 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Handle registers handler on mux and adds operation with the same pattern to reflector,
+// so that documented and served routes do not drift apart.
+//
+// Pattern follows Go 1.22 http.ServeMux syntax and must have a method, e.g. "GET /items/{id}".
+// Setup functions configure operation context before it is added, for example with request
+// and response structures. Handler is not registered if operation fails to be added.
+func Handle(
+	mux *http.ServeMux,
+	r Reflector,
+	pattern string,
+	handler http.Handler,
+	setup ...func(oc OperationContext),
+) error {
+	if _, _, ok := splitMethodPattern(pattern); !ok {
+		return fmt.Errorf("method is missing in pattern %q", pattern)
+	}
+
+	oc, err := r.NewOperationContext("", pattern)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range setup {
+		f(oc)
+	}
+
+	if err := r.AddOperation(oc); err != nil {
+		return err
+	}
+
+	mux.Handle(pattern, handler)
+
+	return nil
+}
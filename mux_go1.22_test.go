@@ -0,0 +1,73 @@
+//go:build go1.22
+// +build go1.22
+
+//go:debug httpmuxgo121=0
+
+package openapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestHandle(t *testing.T) {
+	mux := http.NewServeMux()
+	r := openapi31.NewReflector()
+
+	type fileReq struct {
+		Dir  string `path:"dir"`
+		Path string `path:"path"`
+	}
+
+	require.NoError(t, openapi.Handle(mux, r, "GET /files/{dir}/{path...}",
+		http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			_, _ = rw.Write([]byte(r.PathValue("dir") + ":" + r.PathValue("path"))) //nolint:errcheck
+		}),
+		func(oc openapi.OperationContext) {
+			oc.AddReqStructure(fileReq{})
+			oc.AddRespStructure(nil, openapi.WithContentType("text/plain"))
+		},
+	))
+
+	require.NoError(t, openapi.Handle(mux, r, "GET /{$}", http.NotFoundHandler()))
+
+	req := httptest.NewRequest(http.MethodGet, "/files/docs/a/b.txt", nil)
+	rw := httptest.NewRecorder()
+	mux.ServeHTTP(rw, req)
+	assert.Equal(t, "docs:a/b.txt", rw.Body.String())
+
+	assertjson.EqualMarshal(t, []byte(`{
+	  "openapi":"3.1.0","info":{"title":"","version":""},
+	  "paths":{
+		"/":{"get":{"responses":{"204":{"description":"No Content"}}}},
+		"/files/{dir}/{path}":{
+		  "get":{
+			"parameters":[
+			  {"name":"dir","in":"path","required":true,"schema":{"type":"string"}},
+			  {"name":"path","in":"path","required":true,"schema":{"type":"string"}}
+			],
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{"text/plain":{"schema":{"type":"string"}}}
+			  }
+			}
+		  }
+		}
+	  }
+	}`), r.Spec)
+
+	// Operation conflict prevents handler registration.
+	err := openapi.Handle(mux, r, "GET /{$}", http.NotFoundHandler())
+	assert.EqualError(t, err, "operation already exists: GET /")
+
+	assert.EqualError(t, openapi.Handle(mux, r, "/items", http.NotFoundHandler()),
+		`method is missing in pattern "/items"`)
+}
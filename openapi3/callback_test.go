@@ -0,0 +1,94 @@
+package openapi3_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+type Subscription struct {
+	CallbackURL string `json:"callbackUrl" format:"uri"`
+	Topic       string `json:"topic"`
+}
+
+type Notification struct {
+	Signature string `header:"X-Signature"`
+	Topic     string `json:"topic"`
+	Payload   string `json:"payload"`
+}
+
+func TestOperationContext_AddCallback(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/subscriptions")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Subscription{})
+	oc.AddRespStructure(Subscription{}, openapi.WithHTTPStatus(http.StatusCreated))
+
+	cb, err := oc.(openapi.OperationCallbacks).AddCallback("onNotification", "{$request.body#/callbackUrl}", http.MethodPost)
+	require.NoError(t, err)
+
+	cb.SetSummary("Notification delivery")
+	cb.AddReqStructure(Notification{})
+
+	_, err = oc.(openapi.OperationCallbacks).AddCallback("onNotification", "{$request.body#/callbackUrl}", http.MethodPost)
+	require.EqualError(t, err, "callback already exists: onNotification post {$request.body#/callbackUrl}")
+
+	cb, err = oc.(openapi.OperationCallbacks).AddCallback("onNotification", "{$request.body#/callbackUrl}", http.MethodDelete)
+	require.NoError(t, err)
+
+	cb.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusGone))
+
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+		"openapi":"3.0.3","info":{"title":"","version":""},
+		"paths":{
+			"/subscriptions":{
+				"post":{
+					"requestBody":{
+						"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestSubscription"}}}
+					},
+					"responses":{
+						"201":{
+							"description":"Created",
+							"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestSubscription"}}}
+						}
+					},
+					"callbacks":{
+						"onNotification":{
+							"{$request.body#/callbackUrl}":{
+								"post":{
+									"summary":"Notification delivery",
+									"parameters":[{"name":"X-Signature","in":"header","schema":{"type":"string"}}],
+									"requestBody":{
+										"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi3TestNotification"}}}
+									},
+									"responses":{"204":{"description":"No Content"}}
+								},
+								"delete":{"responses":{"410":{"description":"Gone"}}}
+							}
+						}
+					}
+				}
+			}
+		},
+		"components":{
+			"schemas":{
+				"Openapi3TestNotification":{
+					"type":"object",
+					"properties":{"payload":{"type":"string"},"topic":{"type":"string"}}
+				},
+				"Openapi3TestSubscription":{
+					"type":"object",
+					"properties":{"callbackUrl":{"type":"string","format":"uri"},"topic":{"type":"string"}}
+				}
+			}
+		}
+	}`, r.SpecSchema())
+}
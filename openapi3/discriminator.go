@@ -0,0 +1,90 @@
+package openapi3
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/refl"
+)
+
+// AddDiscriminator declares discriminator for a polymorphic type.
+//
+// Base is an instance of the type, use a pointer to declare discriminator for an interface type,
+// for example (*Event)(nil). Alternatively, type can implement openapi.DiscriminatorExposer,
+// such types are supported by reflectors created with NewReflector.
+func (r *Reflector) AddDiscriminator(base interface{}, d openapi.Discriminator) {
+	r.ensureDiscriminators()
+
+	r.discriminators[refl.DeepIndirect(reflect.TypeOf(base))] = d
+}
+
+// ensureDiscriminators enables discriminator support in reflection.
+func (r *Reflector) ensureDiscriminators() {
+	if r.discriminatorsEnabled {
+		return
+	}
+
+	r.discriminatorsEnabled = true
+
+	if r.discriminators == nil {
+		r.discriminators = map[reflect.Type]openapi.Discriminator{}
+	}
+
+	r.DefaultOptions = append(r.DefaultOptions, jsonschema.InterceptSchema(
+		internal.InterceptDiscriminator(r.discriminators, r.reflectDiscriminatorVariant),
+	))
+}
+
+func (r *Reflector) reflectDiscriminatorVariant(variant interface{}, propertyName string, values []string) (string, error) {
+	s, err := r.Reflect(variant,
+		jsonschema.DefinitionsPrefix(componentsSchemas),
+		jsonschema.CollectDefinitions(r.collectDefinition()),
+		jsonschema.RootRef,
+		sanitizeDefName,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if s.Ref == nil || !strings.HasPrefix(*s.Ref, componentsSchemas) {
+		return "", fmt.Errorf("variant must be a named type")
+	}
+
+	cs := r.SpecEns().ComponentsEns().SchemasEns().MapOfSchemaOrRefValues[strings.TrimPrefix(*s.Ref, componentsSchemas)]
+	if cs.Schema == nil {
+		return "", fmt.Errorf("variant must be an object schema")
+	}
+
+	ps, ok := cs.Schema.Properties[propertyName]
+
+	switch {
+	case !ok:
+		ps = SchemaOrRef{Schema: (&Schema{}).WithType(SchemaTypeString)}
+	case ps.Schema == nil:
+		// Referenced schema is kept to preserve its constraints.
+		ps = SchemaOrRef{Schema: (&Schema{}).WithAllOf(ps)}
+	}
+
+	enum := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		enum = append(enum, v)
+	}
+
+	ps.Schema.Enum = enum
+
+	cs.Schema.WithPropertiesItem(propertyName, ps)
+
+	for _, name := range cs.Schema.Required {
+		if name == propertyName {
+			return *s.Ref, nil
+		}
+	}
+
+	cs.Schema.Required = append(cs.Schema.Required, propertyName)
+
+	return *s.Ref, nil
+}
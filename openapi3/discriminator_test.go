@@ -0,0 +1,185 @@
+package openapi3_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+type Pet interface {
+	PetName() string
+}
+
+type Cat struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Lives int    `json:"lives"`
+}
+
+func (c Cat) PetName() string { return c.Name }
+
+type Dog struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Bark bool   `json:"bark"`
+}
+
+func (d Dog) PetName() string { return d.Name }
+
+type Shape struct {
+	Type string `json:"type"`
+}
+
+func (Shape) OpenAPIDiscriminator() openapi.Discriminator {
+	return openapi.Discriminator{
+		PropertyName: "type",
+		Mapping: map[string]interface{}{
+			"circle": Circle{},
+			"square": Square{},
+			"box":    Square{},
+		},
+	}
+}
+
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+type Square struct {
+	Type string  `json:"type" minLength:"1"`
+	Side float64 `json:"side"`
+}
+
+func TestReflector_AddDiscriminator(t *testing.T) {
+	r := openapi3.Reflector{}
+	r.AddDiscriminator((*Pet)(nil), openapi.Discriminator{
+		PropertyName: "kind",
+		Mapping: map[string]interface{}{
+			"cat": Cat{},
+			"dog": new(Dog),
+		},
+	})
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/pets")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(struct {
+		Pet   Pet   `json:"pet"`
+		Shape Shape `json:"shape"`
+	}{})
+	oc.AddRespStructure(new(Shape))
+
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{"schemas":{
+		"Openapi3TestCat":{
+			"required":["kind"],"type":"object",
+			"properties":{"kind":{"enum":["cat"],"type":"string"},"lives":{"type":"integer"},"name":{"type":"string"}}
+		},
+		"Openapi3TestCircle":{
+			"required":["type"],"type":"object",
+			"properties":{"radius":{"type":"number"},"type":{"enum":["circle"],"type":"string"}}
+		},
+		"Openapi3TestDog":{
+			"required":["kind"],"type":"object",
+			"properties":{"bark":{"type":"boolean"},"kind":{"enum":["dog"],"type":"string"},"name":{"type":"string"}}
+		},
+		"Openapi3TestPet":{
+			"discriminator":{
+				"propertyName":"kind",
+				"mapping":{"cat":"#/components/schemas/Openapi3TestCat","dog":"#/components/schemas/Openapi3TestDog"}
+			},
+			"oneOf":[{"$ref":"#/components/schemas/Openapi3TestCat"},{"$ref":"#/components/schemas/Openapi3TestDog"}]
+		},
+		"Openapi3TestShape":{
+			"discriminator":{
+				"propertyName":"type",
+				"mapping":{
+					"box":"#/components/schemas/Openapi3TestSquare",
+					"circle":"#/components/schemas/Openapi3TestCircle",
+					"square":"#/components/schemas/Openapi3TestSquare"
+				}
+			},
+			"oneOf":[{"$ref":"#/components/schemas/Openapi3TestSquare"},{"$ref":"#/components/schemas/Openapi3TestCircle"}],
+			"properties":{"type":{"type":"string"}},"type":"object"
+		},
+		"Openapi3TestSquare":{
+			"required":["type"],"type":"object",
+			"properties":{
+				"side":{"type":"number"},
+				"type":{"enum":["box","square"],"minLength":1,"type":"string"}
+			}
+		}
+	}}`, r.Spec.Components)
+}
+
+type VehicleKind string
+
+func (VehicleKind) Enum() []interface{} {
+	return []interface{}{"car", "bike"}
+}
+
+type Vehicle struct {
+	Kind VehicleKind `json:"kind"`
+}
+
+func (Vehicle) OpenAPIDiscriminator() openapi.Discriminator {
+	return openapi.Discriminator{
+		PropertyName: "kind",
+		Mapping: map[string]interface{}{
+			"car": Car{},
+		},
+	}
+}
+
+type Car struct {
+	Kind  VehicleKind `json:"kind"`
+	Seats int         `json:"seats"`
+}
+
+func TestReflector_Reflect_discriminatorExposer(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	s, err := r.Reflect(Vehicle{}, jsonschema.RootRef)
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+		"$ref":"#/definitions/Openapi3TestVehicle",
+		"definitions":{
+			"Openapi3TestVehicle":{
+				"discriminator":{"propertyName":"kind","mapping":{"car":"#/components/schemas/Openapi3TestCar"}},
+				"oneOf":[{"$ref":"#/components/schemas/Openapi3TestCar"}],
+				"properties":{"kind":{"$ref":"#/definitions/Openapi3TestVehicleKind"}},"type":"object"
+			},
+			"Openapi3TestVehicleKind":{"enum":["car","bike"],"type":"string"}
+		}
+	}`, s)
+
+	assertjson.EqMarshal(t, `{"schemas":{
+		"Openapi3TestCar":{
+			"required":["kind"],"type":"object",
+			"properties":{
+				"kind":{"allOf":[{"$ref":"#/components/schemas/Openapi3TestVehicleKind"}],"enum":["car"]},
+				"seats":{"type":"integer"}
+			}
+		},
+		"Openapi3TestVehicleKind":{"enum":["car","bike"],"type":"string"}
+	}}`, r.Spec.Components)
+}
+
+func TestReflector_AddOperation_noDiscriminators(t *testing.T) {
+	r := openapi3.Reflector{}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/pets")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(Shape{})
+	require.NoError(t, r.AddOperation(oc))
+
+	assert.Empty(t, r.DefaultOptions)
+}
@@ -0,0 +1,58 @@
+package openapi3
+
+import (
+	"strings"
+
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// Filter selects operations for Spec.Filter, operation is kept if it matches all non-empty criteria.
+type Filter struct {
+	// Tags keeps operations that have any of the tags.
+	Tags []string
+
+	// Paths keeps operations of paths that match any of the globs, e.g. "/public/**".
+	// Wildcard "*" matches any characters except "/", "**" matches any characters.
+	Paths []string
+
+	// Operation keeps operations for which it returns true, e.g. to exclude operations with x-internal.
+	// Method is in upper case, e.g. http.MethodGet.
+	Operation func(method, path string, op *Operation) bool
+}
+
+// Filter returns a copy of spec with operations that match the filter.
+//
+// Path items without operations are removed. Components that are no longer referenced
+// (directly or through other components or security requirements) and tags of removed operations
+// are pruned.
+func (s *Spec) Filter(f Filter) (*Spec, error) {
+	doc, err := internal.ToDocument(s)
+	if err != nil {
+		return nil, err
+	}
+
+	of := internal.OperationFilter{Tags: f.Tags, Paths: f.Paths}
+
+	if f.Operation != nil {
+		of.Operation = func(method, path string, v map[string]interface{}) (bool, error) {
+			op := Operation{}
+			if err := internal.FromDocument(v, &op); err != nil {
+				return false, err
+			}
+
+			return f.Operation(strings.ToUpper(method), path, &op), nil
+		}
+	}
+
+	if err := internal.FilterDocument(doc, of); err != nil {
+		return nil, err
+	}
+
+	res := &Spec{}
+
+	if err := internal.FromDocument(doc, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,119 @@
+package openapi3_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+func TestSpec_Filter(t *testing.T) {
+	s := openapi3.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.0.3
+info: {title: Shop, version: 1.0.0}
+tags: [{name: public}, {name: admin}]
+security: [{apiKey: []}]
+paths:
+  /items:
+    get:
+      tags: [public]
+      responses:
+        "200": {$ref: '#/components/responses/items'}
+    delete:
+      tags: [admin]
+      x-internal: true
+      security: [{basic: []}]
+      responses:
+        "204": {description: OK}
+  /users:
+    get:
+      tags: [admin]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+components:
+  responses:
+    items:
+      description: OK
+      content:
+        application/json:
+          schema: {type: array, items: {$ref: '#/components/schemas/Item'}}
+  schemas:
+    Item: {type: object, properties: {owner: {$ref: '#/components/schemas/Owner'}}}
+    Owner: {type: object}
+    User: {type: object}
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-Key}
+    basic: {type: http, scheme: basic}
+`)))
+
+	f, err := s.Filter(openapi3.Filter{
+		Paths: []string{"/it*"},
+		Operation: func(_, _ string, op *openapi3.Operation) bool {
+			return op.MapOfAnything["x-internal"] != true
+		},
+	})
+	require.NoError(t, err)
+
+	assertjson.EqualMarshal(t, []byte(`{
+	  "openapi":"3.0.3","info":{"title":"Shop","version":"1.0.0"},
+	  "security":[{"apiKey":[]}],"tags":[{"name":"public"}],
+	  "paths":{"/items":{"get":{"tags":["public"],"responses":{"200":{"$ref":"#/components/responses/items"}}}}},
+	  "components":{
+		"schemas":{
+		  "Item":{"properties":{"owner":{"$ref":"#/components/schemas/Owner"}},"type":"object"},
+		  "Owner":{"type":"object"}
+		},
+		"responses":{
+		  "items":{
+			"description":"OK",
+			"content":{
+			  "application/json":{"schema":{"items":{"$ref":"#/components/schemas/Item"},"type":"array"}}
+			}
+		  }
+		},
+		"securitySchemes":{"apiKey":{"type":"apiKey","name":"X-Key","in":"header"}}
+	  }
+	}`), f)
+}
+
+func TestSpec_Filter_method(t *testing.T) {
+	s := openapi3.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.0.3
+info: {title: Shop, version: 1.0.0}
+paths:
+  /items:
+    get:
+      responses:
+        "200": {description: OK}
+    delete:
+      responses:
+        "204": {description: OK}
+  /users:
+    get:
+      responses:
+        "200": {description: OK}
+`)))
+
+	f, err := s.Filter(openapi3.Filter{
+		Operation: func(method, _ string, _ *openapi3.Operation) bool {
+			return method == http.MethodGet
+		},
+	})
+	require.NoError(t, err)
+
+	assertjson.EqualMarshal(t, []byte(`{
+	  "openapi":"3.0.3","info":{"title":"Shop","version":"1.0.0"},
+	  "paths":{
+		"/items":{"get":{"responses":{"200":{"description":"OK"}}}},
+		"/users":{"get":{"responses":{"200":{"description":"OK"}}}}
+	  }
+	}`), f)
+}
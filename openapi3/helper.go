@@ -105,14 +105,7 @@ func (s *Spec) AddOperation(method, path string, operation Operation) error {
 		return fmt.Errorf("operation already exists: %s %s", method, path)
 	}
 
-	// Add "No Content" response if there are no responses configured.
-	if len(operation.Responses.MapOfResponseOrRefValues) == 0 && operation.Responses.Default == nil {
-		operation.Responses.WithMapOfResponseOrRefValuesItem(strconv.Itoa(http.StatusNoContent), ResponseOrRef{
-			Response: &Response{
-				Description: http.StatusText(http.StatusNoContent),
-			},
-		})
-	}
+	operation.ensureNoContent()
 
 	return s.SetupOperation(method, path, func(op *Operation) error {
 		*op = operation
@@ -121,6 +114,17 @@ func (s *Spec) AddOperation(method, path string, operation Operation) error {
 	})
 }
 
+// ensureNoContent adds "No Content" response if there are no responses configured.
+func (o *Operation) ensureNoContent() {
+	if len(o.Responses.MapOfResponseOrRefValues) == 0 && o.Responses.Default == nil {
+		o.Responses.WithMapOfResponseOrRefValuesItem(strconv.Itoa(http.StatusNoContent), ResponseOrRef{
+			Response: &Response{
+				Description: http.StatusText(http.StatusNoContent),
+			},
+		})
+	}
+}
+
 // UnknownParamIsForbidden indicates forbidden unknown parameters.
 func (o Operation) UnknownParamIsForbidden(in ParameterIn) bool {
 	f, ok := o.MapOfAnything[xForbidUnknown+string(in)].(bool)
@@ -171,6 +171,15 @@ func (s *SchemaOrRef) toJSONSchema(ctx toJSONSchemaContext) jsonschema.SchemaOrB
 		jso.WithExamples(*ss.Example)
 	}
 
+	if ss.Discriminator != nil {
+		d := map[string]interface{}{"propertyName": ss.Discriminator.PropertyName}
+		if len(ss.Discriminator.Mapping) > 0 {
+			d["mapping"] = ss.Discriminator.Mapping
+		}
+
+		jso.WithExtraPropertiesItem("discriminator", d)
+	}
+
 	for k, v := range ss.MapOfAnything {
 		jso.WithExtraPropertiesItem(k, v)
 	}
@@ -312,6 +321,10 @@ func (s *SchemaOrRef) FromJSONSchema(schema jsonschema.SchemaOrBool) {
 	os.WriteOnly = js.WriteOnly
 	os.UniqueItems = js.UniqueItems
 
+	if d, ok := js.ExtraProperties["discriminator"]; ok {
+		os.Discriminator = discriminator(d)
+	}
+
 	for name, val := range js.ExtraProperties {
 		if strings.HasPrefix(name, "x-") {
 			if os.MapOfAnything == nil {
@@ -325,6 +338,29 @@ func (s *SchemaOrRef) FromJSONSchema(schema jsonschema.SchemaOrBool) {
 	}
 }
 
+func discriminator(v interface{}) *Discriminator {
+	dm, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	d := Discriminator{}
+	d.PropertyName, _ = dm["propertyName"].(string) //nolint:errcheck // Empty is a valid result.
+
+	switch m := dm["mapping"].(type) {
+	case map[string]string:
+		d.Mapping = m
+	case map[string]interface{}:
+		for k, ref := range m {
+			if ref, ok := ref.(string); ok {
+				d.WithMappingItem(k, ref)
+			}
+		}
+	}
+
+	return &d
+}
+
 func checkNullable(t jsonschema.SimpleType, os *Schema) {
 	if t == jsonschema.Null {
 		os.WithNullable(true)
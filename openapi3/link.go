@@ -0,0 +1,71 @@
+package openapi3
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// parseResponseLinks adds links declared with `link` field tags of response structure.
+//
+// Target operation must be the current one or must be already added to spec.
+func (r *Reflector) parseResponseLinks(resp *Response, o *Operation, cu openapi.ContentUnit) error {
+	for _, l := range internal.ResponseLinks(cu.Structure, cu.FieldMapping(openapi.InHeader)) {
+		params, found := r.operationParameters(o, l.OperationID)
+		if !found {
+			return fmt.Errorf("link %s: operation not found", l.OperationID)
+		}
+
+		if !r.hasParameter(params, l.Parameter) {
+			return fmt.Errorf("link %s: parameter %s not found", l.OperationID, l.Parameter)
+		}
+
+		link := resp.Links[l.OperationID]
+		if link.Link == nil {
+			link.Link = (&Link{}).WithOperationID(l.OperationID)
+		}
+
+		link.Link.WithParametersItem(l.Parameter, l.Expression)
+
+		resp.WithLinksItem(l.OperationID, link)
+	}
+
+	return nil
+}
+
+// operationParameters finds parameters of operation by its ID.
+func (r *Reflector) operationParameters(o *Operation, operationID string) ([]ParameterOrRef, bool) {
+	if o.ID != nil && *o.ID == operationID {
+		return o.Parameters, true
+	}
+
+	for _, pi := range r.SpecEns().Paths.MapOfPathItemValues {
+		for _, op := range pi.MapOfOperationValues {
+			if op.ID != nil && *op.ID == operationID {
+				return append(append([]ParameterOrRef{}, pi.Parameters...), op.Parameters...), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// hasParameter checks if parameter exists by name or by qualified name, e.g. "path.id".
+func (r *Reflector) hasParameter(params []ParameterOrRef, name string) bool {
+	for _, pr := range params {
+		p := pr.Parameter
+
+		if pr.ParameterReference != nil && r.Spec.Components != nil && r.Spec.Components.Parameters != nil {
+			ref := r.Spec.Components.Parameters.MapOfParameterOrRefValues[strings.TrimPrefix(pr.ParameterReference.Ref, "#/components/parameters/")]
+			p = ref.Parameter
+		}
+
+		if p != nil && (p.Name == name || string(p.In)+"."+p.Name == name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -20,12 +20,20 @@ type Reflector struct {
 	jsonschema.Reflector
 
 	Spec *Spec
+
+	// ReuseComponents enables deduplication of identical parameters, headers, request bodies and responses
+	// into components, it is applied on every Reflector.AddOperation.
+	ReuseComponents *openapi.ReuseComponents
+
+	discriminators        map[reflect.Type]openapi.Discriminator
+	discriminatorsEnabled bool
 }
 
 // NewReflector creates an instance of OpenAPI 3.0 reflector.
 func NewReflector() *Reflector {
 	r := &Reflector{}
 	r.SpecEns()
+	r.ensureDiscriminators()
 
 	r.DefaultOptions = append(r.DefaultOptions, jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
 		// See https://spec.openapis.org/oas/v3.0.0.html#data-types.
@@ -58,7 +66,7 @@ func (r *Reflector) NewOperationContext(method, pathPattern string) (openapi.Ope
 	operation, found := pathItem.MapOfOperationValues[method]
 
 	if found {
-		return nil, fmt.Errorf("operation already exists: %s %s", method, pathPattern)
+		return nil, fmt.Errorf("operation already exists: %s %s", strings.ToUpper(method), pathPattern)
 	}
 
 	pathParamsMap := make(map[string]bool, len(pathParams))
@@ -282,7 +290,11 @@ func (r *Reflector) AddOperation(oc openapi.OperationContext) error {
 		return fmt.Errorf("setup response %s %s: %w", oc.Method(), oc.PathPattern(), err)
 	}
 
-	return r.SpecEns().AddOperation(oc.Method(), oc.PathPattern(), *c.op)
+	if err := r.setupCallbacks(c.op, oc); err != nil {
+		return fmt.Errorf("setup callbacks %s %s: %w", oc.Method(), oc.PathPattern(), err)
+	}
+
+	return r.addOperation(oc.Method(), oc.PathPattern(), *c.op)
 }
 
 func (r *Reflector) setupRequest(o *Operation, oc openapi.OperationContext) error {
@@ -706,6 +718,10 @@ func (r *Reflector) setupResponse(o *Operation, oc openapi.OperationContext) err
 			}
 		}
 
+		if err := r.parseResponseLinks(resp, o, cu); err != nil {
+			return err
+		}
+
 		if cu.Description != "" {
 			resp.Description = cu.Description
 		}
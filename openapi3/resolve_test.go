@@ -0,0 +1,159 @@
+package openapi3_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+const refSpec = `
+openapi: 3.0.3
+info: {title: Nodes, version: 1.0.0}
+paths:
+  /nodes:
+    get:
+      operationId: listNodes
+      parameters:
+        - $ref: '#/components/parameters/limit'
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Node'}}
+  /items:
+    $ref: '#/paths/~1nodes'
+  /nodes/{id}:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/id'
+      responses:
+        "200":
+          $ref: '#/components/responses/node'
+      callbacks:
+        changed:
+          $ref: '#/components/callbacks/changed'
+components:
+  parameters:
+    limit: {$ref: '#/components/parameters/pageSize'}
+    pageSize: {name: limit, in: query, schema: {type: integer}}
+    id: {name: id, in: path, required: true, schema: {type: string}}
+  responses:
+    node:
+      description: Node.
+      headers:
+        X-Rate-Limit: {$ref: '#/components/headers/rateLimit'}
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Node'}
+          examples:
+            leaf: {$ref: '#/components/examples/leaf'}
+      links:
+        children: {$ref: '#/components/links/children'}
+  headers:
+    rateLimit: {schema: {type: integer}}
+  examples:
+    leaf: {value: {id: "1", children: []}}
+  links:
+    children: {operationId: listNodes}
+  callbacks:
+    changed:
+      '{$request.query.url}':
+        post:
+          requestBody:
+            content:
+              application/json:
+                schema: {$ref: '#/components/schemas/Node'}
+          responses:
+            "204": {description: OK}
+  schemas:
+    Node:
+      type: object
+      properties:
+        id: {type: string}
+        default: {$ref: '#/components/schemas/Leaf'}
+        children: {type: array, items: {$ref: '#/components/schemas/Node'}}
+    Leaf:
+      type: object
+      properties:
+        id: {type: string}
+      default: {$ref: not a reference}
+`
+
+func TestRefResolver_Resolve(t *testing.T) {
+	s := openapi3.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(refSpec)))
+
+	r, err := openapi3.NewRefResolver(&s)
+	require.NoError(t, err)
+
+	var p openapi3.Parameter
+
+	require.NoError(t, r.Resolve("#/components/parameters/limit", &p))
+	assert.Equal(t, "limit", p.Name)
+	assert.Equal(t, openapi3.ParameterInQuery, p.In)
+
+	var pi openapi3.PathItem
+
+	require.NoError(t, r.Resolve("#/paths/~1items", &pi))
+	assert.Equal(t, "listNodes", *pi.MapOfOperationValues["get"].ID)
+
+	var e openapi3.Example
+
+	require.NoError(t, r.Resolve("#/components/responses/node/content/application~1json/examples/leaf", &e))
+	assert.Equal(t, map[string]interface{}{"id": "1", "children": []interface{}{}}, *e.Value)
+
+	var l openapi3.Link
+
+	require.NoError(t, r.Resolve("#/components/responses/node/links/children", &l))
+	assert.Equal(t, "listNodes", *l.OperationID)
+
+	err = r.Resolve("#/components/parameters/missing", &p)
+	assert.EqualError(t, err, "reference not found: #/components/parameters/missing")
+	assert.True(t, errors.Is(err, openapi.ErrReferenceNotFound))
+
+	err = r.Resolve("other.yaml#/components/parameters/limit", &p)
+	assert.EqualError(t, err, "non-local reference: other.yaml#/components/parameters/limit")
+	assert.True(t, errors.Is(err, openapi.ErrNonLocalReference))
+}
+
+func TestSpec_Dereference(t *testing.T) {
+	s := openapi3.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(refSpec)))
+
+	d, err := s.Dereference()
+	require.NoError(t, err)
+
+	// Source spec is not changed.
+	assert.NotNil(t, s.Paths.MapOfPathItemValues["/items"].Ref)
+
+	assert.Nil(t, d.Paths.MapOfPathItemValues["/items"].Ref)
+	assert.Equal(t, "listNodes", *d.Paths.MapOfPathItemValues["/items"].MapOfOperationValues["get"].ID)
+
+	op := d.Paths.MapOfPathItemValues["/nodes/{id}"].MapOfOperationValues["get"]
+	require.NotNil(t, op.Parameters[0].Parameter)
+	assert.Equal(t, "id", op.Parameters[0].Parameter.Name)
+
+	resp := op.Responses.MapOfResponseOrRefValues["200"].Response
+	require.NotNil(t, resp)
+	assert.Equal(t, "Node.", resp.Description)
+	assert.NotNil(t, resp.Headers["X-Rate-Limit"].Header)
+	assert.NotNil(t, resp.Links["children"].Link)
+	assert.NotNil(t, resp.Content["application/json"].Examples["leaf"].Example)
+	assert.NotNil(t, op.Callbacks["changed"].Callback)
+
+	// Recursive schema is left as reference, literal values are not dereferenced.
+	assertjson.EqualMarshal(t, []byte(`{
+	  "type":"object",
+	  "properties":{
+		"children":{"type":"array","items":{"$ref":"#/components/schemas/Node"}},
+		"default":{"type":"object","properties":{"id":{"type":"string"}},"default":{"$ref":"not a reference"}},
+		"id":{"type":"string"}
+	  }
+	}`), resp.Content["application/json"].Schema)
+}
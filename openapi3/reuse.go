@@ -0,0 +1,310 @@
+package openapi3
+
+import (
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// reuseComponents moves duplicated parameters, headers, request bodies and responses to components.
+//
+// With dryRun entities are only collected, so that errors are found before spec is changed.
+func (r *Reflector) reuseComponents(dryRun bool) error {
+	if r.ReuseComponents == nil {
+		return nil
+	}
+
+	rc := *r.ReuseComponents
+
+	for _, reuse := range []struct {
+		kind openapi.ComponentKind
+		fn   func(rc openapi.ReuseComponents, dryRun bool) error
+	}{
+		{kind: openapi.ComponentParameters, fn: r.reuseParameters},
+		{kind: openapi.ComponentHeaders, fn: r.reuseHeaders},
+		{kind: openapi.ComponentRequestBodies, fn: r.reuseRequestBodies},
+		{kind: openapi.ComponentResponses, fn: r.reuseResponses},
+	} {
+		if !rc.IsEnabled(reuse.kind) {
+			continue
+		}
+
+		if err := reuse.fn(rc, dryRun); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addOperation adds operation to spec and moves duplicated entities to components.
+//
+// Added operation is removed if components reuse fails, so that spec is not left half-mutated.
+func (r *Reflector) addOperation(method, path string, op Operation) error {
+	s := r.SpecEns()
+
+	if r.ReuseComponents == nil {
+		return s.AddOperation(method, path, op)
+	}
+
+	cleanMethod, cleanPath, _, err := openapi.SanitizeMethodPath(method, path)
+	if err != nil {
+		return err
+	}
+
+	pathItem, found := s.Paths.MapOfPathItemValues[cleanPath]
+
+	if err := s.AddOperation(method, path, op); err != nil {
+		return err
+	}
+
+	if err := r.reuseComponents(true); err != nil {
+		if found {
+			delete(pathItem.MapOfOperationValues, cleanMethod)
+			s.Paths.MapOfPathItemValues[cleanPath] = pathItem
+		} else {
+			delete(s.Paths.MapOfPathItemValues, cleanPath)
+		}
+
+		return err
+	}
+
+	return r.reuseComponents(false)
+}
+
+func (r *Reflector) reuseParameters(rc openapi.ReuseComponents, dryRun bool) error {
+	existing := map[string]interface{}{}
+
+	if cp := r.components().Parameters; cp != nil {
+		for name, p := range cp.MapOfParameterOrRefValues {
+			existing[name] = p.Parameter
+		}
+	}
+
+	reuse, err := internal.NewComponentsReuse(openapi.ComponentParameters, rc, existing)
+	if err != nil {
+		return err
+	}
+
+	add := func(params []ParameterOrRef) error {
+		for i, p := range params {
+			if p.Parameter == nil {
+				continue
+			}
+
+			i := i
+			if err := reuse.Add(p.Parameter.Name, p.Parameter, func(ref string) {
+				params[i] = ParameterOrRef{ParameterReference: &ParameterReference{Ref: ref}}
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	paths := r.SpecEns().Paths.MapOfPathItemValues
+	for _, path := range internal.SortedMapKeys(paths) {
+		if err := add(paths[path].Parameters); err != nil {
+			return err
+		}
+	}
+
+	if err := r.walkOperations(func(op Operation) error {
+		return add(op.Parameters)
+	}); err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	reuse.Apply(func(name string, entity interface{}) {
+		r.SpecEns().ComponentsEns().ParametersEns().WithMapOfParameterOrRefValuesItem(name, ParameterOrRef{Parameter: entity.(*Parameter)})
+	})
+
+	return nil
+}
+
+func (r *Reflector) reuseHeaders(rc openapi.ReuseComponents, dryRun bool) error {
+	existing := map[string]interface{}{}
+
+	if ch := r.components().Headers; ch != nil {
+		for name, h := range ch.MapOfHeaderOrRefValues {
+			existing[name] = h.Header
+		}
+	}
+
+	reuse, err := internal.NewComponentsReuse(openapi.ComponentHeaders, rc, existing)
+	if err != nil {
+		return err
+	}
+
+	if err := r.walkOperations(func(op Operation) error {
+		return walkResponses(op, func(_ string, resp *Response) error {
+			for _, name := range internal.SortedMapKeys(resp.Headers) {
+				h := resp.Headers[name]
+				if h.Header == nil {
+					continue
+				}
+
+				headers, name := resp.Headers, name
+				if err := reuse.Add(name, h.Header, func(ref string) {
+					headers[name] = HeaderOrRef{HeaderReference: &HeaderReference{Ref: ref}}
+				}); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	reuse.Apply(func(name string, entity interface{}) {
+		r.SpecEns().ComponentsEns().HeadersEns().WithMapOfHeaderOrRefValuesItem(name, HeaderOrRef{Header: entity.(*Header)})
+	})
+
+	return nil
+}
+
+func (r *Reflector) reuseRequestBodies(rc openapi.ReuseComponents, dryRun bool) error {
+	existing := map[string]interface{}{}
+
+	if cr := r.components().RequestBodies; cr != nil {
+		for name, rb := range cr.MapOfRequestBodyOrRefValues {
+			existing[name] = rb.RequestBody
+		}
+	}
+
+	reuse, err := internal.NewComponentsReuse(openapi.ComponentRequestBodies, rc, existing)
+	if err != nil {
+		return err
+	}
+
+	if err := r.walkOperations(func(op Operation) error {
+		rb := op.RequestBody
+		if rb == nil || rb.RequestBody == nil {
+			return nil
+		}
+
+		var refs []string
+
+		for _, ct := range internal.SortedMapKeys(rb.RequestBody.Content) {
+			if s := rb.RequestBody.Content[ct].Schema; s != nil && s.SchemaReference != nil {
+				refs = append(refs, s.SchemaReference.Ref)
+			}
+		}
+
+		return reuse.Add(internal.RequestBodyComponentName(refs...), rb.RequestBody, rb.SetReference)
+	}); err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	reuse.Apply(func(name string, entity interface{}) {
+		r.SpecEns().ComponentsEns().RequestBodiesEns().WithMapOfRequestBodyOrRefValuesItem(name, RequestBodyOrRef{RequestBody: entity.(*RequestBody)})
+	})
+
+	return nil
+}
+
+func (r *Reflector) reuseResponses(rc openapi.ReuseComponents, dryRun bool) error {
+	existing := map[string]interface{}{}
+
+	if cr := r.components().Responses; cr != nil {
+		for name, resp := range cr.MapOfResponseOrRefValues {
+			existing[name] = resp.Response
+		}
+	}
+
+	reuse, err := internal.NewComponentsReuse(openapi.ComponentResponses, rc, existing)
+	if err != nil {
+		return err
+	}
+
+	if err := r.walkOperations(func(op Operation) error {
+		for _, status := range internal.SortedMapKeys(op.Responses.MapOfResponseOrRefValues) {
+			resp := op.Responses.MapOfResponseOrRefValues[status]
+			if resp.Response == nil {
+				continue
+			}
+
+			responses, status := op.Responses.MapOfResponseOrRefValues, status
+			if err := reuse.Add(internal.ResponseComponentName(status), resp.Response, func(ref string) {
+				responses[status] = ResponseOrRef{ResponseReference: &ResponseReference{Ref: ref}}
+			}); err != nil {
+				return err
+			}
+		}
+
+		if d := op.Responses.Default; d != nil && d.Response != nil {
+			return reuse.Add(internal.ResponseComponentName("default"), d.Response, d.SetReference)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	reuse.Apply(func(name string, entity interface{}) {
+		r.SpecEns().ComponentsEns().ResponsesEns().WithMapOfResponseOrRefValuesItem(name, ResponseOrRef{Response: entity.(*Response)})
+	})
+
+	return nil
+}
+
+// components returns spec components without creating them.
+func (r *Reflector) components() Components {
+	if c := r.SpecEns().Components; c != nil {
+		return *c
+	}
+
+	return Components{}
+}
+
+// walkOperations calls f for every operation in a deterministic order.
+func (r *Reflector) walkOperations(f func(op Operation) error) error {
+	paths := r.SpecEns().Paths.MapOfPathItemValues
+
+	for _, path := range internal.SortedMapKeys(paths) {
+		pi := paths[path]
+
+		for _, method := range internal.SortedMapKeys(pi.MapOfOperationValues) {
+			if err := f(pi.MapOfOperationValues[method]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkResponses calls f for every inline response of operation in a deterministic order.
+func walkResponses(op Operation, f func(status string, resp *Response) error) error {
+	for _, status := range internal.SortedMapKeys(op.Responses.MapOfResponseOrRefValues) {
+		if resp := op.Responses.MapOfResponseOrRefValues[status].Response; resp != nil {
+			if err := f(status, resp); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d := op.Responses.Default; d != nil && d.Response != nil {
+		return f("default", d.Response)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package openapi3
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/resources/schema"
+)
+
+// Validate checks document against OpenAPI 3.0 meta-schema and semantic rules.
+//
+// Semantic rules require unique operation IDs, resolvable local references, path parameters
+// matching path templates and security requirements referencing defined security schemes.
+// Returned error is *openapi.SpecError with all found problems.
+func (s *Spec) Validate() error {
+	return internal.ValidateSpec(s, "https://spec.openapis.org/oas/3.0/schema/2019-04-02", schema.OpenAPI3, jsonschema.Draft4)
+}
@@ -0,0 +1,66 @@
+package openapi31
+
+import (
+	"fmt"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+var _ openapi.OperationCallbacks = operationContext{}
+
+// AddCallback creates callback operation context, it is reflected with Reflector.AddOperation of parent operation.
+func (o operationContext) AddCallback(name, expression, method string) (openapi.OperationContext, error) {
+	method, _, _, err := openapi.SanitizeMethodPath(method, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	cb := operationContext{
+		OperationContext: internal.NewOperationContext(method, expression),
+		op:               &Operation{},
+	}
+
+	if err := o.AppendCallback(openapi.Callback{Name: name, Expression: expression, Operation: cb}); err != nil {
+		return nil, err
+	}
+
+	return cb, nil
+}
+
+func (r *Reflector) setupCallbacks(o *Operation, oc openapi.OperationContext) error {
+	ocb, ok := oc.(openapi.OperationCallbacks)
+	if !ok {
+		return nil
+	}
+
+	for _, cb := range ocb.Callbacks() {
+		c, ok := cb.Operation.(operationContext)
+		if !ok {
+			return fmt.Errorf("wrong callback operation context %T received, %T expected", cb.Operation, operationContext{})
+		}
+
+		if err := r.setupRequest(c.op, c); err != nil {
+			return fmt.Errorf("setup callback %s request %s %s: %w", cb.Name, c.Method(), cb.Expression, err)
+		}
+
+		if err := r.setupResponse(c.op, c); err != nil {
+			return fmt.Errorf("setup callback %s response %s %s: %w", cb.Name, c.Method(), cb.Expression, err)
+		}
+
+		c.op.ensureNoContent()
+
+		callback := o.Callbacks[cb.Name]
+		pathItem := callback.CallbacksEns().AdditionalProperties[cb.Expression]
+
+		if err := pathItem.PathItemEns().SetOperation(c.Method(), c.op); err != nil {
+			return err
+		}
+
+		callback.Callbacks.WithAdditionalPropertiesItem(cb.Expression, pathItem)
+
+		o.WithCallbacksItem(cb.Name, callback)
+	}
+
+	return nil
+}
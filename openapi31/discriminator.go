@@ -0,0 +1,98 @@
+package openapi31
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/refl"
+)
+
+// AddDiscriminator declares discriminator for a polymorphic type.
+//
+// Base is an instance of the type, use a pointer to declare discriminator for an interface type,
+// for example (*Event)(nil). Alternatively, type can implement openapi.DiscriminatorExposer,
+// such types are supported by reflectors created with NewReflector.
+func (r *Reflector) AddDiscriminator(base interface{}, d openapi.Discriminator) {
+	r.ensureDiscriminators()
+
+	r.discriminators[refl.DeepIndirect(reflect.TypeOf(base))] = d
+}
+
+// ensureDiscriminators enables discriminator support in reflection.
+func (r *Reflector) ensureDiscriminators() {
+	if r.discriminatorsEnabled {
+		return
+	}
+
+	r.discriminatorsEnabled = true
+
+	if r.discriminators == nil {
+		r.discriminators = map[reflect.Type]openapi.Discriminator{}
+	}
+
+	r.DefaultOptions = append(r.DefaultOptions, jsonschema.InterceptSchema(
+		internal.InterceptDiscriminator(r.discriminators, r.reflectDiscriminatorVariant),
+	))
+}
+
+func (r *Reflector) reflectDiscriminatorVariant(variant interface{}, propertyName string, values []string) (string, error) {
+	s, err := r.Reflect(variant,
+		jsonschema.DefinitionsPrefix(componentsSchemas),
+		jsonschema.CollectDefinitions(r.collectDefinition()),
+		jsonschema.RootRef,
+		sanitizeDefName,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if s.Ref == nil || !strings.HasPrefix(*s.Ref, componentsSchemas) {
+		return "", fmt.Errorf("variant must be a named type")
+	}
+
+	cs := r.SpecEns().ComponentsEns().Schemas[strings.TrimPrefix(*s.Ref, componentsSchemas)]
+
+	props, ok := cs["properties"].(map[string]interface{})
+	if !ok {
+		props = map[string]interface{}{}
+		cs["properties"] = props
+	}
+
+	ps, ok := props[propertyName].(map[string]interface{})
+
+	switch {
+	case !ok:
+		ps = map[string]interface{}{"type": "string"}
+	case ps["$ref"] != nil:
+		// Referenced schema is kept to preserve its constraints.
+		ps = map[string]interface{}{"allOf": []interface{}{ps}}
+	}
+
+	if len(values) == 1 {
+		ps["const"] = values[0]
+	} else {
+		enum := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			enum = append(enum, v)
+		}
+
+		ps["enum"] = enum
+	}
+
+	props[propertyName] = ps
+
+	required, _ := cs["required"].([]interface{}) //nolint:errcheck // Nil is a valid result.
+	for _, name := range required {
+		if name == propertyName {
+			return *s.Ref, nil
+		}
+	}
+
+	cs["required"] = append(required, propertyName)
+
+	return *s.Ref, nil
+}
@@ -1884,7 +1884,7 @@ var knownKeysExample = []string{
 func (e *Example) UnmarshalJSON(data []byte) error {
 	var err error
 
-	var not interface{}
+	var not ExampleNot
 
 	if json.Unmarshal(data, &not) == nil {
 		return errors.New("not constraint failed for Example")
@@ -1940,6 +1940,16 @@ func (e *Example) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	if len(rawMap) != 0 {
+		offendingKeys := make([]string, 0, len(rawMap))
+
+		for key := range rawMap {
+			offendingKeys = append(offendingKeys, key)
+		}
+
+		return fmt.Errorf("additional properties not allowed in Example: %v", offendingKeys)
+	}
+
 	*e = Example(me)
 
 	return nil
@@ -1950,6 +1960,62 @@ func (e Example) MarshalJSON() ([]byte, error) {
 	return marshalUnion(marshalExample(e), e.MapOfAnything)
 }
 
+// ExampleNot structure is generated from "#/$defs/example->not".
+//
+// Value and External Value are mutually exclusive.
+type ExampleNot struct {
+	Value         interface{} `json:"value"`         // Required.
+	ExternalValue interface{} `json:"externalValue"` // Required.
+}
+
+// WithValue sets Value value.
+func (e *ExampleNot) WithValue(val interface{}) *ExampleNot {
+	e.Value = val
+	return e
+}
+
+// WithExternalValue sets ExternalValue value.
+func (e *ExampleNot) WithExternalValue(val interface{}) *ExampleNot {
+	e.ExternalValue = val
+	return e
+}
+
+type marshalExampleNot ExampleNot
+
+var requireKeysExampleNot = []string{
+	"value",
+	"externalValue",
+}
+
+// UnmarshalJSON decodes JSON.
+func (e *ExampleNot) UnmarshalJSON(data []byte) error {
+	var err error
+
+	me := marshalExampleNot(*e)
+
+	err = json.Unmarshal(data, &me)
+	if err != nil {
+		return err
+	}
+
+	var rawMap map[string]json.RawMessage
+
+	err = json.Unmarshal(data, &rawMap)
+	if err != nil {
+		rawMap = nil
+	}
+
+	for _, key := range requireKeysExampleNot {
+		if _, found := rawMap[key]; !found {
+			return errors.New("required key missing: " + key)
+		}
+	}
+
+	*e = ExampleNot(me)
+
+	return nil
+}
+
 // ExampleOrReference structure is generated from "#/$defs/example-or-reference".
 type ExampleOrReference struct {
 	Reference *Reference `json:"-"`
@@ -5526,6 +5592,9 @@ type ParameterStyle string
 
 // ParameterStyle values enumeration.
 const (
+	ParameterStyleMatrix         = ParameterStyle("matrix")
+	ParameterStyleLabel          = ParameterStyle("label")
+	ParameterStyleSimple         = ParameterStyle("simple")
 	ParameterStyleForm           = ParameterStyle("form")
 	ParameterStyleSpaceDelimited = ParameterStyle("spaceDelimited")
 	ParameterStylePipeDelimited  = ParameterStyle("pipeDelimited")
@@ -5535,6 +5604,9 @@ const (
 // MarshalJSON encodes JSON.
 func (i ParameterStyle) MarshalJSON() ([]byte, error) {
 	switch i {
+	case ParameterStyleMatrix:
+	case ParameterStyleLabel:
+	case ParameterStyleSimple:
 	case ParameterStyleForm:
 	case ParameterStyleSpaceDelimited:
 	case ParameterStylePipeDelimited:
@@ -5559,6 +5631,9 @@ func (i *ParameterStyle) UnmarshalJSON(data []byte) error {
 	v := ParameterStyle(ii)
 
 	switch v {
+	case ParameterStyleMatrix:
+	case ParameterStyleLabel:
+	case ParameterStyleSimple:
 	case ParameterStyleForm:
 	case ParameterStyleSpaceDelimited:
 	case ParameterStylePipeDelimited:
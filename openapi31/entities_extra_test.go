@@ -93,3 +93,36 @@ paths:
 
 	require.NoError(t, s.UnmarshalYAML([]byte(spec)))
 }
+
+func TestExample_UnmarshalJSON(t *testing.T) {
+	var e openapi31.Example
+
+	require.NoError(t, e.UnmarshalJSON([]byte(`{"summary":"Foo","value":{"name":"Rex"}}`)))
+	require.NotNil(t, e.Value)
+	require.Equal(t, map[string]interface{}{"name": "Rex"}, *e.Value)
+
+	e = openapi31.Example{}
+	require.NoError(t, e.UnmarshalJSON([]byte(`{"externalValue":"https://example.com/pet.json"}`)))
+	require.Equal(t, "https://example.com/pet.json", *e.ExternalValue)
+
+	e = openapi31.Example{}
+	require.EqualError(t, e.UnmarshalJSON([]byte(`{"value":1,"externalValue":"https://example.com/pet.json"}`)),
+		"not constraint failed for Example")
+
+	e = openapi31.Example{}
+	require.EqualError(t, e.UnmarshalJSON([]byte(`{"$ref":"#/components/examples/foo"}`)),
+		"additional properties not allowed in Example: [$ref]")
+}
+
+func TestExampleOrReference_UnmarshalJSON(t *testing.T) {
+	var e openapi31.ExampleOrReference
+
+	require.NoError(t, e.UnmarshalJSON([]byte(`{"$ref":"#/components/examples/foo"}`)))
+	require.NotNil(t, e.Reference)
+	require.Nil(t, e.Example)
+
+	e = openapi31.ExampleOrReference{}
+	require.NoError(t, e.UnmarshalJSON([]byte(`{"value":"foo"}`)))
+	require.Nil(t, e.Reference)
+	require.NotNil(t, e.Example)
+}
@@ -0,0 +1,178 @@
+package openapi31_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+const filterSpec = `
+openapi: 3.1.0
+info: {title: Shop, version: 1.0.0}
+tags: [{name: public}, {name: admin}, {name: unused}]
+paths:
+  /public/items:
+    get:
+      tags: [public]
+      security: [{apiKey: []}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Item'}}
+    post:
+      tags: [public]
+      x-internal: true
+      requestBody: {$ref: '#/components/requestBodies/newItem'}
+      responses:
+        "204": {description: OK}
+  /public/items/{id}:
+    get:
+      tags: [public]
+      parameters: [{$ref: '#/components/parameters/id'}]
+      responses:
+        "200": {$ref: '#/components/responses/item'}
+  /admin/users:
+    get:
+      tags: [admin]
+      security: [{basic: []}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+webhooks:
+  itemChanged:
+    post:
+      tags: [admin]
+      responses:
+        "204": {description: OK}
+components:
+  parameters:
+    id: {name: id, in: path, required: true, schema: {type: string}}
+  requestBodies:
+    newItem:
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/NewItem'}
+  responses:
+    item:
+      description: OK
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Item'}
+  schemas:
+    Item: {type: object, properties: {price: {$ref: '#/components/schemas/Price'}}}
+    Price: {type: number}
+    NewItem: {type: object}
+    User: {type: object}
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-Key}
+    basic: {type: http, scheme: basic}
+`
+
+func TestSpec_Filter(t *testing.T) {
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(filterSpec)))
+
+	f, err := s.Filter(openapi31.Filter{
+		Tags:  []string{"public"},
+		Paths: []string{"/public/**"},
+		Operation: func(_, _ string, op *openapi31.Operation) bool {
+			return op.MapOfAnything["x-internal"] != true
+		},
+	})
+	require.NoError(t, err)
+
+	// Source spec is not changed.
+	require.NotNil(t, s.Paths.MapOfPathItemValues["/admin/users"].Get)
+
+	assertjson.EqualMarshal(t, []byte(`{
+	  "openapi":"3.1.0","info":{"title":"Shop","version":"1.0.0"},
+	  "tags":[{"name":"public"},{"name":"unused"}],
+	  "paths":{
+		"/public/items":{
+		  "get":{
+			"tags":["public"],"security":[{"apiKey":[]}],
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{
+				  "application/json":{"schema":{"items":{"$ref":"#/components/schemas/Item"},"type":"array"}}
+				}
+			  }
+			}
+		  }
+		},
+		"/public/items/{id}":{
+		  "get":{
+			"tags":["public"],"parameters":[{"$ref":"#/components/parameters/id"}],
+			"responses":{"200":{"$ref":"#/components/responses/item"}}
+		  }
+		}
+	  },
+	  "components":{
+		"schemas":{
+		  "Item":{"properties":{"price":{"$ref":"#/components/schemas/Price"}},"type":"object"},
+		  "Price":{"type":"number"}
+		},
+		"responses":{
+		  "item":{
+			"description":"OK",
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Item"}}}
+		  }
+		},
+		"parameters":{"id":{"name":"id","in":"path","required":true,"schema":{"type":"string"}}},
+		"securitySchemes":{"apiKey":{"type":"apiKey","name":"X-Key","in":"header"}}
+	  }
+	}`), f)
+
+	f, err = s.Filter(openapi31.Filter{Tags: []string{"admin"}})
+	require.NoError(t, err)
+
+	assertjson.EqualMarshal(t, []byte(`{
+	  "openapi":"3.1.0","info":{"title":"Shop","version":"1.0.0"},
+	  "tags":[{"name":"admin"},{"name":"unused"}],
+	  "paths":{
+		"/admin/users":{
+		  "get":{
+			"tags":["admin"],"security":[{"basic":[]}],
+			"responses":{
+			  "200":{
+				"description":"OK",
+				"content":{"application/json":{"schema":{"$ref":"#/components/schemas/User"}}}
+			  }
+			}
+		  }
+		}
+	  },
+	  "webhooks":{"itemChanged":{"post":{"tags":["admin"],"responses":{"204":{"description":"OK"}}}}},
+	  "components":{
+		"schemas":{"User":{"type":"object"}},
+		"securitySchemes":{"basic":{"type":"http","scheme":"basic"}}
+	  }
+	}`), f)
+}
+
+func TestSpec_Filter_method(t *testing.T) {
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(filterSpec)))
+
+	f, err := s.Filter(openapi31.Filter{
+		Operation: func(method, _ string, _ *openapi31.Operation) bool {
+			return method == http.MethodPost
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, f.Paths.MapOfPathItemValues, 1)
+	assert.NotNil(t, f.Paths.MapOfPathItemValues["/public/items"].Post)
+	assert.Nil(t, f.Paths.MapOfPathItemValues["/public/items"].Get)
+	assert.NotNil(t, f.Webhooks["itemChanged"].PathItem.Post)
+}
@@ -166,14 +166,7 @@ func (s *Spec) AddOperation(method, path string, operation Operation) error {
 		return fmt.Errorf("operation already exists: %s %s", method, path)
 	}
 
-	// Add "No Content" response if there are no responses configured.
-	if len(operation.ResponsesEns().MapOfResponseOrReferenceValues) == 0 && operation.Responses.Default == nil {
-		operation.Responses.WithMapOfResponseOrReferenceValuesItem(strconv.Itoa(http.StatusNoContent), ResponseOrReference{
-			Response: &Response{
-				Description: http.StatusText(http.StatusNoContent),
-			},
-		})
-	}
+	operation.ensureNoContent()
 
 	return s.SetupOperation(method, path, func(op *Operation) error {
 		*op = operation
@@ -182,6 +175,17 @@ func (s *Spec) AddOperation(method, path string, operation Operation) error {
 	})
 }
 
+// ensureNoContent adds "No Content" response if there are no responses configured.
+func (o *Operation) ensureNoContent() {
+	if len(o.ResponsesEns().MapOfResponseOrReferenceValues) == 0 && o.Responses.Default == nil {
+		o.Responses.WithMapOfResponseOrReferenceValuesItem(strconv.Itoa(http.StatusNoContent), ResponseOrReference{
+			Response: &Response{
+				Description: http.StatusText(http.StatusNoContent),
+			},
+		})
+	}
+}
+
 // AddWebhook validates and sets webhook by name and method.
 //
 // It will fail if webhook with same name already exists.
@@ -192,14 +196,7 @@ func (s *Spec) AddWebhook(method, name string, operation Operation) error {
 
 	method = strings.ToLower(method)
 
-	// Add "No Content" response if there are no responses configured.
-	if len(operation.ResponsesEns().MapOfResponseOrReferenceValues) == 0 && operation.Responses.Default == nil {
-		operation.Responses.WithMapOfResponseOrReferenceValuesItem(strconv.Itoa(http.StatusNoContent), ResponseOrReference{
-			Response: &Response{
-				Description: http.StatusText(http.StatusNoContent),
-			},
-		})
-	}
+	operation.ensureNoContent()
 
 	method, _, _, err := openapi.SanitizeMethodPath(method, "/")
 	if err != nil {
@@ -0,0 +1,89 @@
+package openapi31
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// parseResponseLinks adds links declared with `link` field tags of response structure.
+//
+// Target operation must be the current one or must be already added to spec.
+func (r *Reflector) parseResponseLinks(resp *Response, o *Operation, cu openapi.ContentUnit) error {
+	for _, l := range internal.ResponseLinks(cu.Structure, cu.FieldMapping(openapi.InHeader)) {
+		params, found := r.operationParameters(o, l.OperationID)
+		if !found {
+			return fmt.Errorf("link %s: operation not found", l.OperationID)
+		}
+
+		if !r.hasParameter(params, l.Parameter) {
+			return fmt.Errorf("link %s: parameter %s not found", l.OperationID, l.Parameter)
+		}
+
+		link := resp.Links[l.OperationID]
+		if link.Link == nil {
+			link.Link = (&Link{}).WithOperationID(l.OperationID)
+		}
+
+		link.Link.WithParametersItem(l.Parameter, l.Expression)
+
+		resp.WithLinksItem(l.OperationID, link)
+	}
+
+	return nil
+}
+
+// operationParameters finds parameters of operation by its ID.
+func (r *Reflector) operationParameters(o *Operation, operationID string) ([]ParameterOrReference, bool) {
+	if o.ID != nil && *o.ID == operationID {
+		return o.Parameters, true
+	}
+
+	pathItems := make([]PathItem, 0, len(r.SpecEns().PathsEns().MapOfPathItemValues)+len(r.Spec.Webhooks))
+
+	for _, pi := range r.Spec.Paths.MapOfPathItemValues {
+		pathItems = append(pathItems, pi)
+	}
+
+	for _, pi := range r.Spec.Webhooks {
+		if pi.PathItem != nil {
+			pathItems = append(pathItems, *pi.PathItem)
+		}
+	}
+
+	methods := []string{
+		http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+		http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace,
+	}
+
+	for _, pi := range pathItems {
+		for _, method := range methods {
+			op, _ := pi.Operation(method) //nolint:errcheck // Method is valid.
+			if op != nil && op.ID != nil && *op.ID == operationID {
+				return append(append([]ParameterOrReference{}, pi.Parameters...), op.Parameters...), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// hasParameter checks if parameter exists by name or by qualified name, e.g. "path.id".
+func (r *Reflector) hasParameter(params []ParameterOrReference, name string) bool {
+	for _, pr := range params {
+		p := pr.Parameter
+
+		if pr.Reference != nil && r.Spec.Components != nil {
+			p = r.Spec.Components.Parameters[strings.TrimPrefix(pr.Reference.Ref, "#/components/parameters/")].Parameter
+		}
+
+		if p != nil && (p.Name == name || string(p.In)+"."+p.Name == name) {
+			return true
+		}
+	}
+
+	return false
+}
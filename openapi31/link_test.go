@@ -0,0 +1,95 @@
+package openapi31_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+type getOrderReq struct {
+	ID     int    `path:"id"`
+	Expand string `query:"expand"`
+}
+
+type createdOrder struct {
+	Location string `header:"Location" link:"getOrder:expand"`
+	ID       int    `json:"id" link:"getOrder:path.id,cancelOrder:orderId"`
+	Customer struct {
+		ID int `json:"id" link:"getCustomer"`
+	} `json:"customer"`
+}
+
+func TestReflector_AddOperation_links(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	for _, op := range []struct {
+		id, method, path string
+		req              interface{}
+	}{
+		{"getOrder", http.MethodGet, "/orders/{id}", new(getOrderReq)},
+		{"cancelOrder", http.MethodDelete, "/orders/{orderId}", new(struct {
+			OrderID int `path:"orderId"`
+		})},
+	} {
+		oc, err := r.NewOperationContext(op.method, op.path)
+		require.NoError(t, err)
+
+		oc.SetID(op.id)
+		oc.AddReqStructure(op.req)
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	oc, err := r.NewOperationContext(http.MethodPost, "/orders")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(new(createdOrder), openapi.WithHTTPStatus(http.StatusCreated))
+
+	assert.EqualError(t, r.AddOperation(oc),
+		"setup response post /orders: link getCustomer: operation not found")
+
+	oc, err = r.NewOperationContext(http.MethodGet, "/customers/{id}")
+	require.NoError(t, err)
+
+	oc.SetID("getCustomer")
+	oc.AddReqStructure(new(struct {
+		CustomerID int `path:"id"`
+	}))
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/orders")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(new(createdOrder), openapi.WithHTTPStatus(http.StatusCreated))
+	require.NoError(t, r.AddOperation(oc))
+
+	assertjson.EqMarshal(t, `{
+		"201":{
+			"description":"Created",
+			"headers":{"Location":{"style":"simple","schema":{"type":"string"}}},
+			"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestCreatedOrder"}}},
+			"links":{
+				"cancelOrder":{"operationId":"cancelOrder","parameters":{"orderId":"$response.body#/id"}},
+				"getCustomer":{"operationId":"getCustomer","parameters":{"id":"$response.body#/customer/id"}},
+				"getOrder":{
+					"operationId":"getOrder",
+					"parameters":{"expand":"$response.header.Location","path.id":"$response.body#/id"}
+				}
+			}
+		}
+	}`, r.Spec.Paths.MapOfPathItemValues["/orders"].Post.Responses)
+
+	oc, err = r.NewOperationContext(http.MethodPut, "/orders")
+	require.NoError(t, err)
+
+	oc.AddRespStructure(new(struct {
+		ID int `json:"id" link:"getOrder:orderId"`
+	}))
+
+	assert.EqualError(t, r.AddOperation(oc),
+		"setup response put /orders: link getOrder: parameter orderId not found")
+}
@@ -20,12 +20,20 @@ type Reflector struct {
 	jsonschema.Reflector
 
 	Spec *Spec
+
+	// ReuseComponents enables deduplication of identical parameters, headers, request bodies and responses
+	// into components, it is applied on every Reflector.AddOperation and Reflector.AddWebhook.
+	ReuseComponents *openapi.ReuseComponents
+
+	discriminators        map[reflect.Type]openapi.Discriminator
+	discriminatorsEnabled bool
 }
 
 // NewReflector creates an instance of OpenAPI 3.1 reflector.
 func NewReflector() *Reflector {
 	r := &Reflector{}
 	r.SpecEns()
+	r.ensureDiscriminators()
 
 	r.DefaultOptions = append(r.DefaultOptions, jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
 		// See https://spec.openapis.org/oas/v3.1.0.html#data-types.
@@ -62,7 +70,7 @@ func (r *Reflector) NewOperationContext(method, pathPatternOrWebhookName string)
 	}
 
 	if operation != nil {
-		return nil, fmt.Errorf("operation already exists: %s %s", method, pathPatternOrWebhookName)
+		return nil, fmt.Errorf("operation already exists: %s %s", strings.ToUpper(method), pathPattern)
 	}
 
 	operation = &Operation{}
@@ -217,7 +225,7 @@ func (r *Reflector) AddOperation(oc openapi.OperationContext) error {
 		return err
 	}
 
-	return r.SpecEns().AddOperation(oc.Method(), oc.PathPattern(), *c.op)
+	return r.addOperation(oc.Method(), oc.PathPattern(), *c.op)
 }
 
 // AddWebhook configures webhook request and response schema.
@@ -227,7 +235,7 @@ func (r *Reflector) AddWebhook(oc openapi.OperationContext) error {
 		return err
 	}
 
-	return r.SpecEns().AddWebhook(oc.Method(), c.PathPattern(), *c.op)
+	return r.addWebhook(oc.Method(), c.PathPattern(), *c.op)
 }
 
 func (r *Reflector) setupOC(oc openapi.OperationContext) (operationContext, error) {
@@ -248,6 +256,10 @@ func (r *Reflector) setupOC(oc openapi.OperationContext) (operationContext, erro
 		return c, fmt.Errorf("setup response %s %s: %w", oc.Method(), oc.PathPattern(), err)
 	}
 
+	if err := r.setupCallbacks(c.op, oc); err != nil {
+		return c, fmt.Errorf("setup callbacks %s %s: %w", oc.Method(), oc.PathPattern(), err)
+	}
+
 	return c, nil
 }
 
@@ -676,6 +688,10 @@ func (r *Reflector) setupResponse(o *Operation, oc openapi.OperationContext) err
 			}
 		}
 
+		if err := r.parseResponseLinks(resp, o, cu); err != nil {
+			return err
+		}
+
 		if cu.Description != "" {
 			resp.Description = cu.Description
 		}
@@ -1647,3 +1647,17 @@ func TestReflector_AddWebhook(t *testing.T) {
 	  }
 	}`, r.SpecEns())
 }
+
+func TestReflector_NewOperationContext_exists(t *testing.T) {
+	r := openapi31.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/items/{id:[0-9]+}")
+	require.NoError(t, err)
+	oc.AddReqStructure(struct {
+		ID int `path:"id"`
+	}{})
+	require.NoError(t, r.AddOperation(oc))
+
+	_, err = r.NewOperationContext("", "GET /items/{id}")
+	assert.EqualError(t, err, "operation already exists: GET /items/{id}")
+}
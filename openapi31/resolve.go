@@ -0,0 +1,63 @@
+package openapi31
+
+import (
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// RefResolver resolves local references of a spec.
+//
+// It works with a snapshot of the spec taken by NewRefResolver, later changes of the spec are not visible.
+type RefResolver struct {
+	doc map[string]interface{}
+}
+
+// NewRefResolver creates resolver for a spec.
+func NewRefResolver(s *Spec) (*RefResolver, error) {
+	doc, err := internal.ToDocument(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RefResolver{doc: doc}, nil
+}
+
+// Resolve loads a value by local JSON Pointer reference into v.
+//
+// Any part of a document can be resolved, for example "#/components/parameters/limit" into *Parameter,
+// "#/components/pathItems/users" into *PathItem or "#/paths/~1users/get/responses/200" into *Response.
+// Chained references (a component that refers to another component) are followed.
+//
+// Errors wrap openapi.ErrReferenceNotFound, openapi.ErrNonLocalReference or openapi.ErrCircularReference.
+func (r *RefResolver) Resolve(ref string, v interface{}) error {
+	res, err := internal.ResolveRef(r.doc, ref)
+	if err != nil {
+		return err
+	}
+
+	return internal.FromDocument(res, v)
+}
+
+// Dereference returns a copy of spec with local references replaced by referenced values.
+//
+// Properties next to "$ref" (e.g. "summary" and "description") override properties of referenced value.
+// References that would lead to infinite expansion (e.g. recursive schemas) are left intact
+// together with their components.
+func (s *Spec) Dereference() (*Spec, error) {
+	doc, err := internal.ToDocument(s)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err = internal.Dereference(doc, internal.DereferenceOptions{KeepRefSiblings: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Spec{}
+
+	if err := internal.FromDocument(doc, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,151 @@
+package openapi31_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+const refSpec = `
+openapi: 3.1.0
+info: {title: Nodes, version: 1.0.0}
+paths:
+  /nodes:
+    get:
+      operationId: listNodes
+      parameters:
+        - $ref: '#/components/parameters/limit'
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Node'}}
+  /nodes/{id}:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/id'
+      responses:
+        "200":
+          $ref: '#/components/responses/node'
+      callbacks:
+        changed:
+          $ref: '#/components/callbacks/changed'
+components:
+  parameters:
+    limit: {$ref: '#/components/parameters/pageSize'}
+    pageSize: {name: limit, in: query, schema: {type: integer}}
+    id: {name: id, in: path, required: true, schema: {type: string}}
+  responses:
+    node:
+      description: Node.
+      headers:
+        X-Rate-Limit: {$ref: '#/components/headers/rateLimit'}
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Node'}
+      links:
+        children: {operationId: listNodes, parameters: {limit: '$request.query.limit'}}
+  headers:
+    rateLimit: {schema: {type: integer}}
+  links:
+    children: {operationId: listNodes}
+  callbacks:
+    changed:
+      '{$request.query.url}':
+        post:
+          requestBody:
+            content:
+              application/json:
+                schema: {$ref: '#/components/schemas/Node'}
+          responses:
+            "204": {description: OK}
+  schemas:
+    Node:
+      type: object
+      properties:
+        id: {type: string}
+        default: {$ref: '#/components/schemas/Leaf', description: Default child.}
+        children: {type: array, items: {$ref: '#/components/schemas/Node'}}
+    Leaf:
+      type: object
+      properties:
+        id: {type: string}
+      default: {$ref: not a reference}
+`
+
+func TestRefResolver_Resolve(t *testing.T) {
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(refSpec)))
+
+	r, err := openapi31.NewRefResolver(&s)
+	require.NoError(t, err)
+
+	var p openapi31.Parameter
+
+	require.NoError(t, r.Resolve("#/components/parameters/limit", &p))
+	assert.Equal(t, "limit", p.Name)
+	assert.Equal(t, openapi31.ParameterInQuery, p.In)
+
+	var cb map[string]openapi31.PathItem
+
+	require.NoError(t, r.Resolve("#/paths/~1nodes~1{id}/get/callbacks/changed", &cb))
+	require.NotNil(t, cb["{$request.query.url}"].Post)
+
+	var l openapi31.Link
+
+	require.NoError(t, r.Resolve("#/components/links/children", &l))
+	assert.Equal(t, "listNodes", *l.OperationID)
+
+	var h openapi31.Header
+
+	require.NoError(t, r.Resolve("#/components/responses/node/headers/X-Rate-Limit", &h))
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, h.Schema)
+
+	err = r.Resolve("#/components/parameters/missing", &p)
+	assert.EqualError(t, err, "reference not found: #/components/parameters/missing")
+	assert.True(t, errors.Is(err, openapi.ErrReferenceNotFound))
+
+	err = r.Resolve("other.yaml#/components/parameters/limit", &p)
+	assert.EqualError(t, err, "non-local reference: other.yaml#/components/parameters/limit")
+	assert.True(t, errors.Is(err, openapi.ErrNonLocalReference))
+}
+
+func TestSpec_Dereference(t *testing.T) {
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(refSpec)))
+
+	d, err := s.Dereference()
+	require.NoError(t, err)
+
+	// Source spec is not changed.
+	assert.NotNil(t, s.Paths.MapOfPathItemValues["/nodes/{id}"].Get.Parameters[0].Reference)
+
+	op := d.Paths.MapOfPathItemValues["/nodes/{id}"].Get
+	require.NotNil(t, op.Parameters[0].Parameter)
+	assert.Equal(t, "id", op.Parameters[0].Parameter.Name)
+
+	resp := op.Responses.MapOfResponseOrReferenceValues["200"].Response
+	require.NotNil(t, resp)
+	assert.Equal(t, "Node.", resp.Description)
+	assert.NotNil(t, resp.Headers["X-Rate-Limit"].Header)
+	assert.NotNil(t, op.Callbacks["changed"].Callbacks)
+
+	// Recursive schema is left as reference, literal values are not dereferenced.
+	assertjson.EqualMarshal(t, []byte(`{
+	  "type":"object",
+	  "properties":{
+		"children":{"type":"array","items":{"$ref":"#/components/schemas/Node"}},
+		"default":{
+		  "type":"object","description":"Default child.",
+		  "properties":{"id":{"type":"string"}},"default":{"$ref":"not a reference"}
+		},
+		"id":{"type":"string"}
+	  }
+	}`), resp.Content["application/json"].Schema)
+}
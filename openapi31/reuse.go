@@ -0,0 +1,354 @@
+package openapi31
+
+import (
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// reuseComponents moves duplicated parameters, headers, request bodies and responses to components.
+//
+// With dryRun entities are only collected, so that errors are found before spec is changed.
+func (r *Reflector) reuseComponents(dryRun bool) error {
+	if r.ReuseComponents == nil {
+		return nil
+	}
+
+	rc := *r.ReuseComponents
+
+	for _, reuse := range []struct {
+		kind openapi.ComponentKind
+		fn   func(rc openapi.ReuseComponents, dryRun bool) error
+	}{
+		{kind: openapi.ComponentParameters, fn: r.reuseParameters},
+		{kind: openapi.ComponentHeaders, fn: r.reuseHeaders},
+		{kind: openapi.ComponentRequestBodies, fn: r.reuseRequestBodies},
+		{kind: openapi.ComponentResponses, fn: r.reuseResponses},
+	} {
+		if !rc.IsEnabled(reuse.kind) {
+			continue
+		}
+
+		if err := reuse.fn(rc, dryRun); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addOperation adds operation to spec and moves duplicated entities to components.
+//
+// Added operation is removed if components reuse fails, so that spec is not left half-mutated.
+func (r *Reflector) addOperation(method, path string, op Operation) error {
+	s := r.SpecEns()
+
+	if r.ReuseComponents == nil {
+		return s.AddOperation(method, path, op)
+	}
+
+	_, cleanPath, _, err := openapi.SanitizeMethodPath(method, path)
+	if err != nil {
+		return err
+	}
+
+	paths := s.Paths
+	pathItem, found := s.PathsEns().MapOfPathItemValues[cleanPath]
+
+	if err := s.AddOperation(method, path, op); err != nil {
+		return err
+	}
+
+	if err := r.reuseComponents(true); err != nil {
+		switch {
+		case paths == nil:
+			s.Paths = nil
+		case found:
+			s.Paths.MapOfPathItemValues[cleanPath] = pathItem
+		default:
+			delete(s.Paths.MapOfPathItemValues, cleanPath)
+		}
+
+		return err
+	}
+
+	return r.reuseComponents(false)
+}
+
+// addWebhook adds webhook to spec and moves duplicated entities to components.
+//
+// Added webhook is removed if components reuse fails, so that spec is not left half-mutated.
+func (r *Reflector) addWebhook(method, name string, op Operation) error {
+	s := r.SpecEns()
+
+	if err := s.AddWebhook(method, name, op); err != nil {
+		return err
+	}
+
+	if err := r.reuseComponents(true); err != nil {
+		delete(s.Webhooks, name)
+
+		return err
+	}
+
+	return r.reuseComponents(false)
+}
+
+func (r *Reflector) reuseParameters(rc openapi.ReuseComponents, dryRun bool) error {
+	c := r.components()
+
+	existing := make(map[string]interface{}, len(c.Parameters))
+	for name, p := range c.Parameters {
+		existing[name] = p.Parameter
+	}
+
+	reuse, err := internal.NewComponentsReuse(openapi.ComponentParameters, rc, existing)
+	if err != nil {
+		return err
+	}
+
+	add := func(params []ParameterOrReference) error {
+		for i, p := range params {
+			if p.Parameter == nil {
+				continue
+			}
+
+			i := i
+			if err := reuse.Add(p.Parameter.Name, p.Parameter, func(ref string) {
+				params[i] = ParameterOrReference{Reference: &Reference{Ref: ref}}
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, pi := range r.pathItems() {
+		if err := add(pi.Parameters); err != nil {
+			return err
+		}
+	}
+
+	if err := r.walkOperations(func(op *Operation) error {
+		return add(op.Parameters)
+	}); err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	reuse.Apply(func(name string, entity interface{}) {
+		r.SpecEns().ComponentsEns().WithParametersItem(name, ParameterOrReference{Parameter: entity.(*Parameter)})
+	})
+
+	return nil
+}
+
+func (r *Reflector) reuseHeaders(rc openapi.ReuseComponents, dryRun bool) error {
+	c := r.components()
+
+	existing := make(map[string]interface{}, len(c.Headers))
+	for name, h := range c.Headers {
+		existing[name] = h.Header
+	}
+
+	reuse, err := internal.NewComponentsReuse(openapi.ComponentHeaders, rc, existing)
+	if err != nil {
+		return err
+	}
+
+	if err := r.walkOperations(func(op *Operation) error {
+		return walkResponses(op, func(_ string, resp *Response) error {
+			for _, name := range internal.SortedMapKeys(resp.Headers) {
+				h := resp.Headers[name]
+				if h.Header == nil {
+					continue
+				}
+
+				headers, name := resp.Headers, name
+				if err := reuse.Add(name, h.Header, func(ref string) {
+					headers[name] = HeaderOrReference{Reference: &Reference{Ref: ref}}
+				}); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	reuse.Apply(func(name string, entity interface{}) {
+		r.SpecEns().ComponentsEns().WithHeadersItem(name, HeaderOrReference{Header: entity.(*Header)})
+	})
+
+	return nil
+}
+
+func (r *Reflector) reuseRequestBodies(rc openapi.ReuseComponents, dryRun bool) error {
+	c := r.components()
+
+	existing := make(map[string]interface{}, len(c.RequestBodies))
+	for name, rb := range c.RequestBodies {
+		existing[name] = rb.RequestBody
+	}
+
+	reuse, err := internal.NewComponentsReuse(openapi.ComponentRequestBodies, rc, existing)
+	if err != nil {
+		return err
+	}
+
+	if err := r.walkOperations(func(op *Operation) error {
+		rb := op.RequestBody
+		if rb == nil || rb.RequestBody == nil {
+			return nil
+		}
+
+		var refs []string
+
+		for _, ct := range internal.SortedMapKeys(rb.RequestBody.Content) {
+			if ref, ok := rb.RequestBody.Content[ct].Schema["$ref"].(string); ok {
+				refs = append(refs, ref)
+			}
+		}
+
+		return reuse.Add(internal.RequestBodyComponentName(refs...), rb.RequestBody, rb.SetReference)
+	}); err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	reuse.Apply(func(name string, entity interface{}) {
+		r.SpecEns().ComponentsEns().WithRequestBodiesItem(name, RequestBodyOrReference{RequestBody: entity.(*RequestBody)})
+	})
+
+	return nil
+}
+
+func (r *Reflector) reuseResponses(rc openapi.ReuseComponents, dryRun bool) error {
+	c := r.components()
+
+	existing := make(map[string]interface{}, len(c.Responses))
+	for name, resp := range c.Responses {
+		existing[name] = resp.Response
+	}
+
+	reuse, err := internal.NewComponentsReuse(openapi.ComponentResponses, rc, existing)
+	if err != nil {
+		return err
+	}
+
+	if err := r.walkOperations(func(op *Operation) error {
+		if op.Responses == nil {
+			return nil
+		}
+
+		for _, status := range internal.SortedMapKeys(op.Responses.MapOfResponseOrReferenceValues) {
+			resp := op.Responses.MapOfResponseOrReferenceValues[status]
+			if resp.Response == nil {
+				continue
+			}
+
+			responses, status := op.Responses.MapOfResponseOrReferenceValues, status
+			if err := reuse.Add(internal.ResponseComponentName(status), resp.Response, func(ref string) {
+				responses[status] = ResponseOrReference{Reference: &Reference{Ref: ref}}
+			}); err != nil {
+				return err
+			}
+		}
+
+		if d := op.Responses.Default; d != nil && d.Response != nil {
+			return reuse.Add(internal.ResponseComponentName("default"), d.Response, d.SetReference)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	reuse.Apply(func(name string, entity interface{}) {
+		r.SpecEns().ComponentsEns().WithResponsesItem(name, ResponseOrReference{Response: entity.(*Response)})
+	})
+
+	return nil
+}
+
+// components returns spec components without creating them.
+func (r *Reflector) components() Components {
+	if c := r.SpecEns().Components; c != nil {
+		return *c
+	}
+
+	return Components{}
+}
+
+// pathItems returns path items of paths and webhooks in a deterministic order.
+func (r *Reflector) pathItems() []*PathItem {
+	var pathItems []*PathItem
+
+	paths := r.SpecEns().PathsEns().MapOfPathItemValues
+	for _, path := range internal.SortedMapKeys(paths) {
+		pi := paths[path]
+		pathItems = append(pathItems, &pi)
+	}
+
+	for _, name := range internal.SortedMapKeys(r.Spec.Webhooks) {
+		if pi := r.Spec.Webhooks[name].PathItem; pi != nil {
+			pathItems = append(pathItems, pi)
+		}
+	}
+
+	return pathItems
+}
+
+// walkOperations calls f for every operation of paths and webhooks in a deterministic order.
+func (r *Reflector) walkOperations(f func(op *Operation) error) error {
+	for _, pi := range r.pathItems() {
+		for _, op := range []*Operation{pi.Get, pi.Put, pi.Post, pi.Delete, pi.Options, pi.Head, pi.Patch, pi.Trace} {
+			if op == nil {
+				continue
+			}
+
+			if err := f(op); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkResponses calls f for every inline response of operation in a deterministic order.
+func walkResponses(op *Operation, f func(status string, resp *Response) error) error {
+	if op.Responses == nil {
+		return nil
+	}
+
+	for _, status := range internal.SortedMapKeys(op.Responses.MapOfResponseOrReferenceValues) {
+		if resp := op.Responses.MapOfResponseOrReferenceValues[status].Response; resp != nil {
+			if err := f(status, resp); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d := op.Responses.Default; d != nil && d.Response != nil {
+		return f("default", d.Response)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package openapi31_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+type traced struct {
+	RequestID string `header:"X-Request-ID"`
+}
+
+type errResp struct {
+	RequestID string `header:"X-Request-ID"`
+	Message   string `json:"message"`
+}
+
+type itemReq struct {
+	traced
+	ID    int    `path:"id"`
+	Title string `json:"title"`
+}
+
+func TestReflector_ReuseComponents(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.ReuseComponents = &openapi.ReuseComponents{
+		Name: func(kind openapi.ComponentKind, defaultName string, entity interface{}) string {
+			if kind == openapi.ComponentParameters {
+				return string(entity.(*openapi31.Parameter).In) + "." + defaultName
+			}
+
+			return defaultName
+		},
+	}
+
+	for _, op := range []struct {
+		method, path string
+		req          interface{}
+	}{
+		{http.MethodPut, "/items/{id}", new(itemReq)},
+		{http.MethodPatch, "/items/{id}", new(itemReq)},
+		{http.MethodGet, "/items", new(traced)},
+	} {
+		oc, err := r.NewOperationContext(op.method, op.path)
+		require.NoError(t, err)
+
+		oc.AddReqStructure(op.req)
+		oc.AddRespStructure(new(errResp), openapi.WithHTTPStatus(http.StatusNotFound))
+
+		require.NoError(t, r.AddOperation(oc))
+	}
+
+	assertjson.EqMarshal(t, `{
+		"openapi":"3.1.0","info":{"title":"","version":""},
+		"paths":{
+			"/items":{
+				"get":{
+					"parameters":[{"$ref":"#/components/parameters/header.X-Request-ID"}],
+					"responses":{"404":{"$ref":"#/components/responses/NotFound"}}
+				}
+			},
+			"/items/{id}":{
+				"put":{
+					"parameters":[
+						{"$ref":"#/components/parameters/path.id"},
+						{"$ref":"#/components/parameters/header.X-Request-ID"}
+					],
+					"requestBody":{"$ref":"#/components/requestBodies/Openapi31TestItemReq"},
+					"responses":{"404":{"$ref":"#/components/responses/NotFound"}}
+				},
+				"patch":{
+					"parameters":[
+						{"$ref":"#/components/parameters/path.id"},
+						{"$ref":"#/components/parameters/header.X-Request-ID"}
+					],
+					"requestBody":{"$ref":"#/components/requestBodies/Openapi31TestItemReq"},
+					"responses":{"404":{"$ref":"#/components/responses/NotFound"}}
+				}
+			}
+		},
+		"components":{
+			"schemas":{
+				"Openapi31TestErrResp":{"properties":{"message":{"type":"string"}},"type":"object"},
+				"Openapi31TestItemReq":{"properties":{"title":{"type":"string"}},"type":"object"}
+			},
+			"responses":{
+				"NotFound":{
+					"description":"Not Found",
+					"headers":{"X-Request-ID":{"$ref":"#/components/headers/X-Request-ID"}},
+					"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestErrResp"}}}
+				}
+			},
+			"parameters":{
+				"header.X-Request-ID":{"name":"X-Request-ID","in":"header","schema":{"type":"string"}},
+				"path.id":{"name":"id","in":"path","required":true,"schema":{"type":"integer"}}
+			},
+			"requestBodies":{
+				"Openapi31TestItemReq":{
+					"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Openapi31TestItemReq"}}}
+				}
+			},
+			"headers":{"X-Request-ID":{"style":"simple","schema":{"type":"string"}}}
+		}
+	}`, r.SpecSchema())
+}
+
+func TestReflector_ReuseComponents_noDuplicates(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.ReuseComponents = &openapi.ReuseComponents{}
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(new(itemReq))
+	oc.AddRespStructure(new(errResp), openapi.WithHTTPStatus(http.StatusNotFound))
+
+	require.NoError(t, r.AddOperation(oc))
+
+	c := r.SpecEns().Components
+	require.NotNil(t, c)
+	assert.Nil(t, c.Parameters)
+	assert.Nil(t, c.Headers)
+	assert.Nil(t, c.RequestBodies)
+	assert.Nil(t, c.Responses)
+}
+
+func TestReflector_ReuseComponents_error(t *testing.T) {
+	r := openapi31.NewReflector()
+	r.ReuseComponents = &openapi.ReuseComponents{}
+
+	var invalid interface{} = make(chan int)
+
+	r.SpecEns().ComponentsEns().WithParametersItem("invalid", openapi31.ParameterOrReference{
+		Parameter: &openapi31.Parameter{Name: "invalid", In: openapi31.ParameterInQuery, Example: &invalid},
+	})
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(new(itemReq))
+
+	require.Error(t, r.AddOperation(oc))
+	assert.Empty(t, r.SpecEns().PathsEns().MapOfPathItemValues)
+}
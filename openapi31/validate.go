@@ -0,0 +1,16 @@
+package openapi31
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/resources/schema"
+)
+
+// Validate checks document against OpenAPI 3.1 meta-schema and semantic rules.
+//
+// Semantic rules require unique operation IDs, resolvable local references, path parameters
+// matching path templates and security requirements referencing defined security schemes.
+// Returned error is *openapi.SpecError with all found problems.
+func (s *Spec) Validate() error {
+	return internal.ValidateSpec(s, "https://spec.openapis.org/oas/3.1/schema/2022-10-07", schema.OpenAPI31, jsonschema.Draft2020)
+}
@@ -0,0 +1,86 @@
+package openapi31_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+func TestSpec_Validate(t *testing.T) {
+	data, err := os.ReadFile("testdata/openapi.json")
+	require.NoError(t, err)
+
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalJSON(data))
+	require.NoError(t, s.Validate())
+
+	s = openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.1.0
+info: {title: Pets, version: 1.0.0}
+security:
+  - apiKey: []
+paths:
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+    delete:
+      operationId: getPet
+      security:
+        - bearer: []
+      parameters:
+        - {name: petId, in: path, required: true, schema: {type: string}}
+        - {name: limit, in: query, schema: {type: integer}, style: matrix}
+      responses:
+        "204": {$ref: '#/components/responses/NoContent'}
+  /owners/{ownerId}/pets:
+    get:
+      responses:
+        "200": {description: OK}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner: {$ref: '#/components/schemas/Owner'}
+        tags: {type: array, items: {$ref: '#/components/schemas/Tag'}}
+    Tag: {type: string}
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+`)))
+
+	err = s.Validate()
+	require.Error(t, err)
+
+	var se *openapi.SpecError
+	require.True(t, errors.As(err, &se))
+
+	var problems []string
+	for _, p := range se.Problems {
+		problems = append(problems, p.String())
+	}
+
+	assert.Equal(t, []string{
+		`/components/schemas/Pet/properties/owner/$ref: unresolved reference "#/components/schemas/Owner"`,
+		`/paths/~1owners~1{ownerId}~1pets/get: missing path parameter "ownerId"`,
+		`/paths/~1pets~1{id}/delete/operationId: duplicate operationId "getPet", first used at /paths/~1pets~1{id}/get`,
+		`/paths/~1pets~1{id}/delete/parameters/0: path parameter "petId" is not in path template`,
+		`/paths/~1pets~1{id}/delete/parameters/1/style: not allowed`,
+		`/paths/~1pets~1{id}/delete/parameters/1/style: value must be one of "form", "spaceDelimited", "pipeDelimited", "deepObject"`,
+		`/paths/~1pets~1{id}/delete/responses/204/$ref: unresolved reference "#/components/responses/NoContent"`,
+		`/security/0/apiKey: undefined security scheme "apiKey"`,
+	}, problems)
+}
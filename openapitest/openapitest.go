@@ -0,0 +1,78 @@
+// Package openapitest provides helpers to check HTTP handlers against OpenAPI spec in tests.
+package openapitest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/swaggest/openapi-go/validator"
+)
+
+// TestingT is a subset of testing.TB.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Recorder records handler response to check it against operation of the request.
+type Recorder struct {
+	*httptest.ResponseRecorder
+
+	v   *validator.Validator
+	req *http.Request
+}
+
+// NewRecorder creates response recorder for a request.
+//
+// Validator created with validator.NewFromReflector for operation contexts checks responses
+// against schemas provided by reflector WalkResponseJSONSchemas.
+func NewRecorder(v *validator.Validator, req *http.Request) *Recorder {
+	return &Recorder{
+		ResponseRecorder: httptest.NewRecorder(),
+		v:                v,
+		req:              req,
+	}
+}
+
+// Validate checks recorded status, headers and body, it returns *validator.Error with all violations.
+func (r *Recorder) Validate() error {
+	res := r.Result()
+	defer res.Body.Close() //nolint:errcheck // Body is in-memory.
+
+	return r.v.ValidateResponse(r.req, res.StatusCode, res.Header, r.Body.Bytes())
+}
+
+// Assert reports every violation as a test error, it returns true if response matches spec.
+func (r *Recorder) Assert(t TestingT) bool {
+	t.Helper()
+
+	err := r.Validate()
+	if err == nil {
+		return true
+	}
+
+	var verr *validator.Error
+	if !errors.As(err, &verr) {
+		t.Errorf("%s %s: %v", r.req.Method, r.req.URL.Path, err)
+
+		return false
+	}
+
+	for _, vl := range verr.Violations {
+		t.Errorf("%s %s %d: %s", r.req.Method, r.req.URL.Path, r.Code, vl.String())
+	}
+
+	return false
+}
+
+// AssertHandler serves request with handler and asserts that response matches spec.
+func AssertHandler(t TestingT, v *validator.Validator, h http.Handler, req *http.Request) *Recorder {
+	t.Helper()
+
+	rec := NewRecorder(v, req)
+	h.ServeHTTP(rec, req)
+	rec.Assert(t)
+
+	return rec
+}
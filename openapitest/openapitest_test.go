@@ -0,0 +1,112 @@
+package openapitest_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/openapitest"
+	"github.com/swaggest/openapi-go/validator"
+)
+
+type user struct {
+	RateLimit int    `header:"X-Rate-Limit" required:"true" minimum:"1"`
+	ID        int    `json:"id" required:"true"`
+	Name      string `json:"name" minLength:"1"`
+	Role      string `json:"role" enum:"admin,guest"`
+}
+
+type errorsT struct {
+	errors []string
+}
+
+func (t *errorsT) Helper() {}
+
+func (t *errorsT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecorder_Assert(t *testing.T) {
+	for name, r := range map[string]openapi.Reflector{
+		"openapi3":  openapi3.NewReflector(),
+		"openapi31": openapi31.NewReflector(),
+	} {
+		r := r
+
+		t.Run(name, func(t *testing.T) {
+			oc, err := r.NewOperationContext(http.MethodGet, "/users/{id}")
+			require.NoError(t, err)
+
+			oc.AddReqStructure(new(struct {
+				ID int `path:"id"`
+			}))
+			oc.AddRespStructure(new(user))
+			oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNotFound))
+
+			require.NoError(t, r.AddOperation(oc))
+
+			v, err := validator.NewFromReflector(r, oc)
+			require.NoError(t, err)
+
+			valid := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("X-Rate-Limit", "10")
+				rw.Header().Set("Content-Type", "application/json")
+				_, _ = rw.Write([]byte(`{"id":1,"name":"Jane","role":"admin"}`))
+			})
+
+			tt := &errorsT{}
+			rec := openapitest.AssertHandler(tt, v, valid, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+			assert.Empty(t, tt.errors)
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			invalid := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("Content-Type", "application/json")
+				_, _ = rw.Write([]byte(`{"name":"","role":"owner"}`))
+			})
+
+			tt = &errorsT{}
+			openapitest.AssertHandler(tt, v, invalid, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+			assert.Equal(t, []string{
+				"GET /users/1 200: header.X-Rate-Limit: missing required header",
+				"GET /users/1 200: body: missing properties: 'id'",
+				"GET /users/1 200: body/name: length must be >= 1, but got 0",
+				"GET /users/1 200: body/role: value must be one of \"admin\", \"guest\"",
+			}, tt.errors)
+
+			wrongType := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("X-Rate-Limit", "0")
+				rw.Header().Set("Content-Type", "text/plain")
+				_, _ = rw.Write([]byte(`hello`))
+			})
+
+			tt = &errorsT{}
+			openapitest.AssertHandler(tt, v, wrongType, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+			assert.Equal(t, []string{
+				"GET /users/1 200: header.X-Rate-Limit: must be >= 1 but found 0",
+				"GET /users/1 200: header.Content-Type: unexpected content type: \"text/plain\"",
+			}, tt.errors)
+
+			undocumented := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusTeapot)
+			})
+
+			tt = &errorsT{}
+			openapitest.AssertHandler(tt, v, undocumented, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+			assert.Equal(t, []string{"GET /users/1 418: undocumented response status: 418"}, tt.errors)
+
+			notFound := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusNotFound)
+			})
+
+			rec = openapitest.NewRecorder(v, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+			notFound.ServeHTTP(rec, nil)
+			assert.NoError(t, rec.Validate())
+		})
+	}
+}
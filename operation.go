@@ -155,6 +155,27 @@ type OperationState interface {
 	SetProcessingIn(in In)
 }
 
+// Callback describes an out-of-band request that API server sends in relation to an operation.
+type Callback struct {
+	// Name is a key of callback in operation, e.g. "onStatusChange".
+	Name string
+
+	// Expression is a runtime expression of callback URL, e.g. "{$request.body#/callbackUrl}".
+	Expression string
+
+	// Operation describes callback request and expected responses.
+	Operation OperationContext
+}
+
+// OperationCallbacks is implemented by OperationContext that can have callbacks.
+type OperationCallbacks interface {
+	// AddCallback creates callback operation context to be configured with request and response structures,
+	// it is reflected together with parent operation.
+	AddCallback(name, expression, method string) (OperationContext, error)
+
+	Callbacks() []Callback
+}
+
 type ocCtxKey struct{}
 
 // WithOperationCtx is a jsonschema.ReflectContext option.
@@ -179,7 +200,19 @@ func OperationCtx(rc *jsonschema.ReflectContext) (OperationContext, bool) {
 var regexFindPathParameter = regexp.MustCompile(`{([^}:]+)(:[^}]+)?(?:})`)
 
 // SanitizeMethodPath validates method and parses path element names.
+//
+// Path pattern can have gorilla/mux style regular expressions, e.g. "/items/{id:[0-9]+}",
+// or Go 1.22 http.ServeMux style method, wildcards and end anchor, e.g. "GET /files/{path...}" or "/{$}".
+// Method of pattern is used if method is empty, otherwise they must match.
 func SanitizeMethodPath(method, pathPattern string) (cleanMethod string, cleanPath string, pathParams []string, err error) {
+	if patternMethod, path, ok := splitMethodPattern(pathPattern); ok {
+		if method != "" && !strings.EqualFold(method, patternMethod) {
+			return "", "", nil, fmt.Errorf("method %s does not match pattern %q", method, pathPattern)
+		}
+
+		method, pathPattern = patternMethod, path
+	}
+
 	method = strings.ToLower(method)
 	pathParametersSubmatches := regexFindPathParameter.FindAllStringSubmatch(pathPattern, -1)
 
@@ -190,15 +223,40 @@ func SanitizeMethodPath(method, pathPattern string) (cleanMethod string, cleanPa
 		return "", "", nil, fmt.Errorf("unexpected http method: %s", method)
 	}
 
-	if len(pathParametersSubmatches) > 0 {
-		for _, submatch := range pathParametersSubmatches {
-			pathParams = append(pathParams, submatch[1])
+	for _, submatch := range pathParametersSubmatches {
+		name := submatch[1]
+
+		switch {
+		case name == "$": // Remove http.ServeMux end anchor.
+			pathPattern = strings.Replace(pathPattern, submatch[0], "", 1)
 
-			if submatch[2] != "" { // Remove gorilla.Mux-style regexp in path.
-				pathPattern = strings.Replace(pathPattern, submatch[0], "{"+submatch[1]+"}", 1)
-			}
+			continue
+		case strings.HasSuffix(name, "..."): // Remove http.ServeMux multi-segment wildcard suffix.
+			name = strings.TrimSuffix(name, "...")
+			pathPattern = strings.Replace(pathPattern, submatch[0], "{"+name+"}", 1)
+		case submatch[2] != "": // Remove gorilla.Mux-style regexp in path.
+			pathPattern = strings.Replace(pathPattern, submatch[0], "{"+name+"}", 1)
 		}
+
+		pathParams = append(pathParams, name)
 	}
 
 	return method, pathPattern, pathParams, nil
 }
+
+// splitMethodPattern splits http.ServeMux pattern with method, e.g. "GET /items/{id}".
+func splitMethodPattern(pattern string) (method, path string, ok bool) {
+	i := strings.IndexAny(pattern, " \t")
+	if i <= 0 {
+		return "", "", false
+	}
+
+	method = pattern[:i]
+	path = strings.TrimLeft(pattern[i:], " \t")
+
+	if method != strings.ToUpper(method) || !strings.HasPrefix(path, "/") {
+		return "", "", false
+	}
+
+	return method, path, true
+}
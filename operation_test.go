@@ -16,3 +16,36 @@ func TestContentUnit_options(t *testing.T) {
 	assert.Equal(t, "text/csv", cu.ContentType)
 	assert.Equal(t, http.StatusConflict, cu.HTTPStatus)
 }
+
+func TestSanitizeMethodPath(t *testing.T) {
+	for _, tc := range []struct {
+		method, pattern string
+		cleanMethod     string
+		cleanPath       string
+		params          []string
+		err             string
+	}{
+		{method: http.MethodGet, pattern: "/items/{id:[0-9]+}", cleanMethod: "get", cleanPath: "/items/{id}", params: []string{"id"}},
+		{pattern: "GET /items/{id}", cleanMethod: "get", cleanPath: "/items/{id}", params: []string{"id"}},
+		{method: "get", pattern: "GET  /files/{dir}/{path...}", cleanMethod: "get", cleanPath: "/files/{dir}/{path}", params: []string{"dir", "path"}},
+		{pattern: "POST /{$}", cleanMethod: "post", cleanPath: "/"},
+		{pattern: "DELETE /items/{id}/{$}", cleanMethod: "delete", cleanPath: "/items/{id}/", params: []string{"id"}},
+		{method: http.MethodPost, pattern: "GET /items", err: `method POST does not match pattern "GET /items"`},
+		{pattern: "/items", err: "unexpected http method: "},
+		{pattern: "FETCH /items", err: "unexpected http method: fetch"},
+	} {
+		t.Run(tc.method+" "+tc.pattern, func(t *testing.T) {
+			method, path, params, err := openapi.SanitizeMethodPath(tc.method, tc.pattern)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.cleanMethod, method)
+			assert.Equal(t, tc.cleanPath, path)
+			assert.Equal(t, tc.params, params)
+		})
+	}
+}
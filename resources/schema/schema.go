@@ -0,0 +1,16 @@
+// Package schema provides bundled JSON Schemas of OpenAPI documents.
+package schema
+
+import (
+	_ "embed" // Meta-schemas are embedded.
+)
+
+// OpenAPI3 is a JSON Schema of OpenAPI 3.0 document.
+//
+//go:embed openapi3.json
+var OpenAPI3 []byte
+
+// OpenAPI31 is a JSON Schema of OpenAPI 3.1 document, it does not validate JSON Schemas within the document.
+//
+//go:embed openapi31.json
+var OpenAPI31 []byte
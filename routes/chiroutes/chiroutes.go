@@ -0,0 +1,28 @@
+// Package chiroutes collects routes of github.com/go-chi/chi/v5 router.
+package chiroutes
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/swaggest/openapi-go/routes"
+)
+
+// Routes walks router tree including mounted sub-routers and returns its routes.
+//
+// Regular expressions of "{name:regexp}" parameters are collected as parameter patterns,
+// trailing "*" wildcard becomes routes.CatchAll parameter.
+func Routes(r chi.Routes) ([]routes.Route, error) {
+	var res []routes.Route
+
+	err := chi.Walk(r, func(method string, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		res = append(res, routes.New(method, route))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return routes.Normalize(res), nil
+}
@@ -0,0 +1,56 @@
+package chiroutes_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/routes"
+	"github.com/swaggest/openapi-go/routes/chiroutes"
+)
+
+func TestRoutes(t *testing.T) {
+	h := http.NotFoundHandler()
+
+	admin := chi.NewRouter()
+	admin.Get("/", h.ServeHTTP)
+	admin.Delete("/sessions/{sid}", h.ServeHTTP)
+
+	r := chi.NewRouter()
+	r.Get("/users/{id:[0-9]+}", h.ServeHTTP)
+	r.Route("/api", func(r chi.Router) {
+		r.Post("/items", h.ServeHTTP)
+		r.Mount("/admin", admin)
+	})
+	r.Get("/files/*", h.ServeHTTP)
+	r.Handle("/static/*", h)
+
+	rr, err := chiroutes.Routes(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, []routes.Route{
+		{Method: http.MethodGet, Pattern: "/api/admin/", Path: "/api/admin/"},
+		{Method: http.MethodDelete, Pattern: "/api/admin/sessions/{sid}", Path: "/api/admin/sessions/{sid}"},
+		{Method: http.MethodPost, Pattern: "/api/items", Path: "/api/items"},
+		{Method: http.MethodGet, Pattern: "/files/*", Path: "/files/{*}"},
+		{Pattern: "/static/*", Path: "/static/{*}"},
+		{
+			Method: http.MethodGet, Pattern: "/users/{id:[0-9]+}", Path: "/users/{id}",
+			ParamPatterns: map[string]string{"id": "^[0-9]+$"},
+		},
+	}, rr)
+
+	// Routes without operations are reported.
+	refl := openapi31.NewReflector()
+
+	oc, err := refl.NewOperationContext(http.MethodPost, "/api/items")
+	require.NoError(t, err)
+	require.NoError(t, refl.AddOperation(oc))
+
+	missing, err := routes.Sync(refl.Spec, rr)
+	require.NoError(t, err)
+	assert.Len(t, missing, 5)
+}
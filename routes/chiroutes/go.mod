@@ -0,0 +1,21 @@
+module github.com/swaggest/openapi-go/routes/chiroutes
+
+go 1.18
+
+require (
+	github.com/go-chi/chi/v5 v5.0.12
+	github.com/stretchr/testify v1.8.2
+	github.com/swaggest/openapi-go v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.0 // indirect
+	github.com/swaggest/jsonschema-go v0.3.78 // indirect
+	github.com/swaggest/refl v1.4.0 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/swaggest/openapi-go => ../../
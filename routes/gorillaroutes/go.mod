@@ -0,0 +1,27 @@
+module github.com/swaggest/openapi-go/routes/gorillaroutes
+
+go 1.18
+
+require (
+	github.com/gorilla/mux v1.8.0
+	github.com/stretchr/testify v1.8.2
+	github.com/swaggest/assertjson v1.9.0
+	github.com/swaggest/openapi-go v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/bool64/shared v0.1.5 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/iancoleman/orderedmap v0.3.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.0 // indirect
+	github.com/sergi/go-diff v1.3.1 // indirect
+	github.com/swaggest/jsonschema-go v0.3.78 // indirect
+	github.com/swaggest/refl v1.4.0 // indirect
+	github.com/yudai/gojsondiff v1.0.0 // indirect
+	github.com/yudai/golcs v0.0.0-20170316035057-ecda9a501e82 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/swaggest/openapi-go => ../../
@@ -0,0 +1,59 @@
+// Package gorillaroutes collects routes of github.com/gorilla/mux router.
+package gorillaroutes
+
+import (
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/swaggest/openapi-go/routes"
+)
+
+// Routes walks router tree including sub-routers and returns routes that have handlers.
+//
+// Regular expressions of "{name:regexp}" parameters are collected as parameter patterns,
+// PathPrefix routes get routes.CatchAll parameter. Routes without methods (on themselves
+// or on parent routes) serve all methods, routes without path template are skipped.
+func Routes(r *mux.Router) ([]routes.Route, error) {
+	var res []routes.Route
+
+	err := r.Walk(func(route *mux.Route, _ *mux.Router, ancestors []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil
+		}
+
+		pattern, err := route.GetPathTemplate()
+		if err != nil {
+			return nil //nolint:nilerr // Routes without path are skipped.
+		}
+
+		if rx, err := route.GetPathRegexp(); err == nil && !strings.HasSuffix(rx, "$") {
+			pattern = strings.TrimSuffix(pattern, "/") + "/*"
+		}
+
+		for _, method := range methods(route, ancestors) {
+			res = append(res, routes.New(method, pattern))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return routes.Normalize(res), nil
+}
+
+// methods returns methods of route or its closest ancestor, empty method stands for any method.
+func methods(route *mux.Route, ancestors []*mux.Route) []string {
+	if m, err := route.GetMethods(); err == nil {
+		return m
+	}
+
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if m, err := ancestors[i].GetMethods(); err == nil {
+			return m
+		}
+	}
+
+	return []string{""}
+}
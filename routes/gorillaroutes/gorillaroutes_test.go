@@ -0,0 +1,61 @@
+package gorillaroutes_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/routes"
+	"github.com/swaggest/openapi-go/routes/gorillaroutes"
+)
+
+func TestRoutes(t *testing.T) {
+	h := http.NotFoundHandler()
+
+	r := mux.NewRouter()
+	r.Handle("/users/{id:[0-9]+}", h).Methods(http.MethodGet, http.MethodPut)
+
+	api := r.PathPrefix("/api").Methods(http.MethodPost).Subrouter()
+	api.Handle("/items/{slug:[a-z-]+}", h)
+
+	r.PathPrefix("/static/").Handler(h)
+	r.Handle("/health", h)
+
+	rr, err := gorillaroutes.Routes(r)
+	require.NoError(t, err)
+
+	var res []string
+	for _, r := range rr {
+		res = append(res, r.String()+" "+r.Path)
+	}
+
+	assert.Equal(t, []string{
+		"POST /api/items/{slug:[a-z-]+} /api/items/{slug}",
+		"* /health /health",
+		"* /static/* /static/{*}",
+		"GET /users/{id:[0-9]+} /users/{id}",
+		"PUT /users/{id:[0-9]+} /users/{id}",
+	}, res)
+
+	refl := openapi31.NewReflector()
+
+	oc, err := refl.NewOperationContext(http.MethodPost, "/api/items/{name}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(struct {
+		Name string `path:"name"`
+	}{})
+	require.NoError(t, refl.AddOperation(oc))
+
+	missing, err := routes.Sync(refl.Spec, rr)
+	require.NoError(t, err)
+	assert.Len(t, missing, 4)
+
+	assertjson.EqualMarshal(t, []byte(`[
+	  {"name":"name","in":"path","required":true,"schema":{"pattern":"^[a-z-]+$","type":"string"}}
+	]`), refl.Spec.Paths.MapOfPathItemValues["/api/items/{name}"].Post.Parameters)
+}
@@ -0,0 +1,285 @@
+// Package routes maps endpoints of HTTP routers to operations of OpenAPI 3.0 and 3.1 specs.
+//
+// Router adapters, e.g. chiroutes or gorillaroutes, collect routes with OpenAPI path templates,
+// Sync applies regular expression constraints of routes to path parameters of spec and
+// reports routes that have no documented operation.
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// CatchAll is a name of path parameter of trailing "*" wildcard, it matches the rest of URL path.
+const CatchAll = "*"
+
+// Route is an endpoint of a router.
+type Route struct {
+	// Method is an upper case HTTP method, empty for routes that serve all methods.
+	Method string
+
+	// Pattern is a router pattern, e.g. "/users/{id:[0-9]+}".
+	Pattern string
+
+	// Path is an OpenAPI path template, e.g. "/users/{id}".
+	Path string
+
+	// ParamPatterns are anchored regular expressions of constrained path parameters, e.g. {"id": "^[0-9]+$"}.
+	ParamPatterns map[string]string
+}
+
+// String returns route as "METHOD /path", method is "*" for routes that serve all methods.
+func (r Route) String() string {
+	method := r.Method
+	if method == "" {
+		method = "*"
+	}
+
+	return method + " " + r.Pattern
+}
+
+var regexRouteParameter = regexp.MustCompile(`{([^}:]+)(?::((?:[^{}]|{[^{}]*})+))?}`)
+
+// New creates a route of router pattern with "{name}" or "{name:regexp}" parameters,
+// trailing "*" wildcard becomes CatchAll parameter.
+func New(method, pattern string) Route {
+	r := Route{Method: strings.ToUpper(method), Pattern: pattern}
+
+	path := regexRouteParameter.ReplaceAllStringFunc(pattern, func(s string) string {
+		m := regexRouteParameter.FindStringSubmatch(s)
+		name, expr := m[1], m[2]
+
+		if expr != "" {
+			if r.ParamPatterns == nil {
+				r.ParamPatterns = map[string]string{}
+			}
+
+			r.ParamPatterns[name] = anchor(expr)
+		}
+
+		return "{" + name + "}"
+	})
+
+	if strings.HasSuffix(path, "/*") {
+		path = strings.TrimSuffix(path, "*") + "{" + CatchAll + "}"
+	}
+
+	r.Path = path
+
+	return r
+}
+
+// anchor makes regular expression match the whole value.
+func anchor(expr string) string {
+	if !strings.HasPrefix(expr, "^") {
+		expr = "^" + expr
+	}
+
+	if !strings.HasSuffix(expr, "$") {
+		expr += "$"
+	}
+
+	return expr
+}
+
+// allMethods are methods that routers register for handlers of any method.
+var allMethods = []string{
+	http.MethodConnect, http.MethodDelete, http.MethodGet, http.MethodHead,
+	http.MethodOptions, http.MethodPatch, http.MethodPost, http.MethodPut, http.MethodTrace,
+}
+
+// Normalize sorts routes by path and method and replaces routes of a pattern that are registered
+// for all methods with a single route with empty method.
+func Normalize(routes []Route) []Route {
+	methods := map[string]map[string]bool{}
+
+	for _, r := range routes {
+		if methods[r.Pattern] == nil {
+			methods[r.Pattern] = map[string]bool{}
+		}
+
+		methods[r.Pattern][r.Method] = true
+	}
+
+	res := make([]Route, 0, len(routes))
+	anyMethod := map[string]bool{}
+
+	for _, r := range routes {
+		if m := methods[r.Pattern]; m[""] || hasAll(m) {
+			if anyMethod[r.Pattern] {
+				continue
+			}
+
+			anyMethod[r.Pattern] = true
+			r.Method = ""
+		}
+
+		res = append(res, r)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Path != res[j].Path {
+			return res[i].Path < res[j].Path
+		}
+
+		return res[i].Method < res[j].Method
+	})
+
+	return res
+}
+
+func hasAll(methods map[string]bool) bool {
+	for _, method := range allMethods {
+		if !methods[method] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Sync applies regular expressions of route parameters as patterns of path parameter schemas
+// of matching operations in spec and returns routes that have no operation in spec.
+//
+// Spec should be a pointer to a spec value, e.g. *openapi3.Spec or *openapi31.Spec. Route paths match
+// spec paths with different parameter names, e.g. "/users/{id}" matches "/users/{userId}".
+// Patterns are only set for string schemas that have no pattern.
+func Sync(s openapi.SpecSchema, routes []Route) ([]Route, error) {
+	rv := reflect.ValueOf(s)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, fmt.Errorf("spec must be a non-nil pointer, %T given", s)
+	}
+
+	doc, err := internal.ToDocument(s)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, _ := doc["paths"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	byTemplate := make(map[string]string, len(paths))
+	for _, path := range internal.SortedKeys(paths) {
+		byTemplate[template(path)] = path
+	}
+
+	var missing []Route
+
+	for _, r := range routes {
+		path, ok := byTemplate[template(r.Path)]
+		if !ok {
+			missing = append(missing, r)
+
+			continue
+		}
+
+		pi, _ := paths[path].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+		if !syncRoute(r, path, pi) {
+			missing = append(missing, r)
+		}
+	}
+
+	synced := reflect.New(rv.Elem().Type())
+
+	if err := internal.FromDocument(doc, synced.Interface()); err != nil {
+		return nil, fmt.Errorf("loading synced document: %w", err)
+	}
+
+	rv.Elem().Set(synced.Elem())
+
+	return missing, nil
+}
+
+// syncRoute applies parameter patterns to operations of path item, it returns false if route has no operation.
+func syncRoute(r Route, path string, pi map[string]interface{}) bool {
+	names := pathParams(path)
+	specNames := make(map[string]string, len(names))
+
+	for i, name := range pathParams(r.Path) {
+		if i < len(names) {
+			specNames[name] = names[i]
+		}
+	}
+
+	found := false
+
+	for _, method := range allMethods {
+		op, ok := pi[strings.ToLower(method)].(map[string]interface{})
+		if !ok || (r.Method != "" && r.Method != method) {
+			continue
+		}
+
+		found = true
+
+		for name, expr := range r.ParamPatterns {
+			setPattern(pi["parameters"], specNames[name], expr)
+			setPattern(op["parameters"], specNames[name], expr)
+		}
+	}
+
+	return found
+}
+
+// setPattern sets pattern of string schema of a path parameter that has no pattern.
+func setPattern(params interface{}, name, expr string) {
+	items, _ := params.([]interface{}) //nolint:errcheck // Nil is valid.
+
+	for _, item := range items {
+		p, ok := item.(map[string]interface{})
+		if !ok || p["in"] != "path" || p["name"] != name {
+			continue
+		}
+
+		schema, ok := p["schema"].(map[string]interface{})
+		if !ok {
+			schema = map[string]interface{}{"type": "string"}
+			p["schema"] = schema
+		}
+
+		if _, ok := schema["pattern"]; !ok && isString(schema) {
+			schema["pattern"] = expr
+		}
+	}
+}
+
+// isString is true for schemas that may have string type, pattern is ignored for other types.
+func isString(schema map[string]interface{}) bool {
+	switch t := schema["type"].(type) {
+	case nil:
+		return true
+	case string:
+		return t == "string"
+	case []interface{}:
+		for _, v := range t {
+			if v == "string" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+var regexPathParameter = regexp.MustCompile(`{([^}]+)}`)
+
+// template replaces parameter names in path with empty braces.
+func template(path string) string {
+	return regexPathParameter.ReplaceAllString(path, "{}")
+}
+
+func pathParams(path string) []string {
+	var names []string
+
+	for _, m := range regexPathParameter.FindAllStringSubmatch(path, -1) {
+		names = append(names, m[1])
+	}
+
+	return names
+}
@@ -0,0 +1,141 @@
+package routes_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/routes"
+)
+
+func TestNew(t *testing.T) {
+	assert.Equal(t, routes.Route{
+		Method:        http.MethodGet,
+		Pattern:       "/users/{id:[0-9]{1,8}}/files/{name}/*",
+		Path:          "/users/{id}/files/{name}/{*}",
+		ParamPatterns: map[string]string{"id": "^[0-9]{1,8}$"},
+	}, routes.New("get", "/users/{id:[0-9]{1,8}}/files/{name}/*"))
+
+	assert.Equal(t, routes.Route{
+		Method:        http.MethodPost,
+		Pattern:       "/{slug:^[a-z-]+$}",
+		Path:          "/{slug}",
+		ParamPatterns: map[string]string{"slug": "^[a-z-]+$"},
+	}, routes.New(http.MethodPost, "/{slug:^[a-z-]+$}"))
+}
+
+func TestNormalize(t *testing.T) {
+	var rr []routes.Route
+
+	for _, method := range []string{
+		http.MethodConnect, http.MethodDelete, http.MethodGet, http.MethodHead,
+		http.MethodOptions, http.MethodPatch, http.MethodPost, http.MethodPut, http.MethodTrace,
+	} {
+		rr = append(rr, routes.New(method, "/static/*"))
+	}
+
+	rr = append(rr, routes.New(http.MethodPost, "/b"), routes.New(http.MethodGet, "/b"), routes.New("", "/a"))
+
+	var res []string
+	for _, r := range routes.Normalize(rr) {
+		res = append(res, r.String())
+	}
+
+	assert.Equal(t, []string{"* /a", "GET /b", "POST /b", "* /static/*"}, res)
+}
+
+func TestSync(t *testing.T) {
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.1.0
+info: {title: Files, version: 1.0.0}
+paths:
+  /users/{userId}/files/{name}:
+    parameters:
+      - {name: userId, in: path, required: true, schema: {type: integer}}
+    get:
+      parameters:
+        - {name: name, in: path, required: true, schema: {type: string}}
+      responses: {"200": {description: OK}}
+    delete:
+      parameters:
+        - {name: name, in: path, required: true, schema: {type: string, pattern: "^[a-z]+$"}}
+      responses: {"204": {description: Deleted}}
+`)))
+
+	missing, err := routes.Sync(&s, []routes.Route{
+		routes.New(http.MethodGet, "/users/{id:[0-9]+}/files/{file:[a-z.]+}"),
+		routes.New(http.MethodDelete, "/users/{id:[0-9]+}/files/{file:[a-z.]+}"),
+		routes.New(http.MethodPut, "/users/{id:[0-9]+}/files/{file:[a-z.]+}"),
+		routes.New("", "/health"),
+	})
+	require.NoError(t, err)
+
+	var res []string
+	for _, r := range missing {
+		res = append(res, r.String())
+	}
+
+	assert.Equal(t, []string{"PUT /users/{id:[0-9]+}/files/{file:[a-z.]+}", "* /health"}, res)
+
+	assertjson.EqualMarshal(t, []byte(`{
+	  "parameters":[{"name":"userId","in":"path","required":true,"schema":{"type":"integer"}}],
+	  "get":{
+		"parameters":[
+		  {"name":"name","in":"path","required":true,"schema":{"pattern":"^[a-z.]+$","type":"string"}}
+		],
+		"responses":{"200":{"description":"OK"}}
+	  },
+	  "delete":{
+		"parameters":[
+		  {"name":"name","in":"path","required":true,"schema":{"pattern":"^[a-z]+$","type":"string"}}
+		],
+		"responses":{"204":{"description":"Deleted"}}
+	  }
+	}`), s.Paths.MapOfPathItemValues["/users/{userId}/files/{name}"])
+}
+
+func TestSync_openapi3(t *testing.T) {
+	r := openapi3.NewReflector()
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/tags/{tag:[a-z]+}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(struct {
+		Tag string `path:"tag"`
+	}{})
+	require.NoError(t, r.AddOperation(oc))
+
+	missing, err := routes.Sync(r.Spec, []routes.Route{routes.New(http.MethodGet, "/tags/{tag:[a-z]+}")})
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	assertjson.EqualMarshal(t, []byte(`[
+	  {"name":"tag","in":"path","required":true,"schema":{"pattern":"^[a-z]+$","type":"string"}}
+	]`), r.Spec.Paths.MapOfPathItemValues["/tags/{tag}"].MapOfOperationValues["get"].Parameters)
+
+	_, err = routes.Sync((*openapi3.Spec)(nil), nil)
+	assert.EqualError(t, err, "spec must be a non-nil pointer, *openapi3.Spec given")
+}
+
+type unloadableSpec struct {
+	openapi31.Spec
+}
+
+func (*unloadableSpec) UnmarshalJSON([]byte) error {
+	return errors.New("failed")
+}
+
+func TestSync_loadFailed(t *testing.T) {
+	s := unloadableSpec{}
+	s.Info.Title = "Files"
+
+	_, err := routes.Sync(&s, []routes.Route{routes.New(http.MethodGet, "/files/{name:[a-z]+}")})
+	require.EqualError(t, err, "loading synced document: failed")
+	assert.Equal(t, "Files", s.Info.Title)
+}
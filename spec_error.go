@@ -0,0 +1,37 @@
+package openapi
+
+import "strings"
+
+// SpecProblem describes a problem of an OpenAPI document.
+type SpecProblem struct {
+	// Pointer is a JSON Pointer to the problematic location in the document.
+	Pointer string
+
+	// Message describes the problem.
+	Message string
+}
+
+// String implements fmt.Stringer.
+func (p SpecProblem) String() string {
+	if p.Pointer == "" {
+		return p.Message
+	}
+
+	return p.Pointer + ": " + p.Message
+}
+
+// SpecError lists problems of an invalid OpenAPI document.
+type SpecError struct {
+	Problems []SpecProblem
+}
+
+// Error implements error.
+func (e *SpecError) Error() string {
+	problems := make([]string, 0, len(e.Problems))
+
+	for _, p := range e.Problems {
+		problems = append(problems, p.String())
+	}
+
+	return "invalid spec: " + strings.Join(problems, "; ")
+}
@@ -0,0 +1,408 @@
+// Package spechandler serves OpenAPI spec over HTTP in JSON and YAML formats.
+package spechandler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// Options configures handler.
+type Options struct {
+	// RewriteServers replaces scheme and host of spec servers with the ones of incoming request,
+	// taken from X-Forwarded-Proto, X-Forwarded-Host and Host headers, and prepends X-Forwarded-Prefix
+	// to server paths. Spec without servers gets a server of request.
+	//
+	// Forwarded headers are not validated against a list of trusted proxies, so this option should only
+	// be enabled behind a proxy that sets them.
+	RewriteServers bool
+}
+
+// maxRewrites limits number of cached representations of rewritten servers.
+const maxRewrites = 64
+
+// New creates a handler that serves the spec in JSON and YAML formats.
+//
+// Spec is marshaled once, later changes of spec are not served. Format is chosen by extension of request path,
+// so that handler can be mounted at "/openapi.json" and "/openapi.yaml" (or "/openapi.yml"), paths with other
+// extensions get YAML if it is preferred in Accept header and JSON otherwise. Responses have strong ETags,
+// conditional requests with If-None-Match get 304 Not Modified, and gzip encoding is applied when accepted.
+//
+// Spec must be a *openapi3.Spec, *openapi31.Spec or other SpecSchema that implements MarshalYAML.
+func New(s openapi.SpecSchema, options Options) (http.Handler, error) {
+	if _, ok := s.(yamlMarshaler); !ok {
+		return nil, fmt.Errorf("%T does not implement MarshalYAML", s)
+	}
+
+	h := &handler{
+		spec:     s,
+		options:  options,
+		rewrites: map[string]*representations{},
+	}
+
+	var err error
+
+	if h.static, err = newRepresentations(s); err != nil {
+		return nil, err
+	}
+
+	if options.RewriteServers {
+		if h.doc, err = internal.ToDocument(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return h, nil
+}
+
+type yamlMarshaler interface {
+	MarshalYAML() ([]byte, error)
+}
+
+type handler struct {
+	spec    openapi.SpecSchema
+	options Options
+	static  *representations
+
+	// doc is a generic document of spec to rewrite servers.
+	doc map[string]interface{}
+
+	mu       sync.Mutex
+	rewrites map[string]*representations
+}
+
+// representations are marshaled spec in supported formats.
+type representations struct {
+	json entity
+	yaml entity
+}
+
+type entity struct {
+	contentType string
+
+	body []byte
+	etag string
+
+	gzipped     []byte
+	gzippedETag string
+}
+
+func newRepresentations(s openapi.SpecSchema) (*representations, error) {
+	jsonData, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	yamlData, err := s.(yamlMarshaler).MarshalYAML() //nolint:forcetypeassert // Checked in New.
+	if err != nil {
+		return nil, err
+	}
+
+	r := &representations{}
+
+	if r.json, err = newEntity("application/json", jsonData); err != nil {
+		return nil, err
+	}
+
+	if r.yaml, err = newEntity("application/yaml", yamlData); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func newEntity(contentType string, body []byte) (entity, error) {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+
+	if _, err := w.Write(body); err != nil {
+		return entity{}, err
+	}
+
+	if err := w.Close(); err != nil {
+		return entity{}, err
+	}
+
+	sum := sha256.Sum256(body)
+	etag := hex.EncodeToString(sum[:16])
+
+	return entity{
+		contentType: contentType,
+		body:        body,
+		etag:        `"` + etag + `"`,
+		gzipped:     buf.Bytes(),
+		gzippedETag: `"` + etag + `-gzip"`,
+	}, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (h *handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		rw.Header().Set("Allow", "GET, HEAD")
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	reps := h.static
+
+	vary := []string{"Accept-Encoding"}
+
+	if h.options.RewriteServers {
+		vary = append(vary, "Host", "X-Forwarded-Proto", "X-Forwarded-Host", "X-Forwarded-Prefix")
+
+		var err error
+
+		if reps, err = h.rewritten(r); err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	e := reps.json
+
+	switch strings.ToLower(path.Ext(r.URL.Path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		e = reps.yaml
+	default:
+		vary = append(vary, "Accept")
+
+		if prefersYAML(r.Header.Get("Accept")) {
+			e = reps.yaml
+		}
+	}
+
+	body, etag := e.body, e.etag
+
+	if acceptsGzip(r.Header.Get("Accept-Encoding")) {
+		body, etag = e.gzipped, e.gzippedETag
+		rw.Header().Set("Content-Encoding", "gzip")
+	}
+
+	rw.Header().Set("Content-Type", e.contentType)
+	rw.Header().Set("ETag", etag)
+	rw.Header().Set("Vary", strings.Join(vary, ", "))
+
+	if matchesETag(r.Header.Get("If-None-Match"), etag) {
+		rw.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	rw.Header().Set("Content-Length", fmt.Sprint(len(body)))
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	_, _ = rw.Write(body) //nolint:errcheck // Client errors are not actionable.
+}
+
+var (
+	regexHost   = regexp.MustCompile(`^[A-Za-z0-9.\-]+(:[0-9]+)?$|^\[[0-9A-Fa-f:.]+\](:[0-9]+)?$`)
+	regexPrefix = regexp.MustCompile(`^(/[A-Za-z0-9._~\-]+)*$`)
+)
+
+// rewritten returns representations of spec with servers of request.
+func (h *handler) rewritten(r *http.Request) (*representations, error) {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	if p := firstValue(r.Header.Get("X-Forwarded-Proto")); p == "http" || p == "https" {
+		scheme = p
+	}
+
+	host := r.Host
+	if fh := firstValue(r.Header.Get("X-Forwarded-Host")); fh != "" {
+		host = fh
+	}
+
+	prefix := strings.TrimSuffix(firstValue(r.Header.Get("X-Forwarded-Prefix")), "/")
+
+	// Spec servers are not rewritten with invalid host.
+	if !regexHost.MatchString(host) {
+		return h.static, nil
+	}
+
+	if !regexPrefix.MatchString(prefix) {
+		prefix = ""
+	}
+
+	base := scheme + "://" + host + prefix
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if reps, ok := h.rewrites[base]; ok {
+		return reps, nil
+	}
+
+	reps, err := h.withServers(base)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(h.rewrites) < maxRewrites {
+		h.rewrites[base] = reps
+	}
+
+	return reps, nil
+}
+
+// withServers marshals spec with servers located at base URL.
+func (h *handler) withServers(base string) (*representations, error) {
+	doc := make(map[string]interface{}, len(h.doc))
+
+	for k, v := range h.doc {
+		doc[k] = v
+	}
+
+	servers, _ := h.doc["servers"].([]interface{}) //nolint:errcheck // Nil is valid.
+	if len(servers) == 0 {
+		servers = []interface{}{map[string]interface{}{"url": "/"}}
+	}
+
+	rewritten := make([]interface{}, 0, len(servers))
+
+	for _, srv := range servers {
+		s, ok := srv.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		u, _ := s["url"].(string) //nolint:errcheck // Empty is valid.
+
+		cp := make(map[string]interface{}, len(s))
+		for k, v := range s {
+			cp[k] = v
+		}
+
+		cp["url"] = base + serverPath(u)
+		rewritten = append(rewritten, cp)
+	}
+
+	doc["servers"] = rewritten
+
+	s := reflect.New(reflect.TypeOf(h.spec).Elem()).Interface()
+
+	if err := internal.FromDocument(doc, s); err != nil {
+		return nil, err
+	}
+
+	spec, ok := s.(openapi.SpecSchema)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a spec", s)
+	}
+
+	return newRepresentations(spec)
+}
+
+// serverPath returns path of server URL without trailing slash, e.g. "/v1" for "https://example.com/v1/".
+func serverPath(serverURL string) string {
+	p := serverURL
+
+	// URL is not parsed to keep server variables, e.g. "{scheme}://{host}/{version}".
+	if i := strings.Index(p, "//"); i >= 0 && !strings.Contains(p[:i], "/") {
+		p = p[i+2:]
+
+		if j := strings.Index(p, "/"); j >= 0 {
+			p = p[j:]
+		} else {
+			p = ""
+		}
+	}
+
+	if p != "" && !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+
+	return strings.TrimSuffix(p, "/")
+}
+
+// firstValue returns first item of comma-separated header value.
+func firstValue(v string) string {
+	return strings.TrimSpace(strings.Split(v, ",")[0])
+}
+
+// prefersYAML is true if Accept header has YAML media type with higher quality than JSON.
+func prefersYAML(accept string) bool {
+	yamlQ, jsonQ := -1.0, -1.0
+
+	for _, item := range strings.Split(accept, ",") {
+		mediaType, q := parseQuality(item)
+
+		switch mediaType {
+		case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+			if q > yamlQ {
+				yamlQ = q
+			}
+		case "application/json":
+			if q > jsonQ {
+				jsonQ = q
+			}
+		}
+	}
+
+	return yamlQ > 0 && yamlQ > jsonQ
+}
+
+// acceptsGzip is true if Accept-Encoding header allows gzip.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, item := range strings.Split(acceptEncoding, ",") {
+		if coding, q := parseQuality(item); (coding == "gzip" || coding == "*") && q > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseQuality parses item of Accept-like header into lowercase value and quality.
+func parseQuality(item string) (string, float64) {
+	parts := strings.Split(item, ";")
+	value := strings.ToLower(strings.TrimSpace(parts[0]))
+	q := 1.0
+
+	for _, p := range parts[1:] {
+		p = strings.TrimSpace(p)
+		if !strings.HasPrefix(p, "q=") {
+			continue
+		}
+
+		if _, err := fmt.Sscanf(p[2:], "%g", &q); err != nil {
+			q = 0
+		}
+	}
+
+	return value, q
+}
+
+// matchesETag is true if If-None-Match header has etag or "*".
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, item := range strings.Split(ifNoneMatch, ",") {
+		item = strings.TrimSpace(item)
+
+		if item == "*" || strings.TrimPrefix(item, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,179 @@
+package spechandler_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/spechandler"
+)
+
+func newSpec(t *testing.T) *openapi31.Spec {
+	t.Helper()
+
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.1.0
+info: {title: Pets, version: 1.0.0}
+servers:
+  - {url: "https://api.example.com/v1/", description: Production}
+  - {url: "{scheme}://{host}/{version}", variables: {scheme: {default: https}, host: {default: localhost}, version: {default: v2}}}
+paths: {}
+`)))
+
+	return &s
+}
+
+func serve(h http.Handler, method, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	return rw
+}
+
+func TestNew(t *testing.T) {
+	s := newSpec(t)
+
+	h, err := spechandler.New(s, spechandler.Options{})
+	require.NoError(t, err)
+
+	jsonData, err := s.MarshalJSON()
+	require.NoError(t, err)
+
+	yamlData, err := s.MarshalYAML()
+	require.NoError(t, err)
+
+	rw := serve(h, http.MethodGet, "/openapi.json", nil)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept-Encoding", rw.Header().Get("Vary"))
+	assert.Equal(t, string(jsonData), rw.Body.String())
+
+	etag := rw.Header().Get("ETag")
+	assert.Len(t, etag, 34)
+
+	rw = serve(h, http.MethodGet, "/openapi.yaml", nil)
+	assert.Equal(t, "application/yaml", rw.Header().Get("Content-Type"))
+	assert.Equal(t, string(yamlData), rw.Body.String())
+	assert.NotEqual(t, etag, rw.Header().Get("ETag"))
+
+	// Content negotiation.
+	rw = serve(h, http.MethodGet, "/openapi", map[string]string{"Accept": "application/json;q=0.5, application/yaml"})
+	assert.Equal(t, "application/yaml", rw.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept-Encoding, Accept", rw.Header().Get("Vary"))
+
+	rw = serve(h, http.MethodGet, "/openapi", map[string]string{"Accept": "application/json, application/yaml;q=0.9"})
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+
+	rw = serve(h, http.MethodGet, "/openapi", nil)
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+
+	// Conditional request.
+	rw = serve(h, http.MethodGet, "/openapi.json", map[string]string{"If-None-Match": `"foo", ` + etag})
+	assert.Equal(t, http.StatusNotModified, rw.Code)
+	assert.Empty(t, rw.Body.String())
+
+	// Gzip.
+	rw = serve(h, http.MethodGet, "/openapi.json", map[string]string{"Accept-Encoding": "br;q=1, gzip;q=0.8"})
+	assert.Equal(t, "gzip", rw.Header().Get("Content-Encoding"))
+	assert.Equal(t, etag[:33]+`-gzip"`, rw.Header().Get("ETag"))
+	assert.Equal(t, string(jsonData), gunzip(t, rw.Body.Bytes()))
+
+	rw = serve(h, http.MethodGet, "/openapi.json", map[string]string{"Accept-Encoding": "gzip;q=0"})
+	assert.Empty(t, rw.Header().Get("Content-Encoding"))
+
+	// Head and other methods.
+	rw = serve(h, http.MethodHead, "/openapi.json", nil)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Empty(t, rw.Body.String())
+	assert.Equal(t, strconv.Itoa(len(jsonData)), rw.Header().Get("Content-Length"))
+
+	rw = serve(h, http.MethodPost, "/openapi.json", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+	assert.Equal(t, "GET, HEAD", rw.Header().Get("Allow"))
+}
+
+func gunzip(t *testing.T, data []byte) string {
+	t.Helper()
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	res, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	return string(res)
+}
+
+func TestNew_rewriteServers(t *testing.T) {
+	h, err := spechandler.New(newSpec(t), spechandler.Options{RewriteServers: true})
+	require.NoError(t, err)
+
+	rw := serve(h, http.MethodGet, "http://docs.local:8080/openapi.yaml", map[string]string{
+		"X-Forwarded-Proto":  "https, http",
+		"X-Forwarded-Host":   "gw.example.org",
+		"X-Forwarded-Prefix": "/pets/",
+	})
+	assert.Equal(t, "Accept-Encoding, Host, X-Forwarded-Proto, X-Forwarded-Host, X-Forwarded-Prefix",
+		rw.Header().Get("Vary"))
+	assert.Equal(t, `openapi: 3.1.0
+info:
+  title: Pets
+  version: 1.0.0
+servers:
+- description: Production
+  url: https://gw.example.org/pets/v1
+- url: https://gw.example.org/pets/{version}
+  variables:
+    host:
+      default: localhost
+    scheme:
+      default: https
+    version:
+      default: v2
+paths: {}
+`, rw.Body.String())
+
+	etag := rw.Header().Get("ETag")
+
+	// Host header is used without forwarded headers.
+	rw = serve(h, http.MethodGet, "http://docs.local:8080/openapi.json", nil)
+	assert.Contains(t, rw.Body.String(), `"servers":[{"url":"http://docs.local:8080/v1","description":"Production"}`)
+
+	// Invalid host is not used.
+	rw = serve(h, http.MethodGet, "/openapi.json", map[string]string{"X-Forwarded-Host": "evil\"host"})
+	assert.Contains(t, rw.Body.String(), `"servers":[{"url":"https://api.example.com/v1/","description":"Production"}`)
+
+	// Cached representation is reused.
+	rw = serve(h, http.MethodGet, "http://docs.local:8080/openapi.yaml", map[string]string{
+		"X-Forwarded-Proto":  "https",
+		"X-Forwarded-Host":   "gw.example.org",
+		"X-Forwarded-Prefix": "/pets",
+	})
+	assert.Equal(t, etag, rw.Header().Get("ETag"))
+}
+
+func TestNew_openapi3(t *testing.T) {
+	s := openapi3.Spec{Openapi: "3.0.3"}
+	s.Info.Title = "Pets"
+
+	h, err := spechandler.New(&s, spechandler.Options{RewriteServers: true})
+	require.NoError(t, err)
+
+	rw := serve(h, http.MethodGet, "https://example.com/openapi.json", nil)
+	assert.Equal(t, `{"openapi":"3.0.3","info":{"title":"Pets","version":""},"servers":[{"url":"https://example.com"}],"paths":{}}`,
+		rw.Body.String())
+}
@@ -0,0 +1,924 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go/convert"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// Export converts OpenAPI 3.0 spec to Swagger 2.0 document in JSON.
+//
+// Request bodies become `in: body` or `in: formData` parameters, media types of request bodies and responses
+// become `consumes` and `produces`, `style` and `explode` of array parameters become `collectionFormat`,
+// schemas become definitions (except those only used by form bodies) and security schemes become security definitions.
+//
+// Features that can not be represented in Swagger 2.0 (e.g. `oneOf`, cookie parameters, callbacks or links)
+// are removed and reported as warnings.
+func Export(s *openapi3.Spec) ([]byte, []convert.Warning, error) {
+	src, err := internal.ToDocument(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ex := exporter{src: src}
+	doc := ex.document()
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, ex.warnings, err
+	}
+
+	return data, ex.warnings, nil
+}
+
+type exporter struct {
+	src      map[string]interface{}
+	warnings []convert.Warning
+
+	// formRefs are references to definitions of form request body schemas.
+	formRefs []interface{}
+}
+
+func (ex *exporter) warn(ptr, msg string) {
+	ex.warnings = append(ex.warnings, convert.Warning{Pointer: ptr, Message: msg})
+}
+
+func (ex *exporter) resolve(v interface{}) (map[string]interface{}, string) {
+	ref, ok := refOf(v)
+	if !ok {
+		m, _ := v.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+		return m, ""
+	}
+
+	r, err := internal.ResolveRef(ex.src, ref)
+	if err != nil {
+		return nil, ref
+	}
+
+	m, _ := r.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	return m, ref
+}
+
+func (ex *exporter) document() map[string]interface{} {
+	doc := map[string]interface{}{
+		"swagger": "2.0",
+	}
+
+	for _, k := range []string{"info", "tags", "externalDocs", "security"} {
+		if v, ok := ex.src[k]; ok {
+			doc[k] = v
+		}
+	}
+
+	copyExtensions(doc, ex.src)
+	ex.servers(doc)
+
+	components, _ := ex.src["components"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	if schemas, ok := components["schemas"].(map[string]interface{}); ok {
+		defs := make(map[string]interface{}, len(schemas))
+
+		for _, name := range internal.SortedKeys(schemas) {
+			defs[name] = ex.schema("/components/schemas/"+internal.EscapeJSONPointer(name), schemas[name])
+		}
+
+		doc["definitions"] = defs
+	}
+
+	params := map[string]interface{}{}
+
+	if cp, ok := components["parameters"].(map[string]interface{}); ok {
+		for _, name := range internal.SortedKeys(cp) {
+			ptr := "/components/parameters/" + internal.EscapeJSONPointer(name)
+
+			p, _ := cp[name].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+			if rp := ex.parameter(ptr, p); rp != nil {
+				params[name] = rp
+			}
+		}
+	}
+
+	if rbs, ok := components["requestBodies"].(map[string]interface{}); ok {
+		for _, name := range internal.SortedKeys(rbs) {
+			ptr := "/components/requestBodies/" + internal.EscapeJSONPointer(name)
+
+			rb, _ := rbs[name].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+			if isForm(rb) {
+				continue // Form bodies are expanded to formData parameters of operations.
+			}
+
+			body, _ := ex.requestBody(ptr, rb)
+			body["name"] = name
+			params[name] = body
+		}
+	}
+
+	if len(params) > 0 {
+		doc["parameters"] = params
+	}
+
+	if cr, ok := components["responses"].(map[string]interface{}); ok {
+		responses := make(map[string]interface{}, len(cr))
+
+		for _, name := range internal.SortedKeys(cr) {
+			r, _ := ex.response("/components/responses/"+internal.EscapeJSONPointer(name), cr[name])
+			responses[name] = r
+		}
+
+		doc["responses"] = responses
+	}
+
+	if ss, ok := components["securitySchemes"].(map[string]interface{}); ok {
+		defs := map[string]interface{}{}
+
+		for _, name := range internal.SortedKeys(ss) {
+			if sd := ex.securityScheme("/components/securitySchemes/"+internal.EscapeJSONPointer(name), ss[name]); sd != nil {
+				defs[name] = sd
+			}
+		}
+
+		doc["securityDefinitions"] = defs
+	}
+
+	for _, k := range []string{"examples", "headers", "links", "callbacks"} {
+		if _, ok := components[k]; ok {
+			ex.warn("/components/"+k, "components are inlined where referenced")
+		}
+	}
+
+	paths := map[string]interface{}{}
+
+	if srcPaths, ok := ex.src["paths"].(map[string]interface{}); ok {
+		for _, p := range internal.SortedKeys(srcPaths) {
+			pi, ok := srcPaths[p].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if strings.HasPrefix(p, "x-") {
+				paths[p] = pi
+
+				continue
+			}
+
+			paths[p] = ex.pathItem("/paths/"+internal.EscapeJSONPointer(p), pi)
+		}
+	}
+
+	doc["paths"] = paths
+
+	ex.pruneFormDefinitions(doc)
+
+	return doc
+}
+
+// pruneFormDefinitions removes definitions that are only used by form request bodies,
+// as such bodies are expanded to formData parameters, other unused definitions are kept.
+func (ex *exporter) pruneFormDefinitions(doc map[string]interface{}) {
+	defs, ok := doc["definitions"].(map[string]interface{})
+	if !ok || len(ex.formRefs) == 0 {
+		return
+	}
+
+	roots := make(map[string]interface{}, len(doc))
+
+	for k, v := range doc {
+		if k != "definitions" {
+			roots[k] = v
+		}
+	}
+
+	used := reachableDefinitions(defs, roots)
+
+	for name := range reachableDefinitions(defs, ex.formRefs) {
+		if !used[name] {
+			delete(defs, name)
+		}
+	}
+}
+
+// reachableDefinitions returns names of definitions referenced directly or transitively from v.
+func reachableDefinitions(defs map[string]interface{}, v interface{}) map[string]bool {
+	res := map[string]bool{}
+
+	var queue []string
+
+	use := func(ref string) string {
+		if !strings.HasPrefix(ref, "#/definitions/") {
+			return ref
+		}
+
+		name := internal.UnescapeJSONPointer(strings.SplitN(strings.TrimPrefix(ref, "#/definitions/"), "/", 2)[0])
+		if !res[name] {
+			res[name] = true
+			queue = append(queue, name)
+		}
+
+		return ref
+	}
+
+	internal.RewriteRefs(v, use)
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		internal.RewriteRefs(defs[name], use)
+	}
+
+	return res
+}
+
+var regexServerVariable = regexp.MustCompile(`{([^}]+)}`)
+
+// servers sets host, basePath and schemes by servers with the same host and base path as the first server.
+func (ex *exporter) servers(doc map[string]interface{}) {
+	servers, _ := ex.src["servers"].([]interface{}) //nolint:errcheck // Nil is valid.
+
+	var (
+		host, basePath string
+		schemes        []interface{}
+	)
+
+	for i, s := range servers {
+		ptr := "/servers/" + strconv.Itoa(i)
+		srv, _ := s.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+		raw := str(srv, "url")
+		vars, _ := srv["variables"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+		if len(vars) > 0 {
+			ex.warn(ptr+"/variables", "server variables replaced with default values")
+
+			raw = regexServerVariable.ReplaceAllStringFunc(raw, func(v string) string {
+				vr, _ := vars[v[1:len(v)-1]].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+				return str(vr, "default")
+			})
+		}
+
+		u, err := url.Parse(raw)
+		if err != nil {
+			ex.warn(ptr+"/url", "invalid server URL removed")
+
+			continue
+		}
+
+		if i == 0 {
+			host, basePath = u.Host, strings.TrimSuffix(u.Path, "/")
+		} else if u.Host != host || strings.TrimSuffix(u.Path, "/") != basePath {
+			ex.warn(ptr, "server removed, only one host and base path is supported")
+
+			continue
+		}
+
+		if u.Scheme != "" {
+			schemes = append(schemes, u.Scheme)
+		}
+	}
+
+	if host != "" {
+		doc["host"] = host
+	}
+
+	if basePath != "" {
+		doc["basePath"] = basePath
+	}
+
+	if len(schemes) > 0 {
+		doc["schemes"] = schemes
+	}
+}
+
+var pathItemUnsupported = []string{"trace", "servers"}
+
+func (ex *exporter) pathItem(ptr string, pi map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+
+	copyExtensions(res, pi)
+
+	for _, k := range pathItemUnsupported {
+		if _, ok := pi[k]; ok {
+			ex.warn(ptr+"/"+k, "unsupported property removed: "+k)
+		}
+	}
+
+	if params := ex.parameters(ptr+"/parameters", pi["parameters"]); len(params) > 0 {
+		res["parameters"] = params
+	}
+
+	for _, method := range pathItemMethods {
+		op, ok := pi[method].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		res[method] = ex.operation(ptr+"/"+method, op)
+	}
+
+	return res
+}
+
+var operationUnsupported = []string{"callbacks", "servers"}
+
+func (ex *exporter) operation(ptr string, op map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+
+	for _, k := range []string{"tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security"} {
+		if v, ok := op[k]; ok {
+			res[k] = v
+		}
+	}
+
+	copyExtensions(res, op)
+
+	for _, k := range operationUnsupported {
+		if _, ok := op[k]; ok {
+			ex.warn(ptr+"/"+k, "unsupported property removed: "+k)
+		}
+	}
+
+	params := ex.parameters(ptr+"/parameters", op["parameters"])
+
+	if rb, ok := op["requestBody"]; ok {
+		rbPtr := ptr + "/requestBody"
+		body, ref := ex.resolve(rb)
+
+		switch {
+		case body == nil:
+			ex.warn(rbPtr, "unresolved request body removed")
+		case isForm(body):
+			consumes, formData := ex.formParameters(rbPtr, body)
+			res["consumes"] = consumes
+			params = append(params, formData...)
+		case ref != "":
+			res["consumes"] = contentTypes(body)
+			params = append(params, map[string]interface{}{"$ref": "#/parameters/" + strings.TrimPrefix(ref, "#/components/requestBodies/")})
+		default:
+			bp, consumes := ex.requestBody(rbPtr, body)
+			bp["name"] = "body"
+			res["consumes"] = consumes
+			params = append(params, bp)
+		}
+	}
+
+	if len(params) > 0 {
+		res["parameters"] = params
+	}
+
+	responses := map[string]interface{}{}
+	produces := map[string]bool{}
+
+	if srcResponses, ok := op["responses"].(map[string]interface{}); ok {
+		for _, code := range internal.SortedKeys(srcResponses) {
+			if strings.HasPrefix(code, "x-") {
+				responses[code] = srcResponses[code]
+
+				continue
+			}
+
+			r, types := ex.response(ptr+"/responses/"+internal.EscapeJSONPointer(code), srcResponses[code])
+			responses[code] = r
+
+			for _, ct := range types {
+				produces[ct] = true
+			}
+		}
+	}
+
+	res["responses"] = responses
+
+	if len(produces) > 0 {
+		list := make([]string, 0, len(produces))
+		for ct := range produces {
+			list = append(list, ct)
+		}
+
+		sort.Strings(list)
+		res["produces"] = list
+	}
+
+	return res
+}
+
+func (ex *exporter) parameters(ptr string, v interface{}) []interface{} {
+	list, _ := v.([]interface{}) //nolint:errcheck // Nil is valid.
+
+	var res []interface{}
+
+	for i, p := range list {
+		pptr := ptr + "/" + strconv.Itoa(i)
+
+		if ref, ok := refOf(p); ok {
+			if rp, _ := ex.resolve(p); rp != nil && rp["in"] == "cookie" {
+				ex.warn(pptr, "cookie parameter removed")
+
+				continue
+			}
+
+			res = append(res, map[string]interface{}{"$ref": "#/parameters/" + strings.TrimPrefix(ref, "#/components/parameters/")})
+
+			continue
+		}
+
+		pm, _ := p.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+		if rp := ex.parameter(pptr, pm); rp != nil {
+			res = append(res, rp)
+		}
+	}
+
+	return res
+}
+
+// parameter converts non-body parameter, it returns nil for parameters that can not be represented.
+func (ex *exporter) parameter(ptr string, p map[string]interface{}) map[string]interface{} {
+	in := str(p, "in")
+
+	if in == "cookie" {
+		ex.warn(ptr, "cookie parameter removed")
+
+		return nil
+	}
+
+	if _, ok := p["content"]; ok {
+		ex.warn(ptr+"/content", "parameter with content removed")
+
+		return nil
+	}
+
+	res := map[string]interface{}{}
+
+	for _, k := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+		if v, ok := p[k]; ok {
+			res[k] = v
+		}
+	}
+
+	copyExtensions(res, p)
+
+	schema := ex.simpleSchema(ptr+"/schema", p["schema"], res)
+
+	if schema["type"] == "array" {
+		style, _ := p["style"].(string) //nolint:errcheck // Empty is valid.
+		explode, hasExplode := p["explode"].(bool)
+
+		if style == "" {
+			style = "simple"
+			if in == "query" {
+				style = "form"
+			}
+		}
+
+		if !hasExplode {
+			explode = style == "form"
+		}
+
+		if f := ex.collectionFormat(ptr, in, style, explode); f != "" {
+			res["collectionFormat"] = f
+		}
+	}
+
+	return res
+}
+
+// collectionFormat returns Swagger 2.0 collection format for style and explode of an array parameter.
+func (ex *exporter) collectionFormat(ptr, in, style string, explode bool) string {
+	isQuery := in == "query" || in == "formData"
+
+	switch {
+	case style == "form" && explode && isQuery:
+		return "multi"
+	case style == "form" && !explode, style == "simple" && !explode:
+		return "csv"
+	case style == "spaceDelimited" && !explode:
+		return "ssv"
+	case style == "pipeDelimited" && !explode:
+		return "pipes"
+	}
+
+	ex.warn(ptr+"/style", "unsupported style "+strconv.Quote(style)+" with explode "+strconv.FormatBool(explode)+
+		" in "+in+", csv is used")
+
+	return "csv"
+}
+
+// simpleSchema copies keywords of a primitive or array schema into a parameter, a header or an items object.
+func (ex *exporter) simpleSchema(ptr string, v interface{}, dst map[string]interface{}) map[string]interface{} {
+	s, ref := ex.resolve(v)
+	if s == nil {
+		ex.warn(ptr, "missing schema, string is used")
+
+		dst["type"] = "string"
+
+		return dst
+	}
+
+	if ref != "" {
+		ptr = strings.TrimPrefix(ref, "#")
+	}
+
+	for _, k := range simpleSchemaKeywords {
+		if v, ok := s[k]; ok {
+			dst[k] = v
+		}
+	}
+
+	switch dst["type"] {
+	case "object", nil:
+		ex.warn(ptr, "complex schema is not supported, string is used")
+
+		dst["type"] = "string"
+	case "array":
+		dst["items"] = ex.simpleSchema(ptr+"/items", s["items"], map[string]interface{}{})
+	}
+
+	return dst
+}
+
+// schema converts OpenAPI 3.0 schema to Swagger 2.0 schema.
+func (ex *exporter) schema(ptr string, v interface{}) interface{} {
+	s, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	res := make(map[string]interface{}, len(s))
+
+	for _, k := range internal.SortedKeys(s) {
+		v := s[k]
+		kptr := ptr + "/" + internal.EscapeJSONPointer(k)
+
+		switch k {
+		case "$ref":
+			if ref, ok := v.(string); ok && strings.HasPrefix(ref, "#/components/schemas/") {
+				v = "#/definitions/" + strings.TrimPrefix(ref, "#/components/schemas/")
+			}
+		case "nullable":
+			k = "x-nullable"
+		case "discriminator":
+			d, _ := v.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+			if _, ok := d["mapping"]; ok {
+				ex.warn(kptr+"/mapping", "unsupported property removed: mapping")
+			}
+
+			v = d["propertyName"]
+		case "oneOf", "anyOf", "not", "writeOnly", "deprecated":
+			ex.warn(kptr, "unsupported property removed: "+k)
+
+			continue
+		case "properties":
+			if props, ok := v.(map[string]interface{}); ok {
+				rp := make(map[string]interface{}, len(props))
+
+				for _, name := range internal.SortedKeys(props) {
+					rp[name] = ex.schema(kptr+"/"+internal.EscapeJSONPointer(name), props[name])
+				}
+
+				v = rp
+			}
+		case "items", "additionalProperties":
+			v = ex.schema(kptr, v)
+		case "allOf":
+			if list, ok := v.([]interface{}); ok {
+				rl := make([]interface{}, len(list))
+
+				for i, item := range list {
+					rl[i] = ex.schema(kptr+"/"+strconv.Itoa(i), item)
+				}
+
+				v = rl
+			}
+		}
+
+		res[k] = v
+	}
+
+	return res
+}
+
+// requestBody converts request body with non-form content to body parameter without a name.
+func (ex *exporter) requestBody(ptr string, rb map[string]interface{}) (map[string]interface{}, []string) {
+	res := map[string]interface{}{"in": "body"}
+
+	for _, k := range []string{"description", "required"} {
+		if v, ok := rb[k]; ok {
+			res[k] = v
+		}
+	}
+
+	copyExtensions(res, rb)
+
+	types := contentTypes(rb)
+	content, _ := rb["content"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	if len(types) > 0 {
+		mt, _ := content[types[0]].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+		res["schema"] = ex.schema(ptr+"/content/"+internal.EscapeJSONPointer(types[0])+"/schema", mt["schema"])
+
+		ex.sameSchemas(ptr+"/content", content, types)
+	}
+
+	if res["schema"] == nil {
+		res["schema"] = map[string]interface{}{}
+	}
+
+	return res, types
+}
+
+// sameSchemas warns about media types with schemas different from the first one.
+func (ex *exporter) sameSchemas(ptr string, content map[string]interface{}, types []string) {
+	first, _ := content[types[0]].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+	fj, _ := json.Marshal(first["schema"])                 //nolint:errcheck // Generic value.
+
+	for _, ct := range types[1:] {
+		mt, _ := content[ct].(map[string]interface{})                    //nolint:errcheck // Nil is valid.
+		if j, _ := json.Marshal(mt["schema"]); string(j) != string(fj) { //nolint:errcheck // Generic value.
+			ex.warn(ptr+"/"+internal.EscapeJSONPointer(ct)+"/schema", "schema differs from "+types[0]+", ignored")
+		}
+	}
+}
+
+// formParameters converts properties of form request body schema to formData parameters.
+func (ex *exporter) formParameters(ptr string, rb map[string]interface{}) ([]string, []interface{}) {
+	types := contentTypes(rb)
+	content, _ := rb["content"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	for _, ct := range types {
+		if ct != mimeForm && ct != mimeMultipart {
+			ex.warn(ptr+"/content/"+internal.EscapeJSONPointer(ct), "media type is not supported together with form data")
+		}
+	}
+
+	ct := mimeForm
+	if _, ok := content[mimeMultipart]; ok {
+		ct = mimeMultipart
+	}
+
+	mt, _ := content[ct].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+	mptr := ptr + "/content/" + internal.EscapeJSONPointer(ct)
+	schema, ref := ex.resolve(mt["schema"])
+
+	internal.RewriteRefs(mt["schema"], func(ref string) string {
+		if strings.HasPrefix(ref, "#/components/schemas/") {
+			ex.formRefs = append(ex.formRefs, map[string]interface{}{
+				"$ref": "#/definitions/" + strings.TrimPrefix(ref, "#/components/schemas/"),
+			})
+		}
+
+		return ref
+	})
+
+	sptr := mptr + "/schema"
+	if ref != "" {
+		sptr = strings.TrimPrefix(ref, "#")
+	}
+
+	props, _ := schema["properties"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+	encoding, _ := mt["encoding"].(map[string]interface{})    //nolint:errcheck // Nil is valid.
+
+	required := map[string]bool{}
+	for _, r := range stringList(schema["required"]) {
+		required[r] = true
+	}
+
+	params := make([]interface{}, 0, len(props))
+
+	for _, name := range internal.SortedKeys(props) {
+		pptr := sptr + "/properties/" + internal.EscapeJSONPointer(name)
+		p := map[string]interface{}{"name": name, "in": "formData"}
+
+		if required[name] {
+			p["required"] = true
+		}
+
+		ps, _ := ex.resolve(props[name])
+		if d, ok := ps["description"]; ok {
+			p["description"] = d
+		}
+
+		if isFile(ps) {
+			p["type"] = "file"
+			params = append(params, p)
+
+			continue
+		}
+
+		if items, _ := ex.resolve(ps["items"]); ps["type"] == "array" && isFile(items) {
+			ex.warn(pptr, "array of files is not supported, single file is used")
+
+			p["type"] = "file"
+			params = append(params, p)
+
+			continue
+		}
+
+		ex.simpleSchema(pptr, props[name], p)
+
+		if p["type"] == "array" {
+			enc, _ := encoding[name].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+			style, _ := enc["style"].(string)                 //nolint:errcheck // Empty is valid.
+			explode, hasExplode := enc["explode"].(bool)
+
+			if style == "" {
+				style = "form"
+			}
+
+			if !hasExplode {
+				explode = style == "form"
+			}
+
+			p["collectionFormat"] = ex.collectionFormat(mptr+"/encoding/"+internal.EscapeJSONPointer(name), "formData", style, explode)
+		}
+
+		params = append(params, p)
+	}
+
+	return []string{ct}, params
+}
+
+func (ex *exporter) response(ptr string, v interface{}) (interface{}, []string) {
+	if ref, ok := refOf(v); ok {
+		r, _ := ex.resolve(v)
+
+		return map[string]interface{}{"$ref": "#/responses/" + strings.TrimPrefix(ref, "#/components/responses/")}, contentTypes(r)
+	}
+
+	r, _ := v.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+	res := map[string]interface{}{"description": r["description"]}
+
+	copyExtensions(res, r)
+
+	if _, ok := r["links"]; ok {
+		ex.warn(ptr+"/links", "unsupported property removed: links")
+	}
+
+	if headers, ok := r["headers"].(map[string]interface{}); ok {
+		rh := make(map[string]interface{}, len(headers))
+
+		for _, name := range internal.SortedKeys(headers) {
+			hptr := ptr + "/headers/" + internal.EscapeJSONPointer(name)
+			h, _ := ex.resolve(headers[name])
+			hr := map[string]interface{}{}
+
+			if d, ok := h["description"]; ok {
+				hr["description"] = d
+			}
+
+			ex.simpleSchema(hptr+"/schema", h["schema"], hr)
+
+			rh[name] = hr
+		}
+
+		res["headers"] = rh
+	}
+
+	types := contentTypes(r)
+	if len(types) == 0 {
+		return res, nil
+	}
+
+	content, _ := r["content"].(map[string]interface{})    //nolint:errcheck // Nil is valid.
+	first, _ := content[types[0]].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	if s, ok := first["schema"]; ok {
+		res["schema"] = ex.schema(ptr+"/content/"+internal.EscapeJSONPointer(types[0])+"/schema", s)
+		ex.sameSchemas(ptr+"/content", content, types)
+	}
+
+	examples := map[string]interface{}{}
+
+	for _, ct := range types {
+		mt, _ := content[ct].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+		if e, ok := mt["example"]; ok {
+			examples[ct] = e
+		}
+
+		if _, ok := mt["examples"]; ok {
+			ex.warn(ptr+"/content/"+internal.EscapeJSONPointer(ct)+"/examples", "unsupported property removed: examples")
+		}
+	}
+
+	if len(examples) > 0 {
+		res["examples"] = examples
+	}
+
+	return res, types
+}
+
+func (ex *exporter) securityScheme(ptr string, v interface{}) map[string]interface{} {
+	ss, _ := ex.resolve(v)
+	res := map[string]interface{}{}
+
+	if d, ok := ss["description"]; ok {
+		res["description"] = d
+	}
+
+	copyExtensions(res, ss)
+
+	switch ss["type"] {
+	case "apiKey":
+		if ss["in"] == "cookie" {
+			ex.warn(ptr+"/in", "cookie API key removed")
+
+			return nil
+		}
+
+		res["type"], res["name"], res["in"] = "apiKey", ss["name"], ss["in"]
+	case "http":
+		switch strings.ToLower(str(ss, "scheme")) {
+		case "basic":
+			res["type"] = "basic"
+		case "bearer":
+			ex.warn(ptr+"/scheme", "bearer scheme is represented as API key in Authorization header")
+
+			res["type"], res["name"], res["in"] = "apiKey", "Authorization", "header"
+		default:
+			ex.warn(ptr+"/scheme", "unsupported http scheme removed: "+str(ss, "scheme"))
+
+			return nil
+		}
+	case "oauth2":
+		return ex.oauth2(ptr, ss, res)
+	default:
+		ex.warn(ptr+"/type", "unsupported security scheme type removed: "+fmt.Sprint(ss["type"]))
+
+		return nil
+	}
+
+	return res
+}
+
+var oauth2Flows = []struct {
+	name, flow string
+}{
+	{name: "authorizationCode", flow: "accessCode"},
+	{name: "implicit", flow: "implicit"},
+	{name: "password", flow: "password"},
+	{name: "clientCredentials", flow: "application"},
+}
+
+func (ex *exporter) oauth2(ptr string, ss, res map[string]interface{}) map[string]interface{} {
+	flows, _ := ss["flows"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	res["type"] = "oauth2"
+	found := false
+
+	for _, f := range oauth2Flows {
+		flow, ok := flows[f.name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if found {
+			ex.warn(ptr+"/flows/"+f.name, "only one oauth2 flow is supported, flow removed")
+
+			continue
+		}
+
+		found = true
+		res["flow"] = f.flow
+
+		for _, k := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+			if v, ok := flow[k]; ok {
+				res[k] = v
+			}
+		}
+	}
+
+	if !found {
+		ex.warn(ptr+"/flows", "missing oauth2 flow, security scheme removed")
+
+		return nil
+	}
+
+	return res
+}
+
+// contentTypes returns sorted media types of a request body or a response.
+func contentTypes(v map[string]interface{}) []string {
+	content, _ := v["content"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	return internal.SortedKeys(content)
+}
+
+func isForm(rb map[string]interface{}) bool {
+	content, _ := rb["content"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	_, form := content[mimeForm]
+	_, multipart := content[mimeMultipart]
+
+	return form || multipart
+}
+
+func isFile(s map[string]interface{}) bool {
+	return s["type"] == "string" && s["format"] == "binary"
+}
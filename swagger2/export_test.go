@@ -0,0 +1,145 @@
+package swagger2_test
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/convert"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/swagger2"
+)
+
+func TestExport(t *testing.T) {
+	r := openapi3.NewReflector()
+	r.Spec.Info.WithTitle("Pets").WithVersion("1.0.0")
+	r.Spec.WithServers(
+		openapi3.Server{URL: "https://api.example.com/v1"},
+		openapi3.Server{URL: "http://api.example.com/v1"},
+		openapi3.Server{URL: "https://staging.example.com/v1"},
+	)
+	r.Spec.SetHTTPBearerTokenSecurity("bearerAuth", "JWT", "Access token")
+
+	type pet struct {
+		ID   int     `json:"id" required:"true"`
+		Name string  `json:"name" required:"true" minLength:"1"`
+		Tag  *string `json:"tag"`
+	}
+
+	type listReq struct {
+		Tags    []string `query:"tags" description:"Tags to filter by."`
+		Limit   int      `query:"limit" minimum:"1" maximum:"100"`
+		Session string   `cookie:"session"`
+	}
+
+	type listResp struct {
+		Total int   `header:"X-Total-Count" json:"-"`
+		Items []pet `json:"items"`
+	}
+
+	type createReq struct {
+		Name string `json:"name" required:"true"`
+		Tag  string `json:"tag"`
+	}
+
+	type uploadReq struct {
+		ID     int              `path:"id"`
+		Photo  multipart.File   `formData:"photo" required:"true"`
+		Photos []multipart.File `formData:"photos"`
+		Labels []string         `formData:"labels"`
+	}
+
+	oc, err := r.NewOperationContext(http.MethodGet, "/pets")
+	require.NoError(t, err)
+	oc.SetID("listPets")
+	oc.AddReqStructure(listReq{})
+	oc.AddRespStructure(listResp{})
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/pets")
+	require.NoError(t, err)
+	oc.SetID("createPet")
+	oc.AddReqStructure(createReq{})
+	oc.AddRespStructure(pet{}, openapi.WithHTTPStatus(http.StatusCreated))
+	require.NoError(t, r.AddOperation(oc))
+
+	oc, err = r.NewOperationContext(http.MethodPost, "/pets/{id}/photo")
+	require.NoError(t, err)
+	oc.SetID("uploadPhoto")
+	oc.AddReqStructure(uploadReq{})
+	oc.AddRespStructure(nil, openapi.WithHTTPStatus(http.StatusNoContent))
+	require.NoError(t, r.AddOperation(oc))
+
+	data, warnings, err := swagger2.Export(r.SpecEns())
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile("testdata/export_last_run.json", data, 0o600))
+
+	assert.Equal(t, []convert.Warning{
+		{Pointer: "/servers/2", Message: "server removed, only one host and base path is supported"},
+		{
+			Pointer: "/components/securitySchemes/bearerAuth/scheme",
+			Message: "bearer scheme is represented as API key in Authorization header",
+		},
+		{Pointer: "/paths/~1pets/get/parameters/2", Message: "cookie parameter removed"},
+		{
+			Pointer: "/components/schemas/FormDataSwagger2TestUploadReq/properties/photos",
+			Message: "array of files is not supported, single file is used",
+		},
+	}, warnings)
+
+	expected, err := os.ReadFile("testdata/export.json")
+	require.NoError(t, err)
+
+	assertjson.Equal(t, expected, data)
+}
+
+func TestExport_formDefinitions(t *testing.T) {
+	var s openapi3.Spec
+
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.0.3
+info: {title: Forms, version: 1.0.0}
+paths:
+  /form:
+    post:
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema: {$ref: '#/components/schemas/Form'}
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Shared'}
+components:
+  schemas:
+    Form:
+      type: object
+      properties:
+        shared: {$ref: '#/components/schemas/Shared'}
+        nested: {$ref: '#/components/schemas/Nested'}
+    Nested: {type: string}
+    Shared: {type: integer}
+    Unused: {type: boolean}
+`)))
+
+	data, _, err := swagger2.Export(&s)
+	require.NoError(t, err)
+
+	var doc struct {
+		Definitions json.RawMessage `json:"definitions"`
+	}
+
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	// Form schema and its nested schema are removed, shared and unrelated schemas are kept.
+	assertjson.Equal(t, []byte(`{"Shared":{"type":"integer"},"Unused":{"type":"boolean"}}`), doc.Definitions)
+}
@@ -0,0 +1,803 @@
+// Package swagger2 converts documents between Swagger 2.0 and OpenAPI 3.0.
+package swagger2
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go/convert"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// ErrNotSwagger2 is returned for documents without `swagger: "2.0"`.
+var ErrNotSwagger2 = errors.New("not a Swagger 2.0 document")
+
+const (
+	mimeJSON      = "application/json"
+	mimeForm      = "application/x-www-form-urlencoded"
+	mimeMultipart = "multipart/form-data"
+)
+
+// Import converts Swagger 2.0 document in JSON or YAML to OpenAPI 3.0 spec.
+//
+// Definitions, responses and non-body parameters become components, body and formData parameters become
+// request bodies with media types of `consumes`, responses get media types of `produces`,
+// security definitions become security schemes and `collectionFormat` becomes `style` and `explode`.
+//
+// Features without OpenAPI 3.0 equivalent are reported as warnings.
+func Import(data []byte) (*openapi3.Spec, []convert.Warning, error) {
+	v, err := internal.DecodeDocument(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	src, ok := v.(map[string]interface{})
+	if !ok || fmt.Sprint(src["swagger"]) != "2.0" {
+		return nil, nil, ErrNotSwagger2
+	}
+
+	im := importer{src: src}
+	doc := im.document()
+
+	s := &openapi3.Spec{}
+
+	if err := internal.FromDocument(doc, s); err != nil {
+		return nil, im.warnings, fmt.Errorf("loading imported document: %w", err)
+	}
+
+	return s, im.warnings, nil
+}
+
+type importer struct {
+	src      map[string]interface{}
+	warnings []convert.Warning
+}
+
+func (im *importer) warn(ptr, msg string) {
+	im.warnings = append(im.warnings, convert.Warning{Pointer: ptr, Message: msg})
+}
+
+func (im *importer) document() map[string]interface{} {
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+	}
+
+	for _, k := range []string{"info", "tags", "externalDocs", "security"} {
+		if v, ok := im.src[k]; ok {
+			doc[k] = v
+		}
+	}
+
+	copyExtensions(doc, im.src)
+
+	if servers := im.servers(im.src["schemes"]); len(servers) > 0 {
+		doc["servers"] = servers
+	}
+
+	components := map[string]interface{}{}
+
+	if defs, ok := im.src["definitions"].(map[string]interface{}); ok {
+		schemas := make(map[string]interface{}, len(defs))
+
+		for _, name := range internal.SortedKeys(defs) {
+			schemas[name] = im.schema("/definitions/"+internal.EscapeJSONPointer(name), defs[name])
+		}
+
+		components["schemas"] = schemas
+	}
+
+	im.componentParameters(components)
+
+	if responses, ok := im.src["responses"].(map[string]interface{}); ok {
+		res := make(map[string]interface{}, len(responses))
+		produces := stringList(im.src["produces"])
+
+		for _, name := range internal.SortedKeys(responses) {
+			res[name] = im.response("/responses/"+internal.EscapeJSONPointer(name), responses[name], produces)
+		}
+
+		components["responses"] = res
+	}
+
+	if sd, ok := im.src["securityDefinitions"].(map[string]interface{}); ok {
+		schemes := make(map[string]interface{}, len(sd))
+
+		for _, name := range internal.SortedKeys(sd) {
+			schemes[name] = im.securityScheme("/securityDefinitions/"+internal.EscapeJSONPointer(name), sd[name])
+		}
+
+		components["securitySchemes"] = schemes
+	}
+
+	if len(components) > 0 {
+		doc["components"] = components
+	}
+
+	paths := map[string]interface{}{}
+
+	if srcPaths, ok := im.src["paths"].(map[string]interface{}); ok {
+		for _, p := range internal.SortedKeys(srcPaths) {
+			pi, ok := srcPaths[p].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if strings.HasPrefix(p, "x-") {
+				paths[p] = pi
+
+				continue
+			}
+
+			paths[p] = im.pathItem("/paths/"+internal.EscapeJSONPointer(p), pi)
+		}
+	}
+
+	doc["paths"] = paths
+
+	return doc
+}
+
+// servers builds server URLs from host, basePath and schemes.
+func (im *importer) servers(schemes interface{}) []interface{} {
+	host, _ := im.src["host"].(string)         //nolint:errcheck // Empty host is valid.
+	basePath, _ := im.src["basePath"].(string) //nolint:errcheck // Empty base path is valid.
+
+	if host == "" && basePath == "" {
+		return nil
+	}
+
+	if host == "" {
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+
+	var servers []interface{}
+
+	for _, scheme := range stringList(schemes) {
+		servers = append(servers, map[string]interface{}{"url": scheme + "://" + host + basePath})
+	}
+
+	if len(servers) == 0 {
+		// Scheme of the document location is used when schemes are not defined.
+		servers = append(servers, map[string]interface{}{"url": "//" + host + basePath})
+	}
+
+	return servers
+}
+
+// componentParameters converts global parameters, body parameters become request bodies,
+// formData parameters are inlined into request bodies of operations.
+func (im *importer) componentParameters(components map[string]interface{}) {
+	params, ok := im.src["parameters"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	res := map[string]interface{}{}
+	bodies := map[string]interface{}{}
+	consumes := stringList(im.src["consumes"])
+
+	for _, name := range internal.SortedKeys(params) {
+		ptr := "/parameters/" + internal.EscapeJSONPointer(name)
+
+		p, ok := params[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		switch p["in"] {
+		case "body":
+			bodies[name] = im.bodyRequest(ptr, p, consumes)
+		case "formData":
+			continue
+		default:
+			res[name] = im.parameter(ptr, p)
+		}
+	}
+
+	if len(res) > 0 {
+		components["parameters"] = res
+	}
+
+	if len(bodies) > 0 {
+		components["requestBodies"] = bodies
+	}
+}
+
+var pathItemMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+func (im *importer) pathItem(ptr string, pi map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+
+	copyExtensions(res, pi)
+
+	if _, ok := pi["$ref"]; ok {
+		im.warn(ptr+"/$ref", "path item reference removed")
+	}
+
+	common, _ := pi["parameters"].([]interface{}) //nolint:errcheck // Nil is valid.
+
+	var params []interface{}
+
+	for i, p := range common {
+		if in := str(im.resolveParameter(p), "in"); in == "body" || in == "formData" {
+			continue // Body parameters are applied to operations.
+		}
+
+		params = append(params, im.parameterOrRef(ptr+"/parameters/"+strconv.Itoa(i), p))
+	}
+
+	if len(params) > 0 {
+		res["parameters"] = params
+	}
+
+	for _, method := range pathItemMethods {
+		op, ok := pi[method].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		res[method] = im.operation(ptr, method, op, common)
+	}
+
+	return res
+}
+
+// operation converts operation of a path item, body and formData parameters of path item are applied to request body.
+func (im *importer) operation(piPtr, method string, op map[string]interface{}, common []interface{}) map[string]interface{} {
+	ptr := piPtr + "/" + method
+	res := map[string]interface{}{}
+
+	for _, k := range []string{"tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security"} {
+		if v, ok := op[k]; ok {
+			res[k] = v
+		}
+	}
+
+	copyExtensions(res, op)
+
+	if schemes, ok := op["schemes"]; ok {
+		if servers := im.servers(schemes); len(servers) > 0 {
+			res["servers"] = servers
+		}
+	}
+
+	consumes := stringList(im.src["consumes"])
+	if c, ok := op["consumes"]; ok {
+		consumes = stringList(c)
+	}
+
+	produces := stringList(im.src["produces"])
+	if p, ok := op["produces"]; ok {
+		produces = stringList(p)
+	}
+
+	opParams, _ := op["parameters"].([]interface{}) //nolint:errcheck // Nil is valid.
+
+	var (
+		params   []interface{}
+		body     interface{}
+		bodyPtr  string
+		formData []formParam
+	)
+
+	// Path item parameters are overridden by operation parameters with the same name and location.
+	overridden := map[string]bool{}
+
+	for _, p := range opParams {
+		rp := im.resolveParameter(p)
+		overridden[str(rp, "in")+":"+str(rp, "name")] = true
+	}
+
+	all := make([]sourceParam, 0, len(common)+len(opParams))
+
+	for i, p := range common {
+		rp := im.resolveParameter(p)
+		if in := str(rp, "in"); (in == "body" || in == "formData") && !overridden[in+":"+str(rp, "name")] {
+			all = append(all, sourceParam{ptr: piPtr + "/parameters/" + strconv.Itoa(i), v: p})
+		}
+	}
+
+	for i, p := range opParams {
+		all = append(all, sourceParam{ptr: ptr + "/parameters/" + strconv.Itoa(i), v: p})
+	}
+
+	for _, sp := range all {
+		resolved := im.resolveParameter(sp.v)
+
+		switch str(resolved, "in") {
+		case "body":
+			if ref, ok := refOf(sp.v); ok {
+				body = map[string]interface{}{"$ref": "#/components/requestBodies/" + strings.TrimPrefix(ref, "#/parameters/")}
+			} else {
+				body = im.bodyRequest(sp.ptr, resolved, consumes)
+			}
+
+			bodyPtr = sp.ptr
+		case "formData":
+			formData = append(formData, formParam{ptr: sp.ptr, p: resolved})
+		default:
+			params = append(params, im.parameterOrRef(sp.ptr, sp.v))
+		}
+	}
+
+	if len(params) > 0 {
+		res["parameters"] = params
+	}
+
+	if len(formData) > 0 {
+		if body != nil {
+			im.warn(bodyPtr, "body parameter ignored in favor of formData parameters")
+		}
+
+		body = im.formRequest(formData, consumes)
+	}
+
+	if body != nil {
+		res["requestBody"] = body
+	}
+
+	responses := map[string]interface{}{}
+
+	if srcResponses, ok := op["responses"].(map[string]interface{}); ok {
+		for _, code := range internal.SortedKeys(srcResponses) {
+			if strings.HasPrefix(code, "x-") {
+				responses[code] = srcResponses[code]
+
+				continue
+			}
+
+			responses[code] = im.response(ptr+"/responses/"+internal.EscapeJSONPointer(code), srcResponses[code], produces)
+		}
+	}
+
+	res["responses"] = responses
+
+	return res
+}
+
+type sourceParam struct {
+	ptr string
+	v   interface{}
+}
+
+type formParam struct {
+	ptr string
+	p   map[string]interface{}
+}
+
+// resolveParameter returns a parameter or a global parameter by reference.
+func (im *importer) resolveParameter(v interface{}) map[string]interface{} {
+	if ref, ok := refOf(v); ok {
+		var err error
+
+		if v, err = internal.ResolvePointer(im.src, ref); err != nil {
+			return nil
+		}
+	}
+
+	p, _ := v.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	return p
+}
+
+func (im *importer) parameterOrRef(ptr string, v interface{}) interface{} {
+	if ref, ok := refOf(v); ok {
+		return map[string]interface{}{"$ref": "#/components/parameters/" + strings.TrimPrefix(ref, "#/parameters/")}
+	}
+
+	p, _ := v.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	return im.parameter(ptr, p)
+}
+
+func (im *importer) parameter(ptr string, p map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+
+	for _, k := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+		if v, ok := p[k]; ok {
+			res[k] = v
+		}
+	}
+
+	copyExtensions(res, p)
+
+	in := str(p, "in")
+	if in == "path" {
+		res["required"] = true
+	}
+
+	res["schema"] = im.simpleSchema(ptr, p)
+
+	if p["type"] == "array" {
+		im.collectionFormat(ptr, in, p["collectionFormat"], res)
+	}
+
+	return res
+}
+
+// collectionFormat sets style and explode of a parameter or an encoding by Swagger 2.0 collection format.
+func (im *importer) collectionFormat(ptr, in string, format interface{}, res map[string]interface{}) {
+	f, _ := format.(string) //nolint:errcheck // Empty is valid.
+	if f == "" {
+		f = "csv"
+	}
+
+	isQuery := in == "query" || in == "formData"
+
+	switch {
+	case f == "csv" && isQuery:
+		res["style"], res["explode"] = "form", false
+	case f == "csv":
+		res["style"], res["explode"] = "simple", false
+	case f == "multi" && isQuery:
+		res["style"], res["explode"] = "form", true
+	case f == "ssv" && isQuery:
+		res["style"], res["explode"] = "spaceDelimited", false
+	case f == "pipes" && isQuery:
+		res["style"], res["explode"] = "pipeDelimited", false
+	default:
+		im.warn(ptr+"/collectionFormat", "unsupported collectionFormat "+strconv.Quote(f)+" in "+in)
+	}
+}
+
+var simpleSchemaKeywords = []string{
+	"type", "format", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+// simpleSchema builds schema of a non-body parameter, a header or an items object.
+func (im *importer) simpleSchema(ptr string, p map[string]interface{}) map[string]interface{} {
+	s := map[string]interface{}{}
+
+	for _, k := range simpleSchemaKeywords {
+		if v, ok := p[k]; ok {
+			s[k] = v
+		}
+	}
+
+	if s["type"] == "file" {
+		s["type"], s["format"] = "string", "binary"
+	}
+
+	if items, ok := p["items"].(map[string]interface{}); ok {
+		if f, ok := items["collectionFormat"]; ok && f != "csv" {
+			im.warn(ptr+"/items/collectionFormat", "unsupported collectionFormat of nested array")
+		}
+
+		s["items"] = im.simpleSchema(ptr+"/items", items)
+	}
+
+	return s
+}
+
+// schema converts Swagger 2.0 schema to OpenAPI 3.0 schema.
+func (im *importer) schema(ptr string, v interface{}) interface{} {
+	s, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	res := make(map[string]interface{}, len(s))
+
+	for _, k := range internal.SortedKeys(s) {
+		v := s[k]
+		kptr := ptr + "/" + internal.EscapeJSONPointer(k)
+
+		switch k {
+		case "$ref":
+			if ref, ok := v.(string); ok && strings.HasPrefix(ref, "#/definitions/") {
+				v = "#/components/schemas/" + strings.TrimPrefix(ref, "#/definitions/")
+			}
+		case "x-nullable":
+			k = "nullable"
+		case "discriminator":
+			if name, ok := v.(string); ok {
+				v = map[string]interface{}{"propertyName": name}
+			}
+		case "format":
+			if s["type"] == "file" {
+				continue
+			}
+		case "type":
+			if v == "file" {
+				v = "string"
+				res["format"] = "binary"
+			}
+		case "properties":
+			if props, ok := v.(map[string]interface{}); ok {
+				rp := make(map[string]interface{}, len(props))
+
+				for _, name := range internal.SortedKeys(props) {
+					rp[name] = im.schema(kptr+"/"+internal.EscapeJSONPointer(name), props[name])
+				}
+
+				v = rp
+			}
+		case "items", "additionalProperties", "not":
+			v = im.schema(kptr, v)
+		case "allOf", "anyOf", "oneOf":
+			if list, ok := v.([]interface{}); ok {
+				rl := make([]interface{}, len(list))
+
+				for i, item := range list {
+					rl[i] = im.schema(kptr+"/"+strconv.Itoa(i), item)
+				}
+
+				v = rl
+			}
+		}
+
+		res[k] = v
+	}
+
+	return res
+}
+
+func (im *importer) bodyRequest(ptr string, p map[string]interface{}, consumes []string) map[string]interface{} {
+	if len(consumes) == 0 {
+		consumes = []string{mimeJSON}
+	}
+
+	content := make(map[string]interface{}, len(consumes))
+	schema := im.schema(ptr+"/schema", p["schema"])
+
+	for _, ct := range consumes {
+		content[ct] = map[string]interface{}{"schema": schema}
+	}
+
+	res := map[string]interface{}{"content": content}
+
+	for _, k := range []string{"description", "required"} {
+		if v, ok := p[k]; ok {
+			res[k] = v
+		}
+	}
+
+	copyExtensions(res, p)
+
+	return res
+}
+
+func (im *importer) formRequest(params []formParam, consumes []string) map[string]interface{} {
+	props := map[string]interface{}{}
+	encoding := map[string]interface{}{}
+	hasFile := false
+
+	var required []interface{}
+
+	for _, fp := range params {
+		name := str(fp.p, "name")
+		s := im.simpleSchema(fp.ptr, fp.p)
+
+		if d, ok := fp.p["description"]; ok {
+			s["description"] = d
+		}
+
+		if fp.p["type"] == "file" {
+			hasFile = true
+		}
+
+		if fp.p["type"] == "array" {
+			enc := map[string]interface{}{}
+			im.collectionFormat(fp.ptr, "formData", fp.p["collectionFormat"], enc)
+
+			if len(enc) > 0 {
+				encoding[name] = enc
+			}
+		}
+
+		if r, _ := fp.p["required"].(bool); r { //nolint:errcheck // False is valid.
+			required = append(required, name)
+		}
+
+		props[name] = s
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	var types []string
+
+	for _, ct := range consumes {
+		if ct == mimeForm || ct == mimeMultipart {
+			types = append(types, ct)
+		}
+	}
+
+	if len(types) == 0 {
+		types = []string{mimeForm}
+		if hasFile {
+			types = []string{mimeMultipart}
+		}
+	}
+
+	content := make(map[string]interface{}, len(types))
+
+	for _, ct := range types {
+		mt := map[string]interface{}{"schema": schema}
+		if len(encoding) > 0 {
+			mt["encoding"] = encoding
+		}
+
+		content[ct] = mt
+	}
+
+	return map[string]interface{}{"content": content}
+}
+
+func (im *importer) response(ptr string, v interface{}, produces []string) interface{} {
+	if ref, ok := refOf(v); ok {
+		return map[string]interface{}{"$ref": "#/components/responses/" + strings.TrimPrefix(ref, "#/responses/")}
+	}
+
+	r, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	res := map[string]interface{}{"description": r["description"]}
+	if res["description"] == nil {
+		res["description"] = ""
+	}
+
+	copyExtensions(res, r)
+
+	if headers, ok := r["headers"].(map[string]interface{}); ok {
+		rh := make(map[string]interface{}, len(headers))
+
+		for _, name := range internal.SortedKeys(headers) {
+			h, _ := headers[name].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+			hptr := ptr + "/headers/" + internal.EscapeJSONPointer(name)
+			hr := map[string]interface{}{"schema": im.simpleSchema(hptr, h)}
+
+			if d, ok := h["description"]; ok {
+				hr["description"] = d
+			}
+
+			if h["type"] == "array" {
+				if f, ok := h["collectionFormat"]; ok && f != "csv" {
+					im.warn(hptr+"/collectionFormat", "unsupported collectionFormat "+strconv.Quote(fmt.Sprint(f))+" in header")
+				}
+			}
+
+			rh[name] = hr
+		}
+
+		res["headers"] = rh
+	}
+
+	examples, _ := r["examples"].(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	if s, ok := r["schema"]; ok {
+		if len(produces) == 0 {
+			produces = []string{mimeJSON}
+		}
+
+		schema := im.schema(ptr+"/schema", s)
+		content := make(map[string]interface{}, len(produces))
+
+		for _, ct := range produces {
+			content[ct] = map[string]interface{}{"schema": schema}
+		}
+
+		for _, ct := range internal.SortedKeys(examples) {
+			mt, ok := content[ct].(map[string]interface{})
+			if !ok {
+				mt = map[string]interface{}{"schema": schema}
+				content[ct] = mt
+			}
+
+			mt["example"] = examples[ct]
+		}
+
+		res["content"] = content
+	} else if len(examples) > 0 {
+		im.warn(ptr+"/examples", "examples of response without schema removed")
+	}
+
+	return res
+}
+
+func (im *importer) securityScheme(ptr string, v interface{}) interface{} {
+	sd, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	res := map[string]interface{}{}
+
+	if d, ok := sd["description"]; ok {
+		res["description"] = d
+	}
+
+	copyExtensions(res, sd)
+
+	switch sd["type"] {
+	case "basic":
+		res["type"], res["scheme"] = "http", "basic"
+	case "apiKey":
+		res["type"], res["name"], res["in"] = "apiKey", sd["name"], sd["in"]
+	case "oauth2":
+		res["type"] = "oauth2"
+
+		flow := map[string]interface{}{"scopes": sd["scopes"]}
+		if flow["scopes"] == nil {
+			flow["scopes"] = map[string]interface{}{}
+		}
+
+		flowName := ""
+
+		switch sd["flow"] {
+		case "implicit":
+			flowName = "implicit"
+			flow["authorizationUrl"] = sd["authorizationUrl"]
+		case "password":
+			flowName = "password"
+			flow["tokenUrl"] = sd["tokenUrl"]
+		case "application":
+			flowName = "clientCredentials"
+			flow["tokenUrl"] = sd["tokenUrl"]
+		case "accessCode":
+			flowName = "authorizationCode"
+			flow["authorizationUrl"] = sd["authorizationUrl"]
+			flow["tokenUrl"] = sd["tokenUrl"]
+		default:
+			im.warn(ptr+"/flow", "unsupported oauth2 flow: "+fmt.Sprint(sd["flow"]))
+
+			return res
+		}
+
+		res["flows"] = map[string]interface{}{flowName: flow}
+	default:
+		im.warn(ptr+"/type", "unsupported security scheme type: "+fmt.Sprint(sd["type"]))
+	}
+
+	return res
+}
+
+func refOf(v interface{}) (string, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+
+	ref, ok := m["$ref"].(string)
+
+	return ref, ok
+}
+
+func str(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string) //nolint:errcheck // Empty is valid.
+
+	return s
+}
+
+func stringList(v interface{}) []string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	res := make([]string, 0, len(list))
+
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
+
+func copyExtensions(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if strings.HasPrefix(k, "x-") && k != "x-nullable" {
+			dst[k] = v
+		}
+	}
+}
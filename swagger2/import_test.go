@@ -0,0 +1,34 @@
+package swagger2_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go/convert"
+	"github.com/swaggest/openapi-go/swagger2"
+)
+
+func TestImport(t *testing.T) {
+	data, err := os.ReadFile("testdata/petstore.yaml")
+	require.NoError(t, err)
+
+	s, warnings, err := swagger2.Import(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, []convert.Warning{
+		{Pointer: "/paths/~1pets/get/parameters/2/collectionFormat", Message: `unsupported collectionFormat "tsv" in header`},
+	}, warnings)
+
+	expected, err := os.ReadFile("testdata/petstore_openapi3.json")
+	require.NoError(t, err)
+
+	assertjson.EqualMarshal(t, expected, s)
+}
+
+func TestImport_notSwagger2(t *testing.T) {
+	_, _, err := swagger2.Import([]byte(`{"openapi":"3.0.3"}`))
+	assert.Equal(t, swagger2.ErrNotSwagger2, err)
+}
@@ -0,0 +1,70 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+)
+
+// Violation describes a single validation problem.
+type Violation struct {
+	// In is a location of invalid value, e.g. query or body.
+	In openapi.In `json:"in,omitempty"`
+
+	// Name is a name of invalid parameter or header.
+	Name string `json:"name,omitempty"`
+
+	// Pointer is a JSON Pointer to invalid value within parameter or body.
+	Pointer string `json:"pointer,omitempty"`
+
+	// Location is a JSON Pointer to the failed schema keyword in spec.
+	Location string `json:"location,omitempty"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// String implements fmt.Stringer.
+func (v Violation) String() string {
+	s := ""
+
+	if v.In != "" {
+		s += string(v.In)
+
+		if v.Name != "" {
+			s += "." + v.Name
+		}
+
+		s += v.Pointer + ": "
+	}
+
+	return s + v.Message
+}
+
+// Error is returned for an invalid request or response.
+type Error struct {
+	// Status is an HTTP status code to respond with.
+	Status     int
+	Violations []Violation
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.String())
+	}
+
+	return "validation failed: " + strings.Join(msgs, ", ")
+}
+
+// Problem is an RFC 9457 problem details object.
+type Problem struct {
+	Type     string      `json:"type"`
+	Title    string      `json:"title"`
+	Status   int         `json:"status"`
+	Detail   string      `json:"detail,omitempty"`
+	Instance string      `json:"instance,omitempty"`
+	Errors   []Violation `json:"errors,omitempty"`
+}
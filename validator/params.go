@@ -0,0 +1,591 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// Parameter styles, see https://spec.openapis.org/oas/v3.1.0#style-values.
+const (
+	styleSimple         = "simple"
+	styleLabel          = "label"
+	styleMatrix         = "matrix"
+	styleForm           = "form"
+	styleSpaceDelimited = "spaceDelimited"
+	stylePipeDelimited  = "pipeDelimited"
+	styleDeepObject     = "deepObject"
+)
+
+const (
+	mimeJSON           = "application/json"
+	mimeFormUrlencoded = "application/x-www-form-urlencoded"
+	mimeMultipart      = "multipart/form-data"
+
+	// xForbidUnknown is a prefix of a vendor extension to indicate forbidden unknown parameters.
+	xForbidUnknown = "x-forbid-unknown-"
+)
+
+type param struct {
+	*openapi31.Parameter
+	ptr string
+}
+
+// parameters collects path item and operation parameters, operation parameters take precedence.
+func (v *Validator) parameters(rt Route) []param {
+	var res []param
+
+	collect := func(ptr string, params []openapi31.ParameterOrReference) {
+		for i, p := range params {
+			pp := p.Parameter
+			pptr := ptr + "/parameters/" + strconv.Itoa(i)
+
+			if p.Reference != nil {
+				var ok bool
+				if pptr, ok = v.resolve(pptr, p.Reference, "parameters"); !ok || v.spec.Components == nil {
+					continue
+				}
+
+				pp = v.spec.Components.Parameters[componentName(pptr)].Parameter
+			}
+
+			if pp == nil {
+				continue
+			}
+
+			replaced := false
+
+			for j, ep := range res {
+				if ep.In == pp.In && ep.Name == pp.Name {
+					res[j] = param{Parameter: pp, ptr: pptr}
+					replaced = true
+				}
+			}
+
+			if !replaced {
+				res = append(res, param{Parameter: pp, ptr: pptr})
+			}
+		}
+	}
+
+	collect(rt.Pointer[:strings.LastIndex(rt.Pointer, "/")], rt.PathItem.Parameters)
+	collect(rt.Pointer, rt.Operation.Parameters)
+
+	return res
+}
+
+func (v *Validator) validateParameters(r *http.Request, rt Route, e *Error) error {
+	params := v.parameters(rt)
+	query := r.URL.Query()
+
+	for _, p := range params {
+		value, present, err := v.decodeParameter(r, rt, query, p)
+		if err != nil {
+			e.Violations = append(e.Violations, Violation{In: openapi.In(p.In), Name: p.Name, Message: err.Error()})
+
+			continue
+		}
+
+		if !present {
+			if p.Required != nil && *p.Required {
+				e.Violations = append(e.Violations, Violation{
+					In: openapi.In(p.In), Name: p.Name, Message: "missing required parameter",
+				})
+			}
+
+			continue
+		}
+
+		sptr := p.ptr + "/schema"
+		if p.Schema == nil {
+			if _, ok := p.Content[mimeJSON]; !ok {
+				continue
+			}
+
+			sptr = p.ptr + "/content/application~1json/schema"
+		}
+
+		if err := v.validate(e, openapi.In(p.In), p.Name, sptr, value); err != nil {
+			return err
+		}
+	}
+
+	v.checkUnknown(r, rt, query, params, e)
+
+	return nil
+}
+
+// checkUnknown reports parameters that are not defined in operation, if they are forbidden by vendor extension.
+func (v *Validator) checkUnknown(r *http.Request, rt Route, query url.Values, params []param, e *Error) {
+	if forbid, _ := rt.Operation.MapOfAnything[xForbidUnknown+string(openapi.InQuery)].(bool); forbid { //nolint:errcheck
+		names := make([]string, 0, len(query))
+		for name := range query {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			if !v.knownQuery(name, params) {
+				e.Violations = append(e.Violations, Violation{
+					In: openapi.InQuery, Name: name, Message: "unknown parameter",
+				})
+			}
+		}
+	}
+
+	if forbid, _ := rt.Operation.MapOfAnything[xForbidUnknown+string(openapi.InCookie)].(bool); forbid { //nolint:errcheck
+		for _, c := range r.Cookies() {
+			if !known(c.Name, openapi31.ParameterInCookie, params) {
+				e.Violations = append(e.Violations, Violation{
+					In: openapi.InCookie, Name: c.Name, Message: "unknown parameter",
+				})
+			}
+		}
+	}
+}
+
+func known(name string, in openapi31.ParameterIn, params []param) bool {
+	for _, p := range params {
+		if p.In == in && p.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *Validator) knownQuery(name string, params []param) bool {
+	for _, p := range params {
+		if p.In != openapi31.ParameterInQuery {
+			continue
+		}
+
+		if p.Name == name {
+			return true
+		}
+
+		style, explode := paramStyle(p.Parameter)
+
+		if style == styleDeepObject && strings.HasPrefix(name, p.Name+"[") {
+			return true
+		}
+
+		if style == styleForm && explode && v.types(p.Schema)["object"] && v.property(p.Schema, name) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func paramStyle(p *openapi31.Parameter) (string, bool) {
+	style := styleSimple
+
+	if p.In == openapi31.ParameterInQuery || p.In == openapi31.ParameterInCookie {
+		style = styleForm
+	}
+
+	if p.Style != nil {
+		style = string(*p.Style)
+	}
+
+	explode := style == styleForm
+
+	if p.Explode != nil {
+		explode = *p.Explode
+	}
+
+	return style, explode
+}
+
+// decodeParameter extracts parameter value from request and converts it according to the schema.
+func (v *Validator) decodeParameter(r *http.Request, rt Route, query url.Values, p param) (interface{}, bool, error) {
+	style, explode := paramStyle(p.Parameter)
+
+	if p.Schema == nil {
+		if _, ok := p.Content[mimeJSON]; !ok {
+			return nil, false, nil
+		}
+
+		raw, present := rawParameter(r, rt, query, p.Parameter)
+		if !present {
+			return nil, false, nil
+		}
+
+		var value interface{}
+
+		if err := unmarshalJSON([]byte(raw), &value); err != nil {
+			return nil, true, fmt.Errorf("invalid JSON value: %w", err)
+		}
+
+		return value, true, nil
+	}
+
+	switch p.In {
+	case openapi31.ParameterInPath:
+		raw, ok := rt.PathParams[p.Name]
+		if !ok {
+			return nil, false, nil
+		}
+
+		return v.decodePath(raw, p.Name, style, explode, p.Schema)
+	case openapi31.ParameterInQuery:
+		value, present := v.decodeQuery(query, p.Name, style, explode, p.Schema)
+
+		return value, present, nil
+	case openapi31.ParameterInHeader:
+		values := r.Header.Values(p.Name)
+		if len(values) == 0 {
+			return nil, false, nil
+		}
+
+		return v.decodeDelimited(strings.Join(values, ","), ",", explode, p.Schema, strings.TrimSpace), true, nil
+	case openapi31.ParameterInCookie:
+		c, err := r.Cookie(p.Name)
+		if err != nil {
+			return nil, false, nil //nolint:nilerr // Missing cookie.
+		}
+
+		return v.decodeDelimited(c.Value, ",", false, p.Schema, nil), true, nil
+	}
+
+	return nil, false, nil
+}
+
+func rawParameter(r *http.Request, rt Route, query url.Values, p *openapi31.Parameter) (string, bool) {
+	switch p.In {
+	case openapi31.ParameterInPath:
+		raw, ok := rt.PathParams[p.Name]
+
+		return pathUnescape(raw), ok
+	case openapi31.ParameterInQuery:
+		if _, ok := query[p.Name]; !ok {
+			return "", false
+		}
+
+		return query.Get(p.Name), true
+	case openapi31.ParameterInHeader:
+		if _, ok := r.Header[http.CanonicalHeaderKey(p.Name)]; !ok {
+			return "", false
+		}
+
+		return r.Header.Get(p.Name), true
+	case openapi31.ParameterInCookie:
+		c, err := r.Cookie(p.Name)
+		if err != nil {
+			return "", false
+		}
+
+		return c.Value, true
+	}
+
+	return "", false
+}
+
+func pathUnescape(s string) string {
+	if u, err := url.PathUnescape(s); err == nil {
+		return u
+	}
+
+	return s
+}
+
+func (v *Validator) decodePath(raw, name, style string, explode bool, schema interface{}) (interface{}, bool, error) {
+	sep := ","
+
+	switch style {
+	case styleLabel:
+		if !strings.HasPrefix(raw, ".") {
+			return nil, true, fmt.Errorf("label style value must start with '.'")
+		}
+
+		raw = raw[1:]
+
+		if explode {
+			sep = "."
+		}
+	case styleMatrix:
+		prefix := ";" + name + "="
+		t := v.types(schema)
+
+		switch {
+		case t["array"] && explode:
+			var items []string
+
+			for _, part := range strings.Split(raw, ";") {
+				if strings.HasPrefix(part, name+"=") {
+					items = append(items, part[len(name)+1:])
+				}
+			}
+
+			raw = strings.Join(items, ",")
+		case t["object"] && explode:
+			raw = strings.TrimPrefix(raw, ";")
+			sep = ";"
+		default:
+			if !strings.HasPrefix(raw, prefix) {
+				return nil, true, fmt.Errorf("matrix style value must start with %q", prefix)
+			}
+
+			raw = raw[len(prefix):]
+		}
+	}
+
+	return v.decodeDelimited(raw, sep, explode, schema, pathUnescape), true, nil
+}
+
+// decodeDelimited converts a string with delimited items to a value according to schema.
+func (v *Validator) decodeDelimited(raw, sep string, explode bool, schema interface{}, clean func(s string) string) interface{} {
+	if clean == nil {
+		clean = func(s string) string { return s }
+	}
+
+	t := v.types(schema)
+
+	switch {
+	case t["array"]:
+		if raw == "" {
+			return []interface{}{}
+		}
+
+		parts := strings.Split(raw, sep)
+		for i, p := range parts {
+			parts[i] = clean(p)
+		}
+
+		return v.coerceArray(schema, parts)
+	case t["object"]:
+		props := map[string]string{}
+		parts := strings.Split(raw, sep)
+
+		if explode {
+			for _, p := range parts {
+				kv := strings.SplitN(p, "=", 2)
+				if len(kv) == 2 {
+					props[clean(kv[0])] = clean(kv[1])
+				}
+			}
+		} else {
+			for i := 0; i+1 < len(parts); i += 2 {
+				props[clean(parts[i])] = clean(parts[i+1])
+			}
+		}
+
+		return v.coerceObject(schema, props)
+	default:
+		return v.coerce(schema, clean(raw))
+	}
+}
+
+// decodeQuery extracts value from URL query or form data.
+func (v *Validator) decodeQuery(query url.Values, name, style string, explode bool, schema interface{}) (interface{}, bool) {
+	t := v.types(schema)
+
+	if style == styleDeepObject {
+		props := map[string]string{}
+		prefix := name + "["
+
+		for k, vals := range query {
+			if strings.HasPrefix(k, prefix) && strings.HasSuffix(k, "]") && len(vals) > 0 {
+				props[k[len(prefix):len(k)-1]] = vals[0]
+			}
+		}
+
+		if len(props) == 0 {
+			return nil, false
+		}
+
+		return v.coerceObject(schema, props), true
+	}
+
+	if t["object"] && explode {
+		props := map[string]string{}
+
+		for k, vals := range query {
+			if v.property(schema, k) != nil && len(vals) > 0 {
+				props[k] = vals[0]
+			}
+		}
+
+		if len(props) == 0 {
+			return nil, false
+		}
+
+		return v.coerceObject(schema, props), true
+	}
+
+	vals, ok := query[name]
+	if !ok || len(vals) == 0 {
+		return nil, false
+	}
+
+	if t["array"] {
+		switch {
+		case style == styleSpaceDelimited && !explode:
+			return v.decodeDelimited(vals[0], " ", false, schema, nil), true
+		case style == stylePipeDelimited && !explode:
+			return v.decodeDelimited(vals[0], "|", false, schema, nil), true
+		case explode:
+			return v.coerceArray(schema, vals), true
+		}
+	}
+
+	return v.decodeDelimited(vals[0], ",", explode, schema, nil), true
+}
+
+// findMediaType finds content for a Content-Type, wildcards are supported.
+func findMediaType(content map[string]openapi31.MediaType, ct string) (openapi31.MediaType, string, bool) {
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		mt = ct
+	}
+
+	if c, ok := content[mt]; ok {
+		return c, mt, true
+	}
+
+	if i := strings.IndexByte(mt, '/'); i > 0 {
+		if c, ok := content[mt[:i]+"/*"]; ok {
+			return c, mt[:i] + "/*", true
+		}
+	}
+
+	if c, ok := content["*/*"]; ok {
+		return c, "*/*", true
+	}
+
+	return openapi31.MediaType{}, "", false
+}
+
+func isJSON(mt string) bool {
+	return mt == mimeJSON || strings.HasSuffix(mt, "+json")
+}
+
+func unmarshalJSON(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	if err := d.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := d.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after JSON value")
+	}
+
+	return nil
+}
+
+// decodeBody converts request body to a value for validation, nil result means body is not validated.
+func (v *Validator) decodeBody(body []byte, ct string, mt openapi31.MediaType) (*interface{}, error) {
+	m, params, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content type: %w", err)
+	}
+
+	var value interface{}
+
+	switch {
+	case isJSON(m):
+		if err := unmarshalJSON(body, &value); err != nil {
+			return nil, fmt.Errorf("invalid JSON body: %w", err)
+		}
+	case m == mimeFormUrlencoded:
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid form body: %w", err)
+		}
+
+		value = v.decodeForm(form, mt)
+	case m == mimeMultipart:
+		form, err := readMultipart(body, params["boundary"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid multipart body: %w", err)
+		}
+
+		value = v.decodeForm(form, mt)
+	default:
+		return nil, nil
+	}
+
+	return &value, nil
+}
+
+func readMultipart(body []byte, boundary string) (url.Values, error) {
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	form := url.Values{}
+
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return form, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// File content is not validated, file name is used as a value.
+		if p.FileName() != "" {
+			form.Add(p.FormName(), p.FileName())
+
+			continue
+		}
+
+		data, err := io.ReadAll(p)
+		if err != nil {
+			return nil, err
+		}
+
+		form.Add(p.FormName(), string(data))
+	}
+}
+
+// decodeForm converts form fields to an object according to body schema and encoding.
+func (v *Validator) decodeForm(form url.Values, mt openapi31.MediaType) map[string]interface{} {
+	res := map[string]interface{}{}
+	props := v.properties(mt.Schema)
+
+	for name := range form {
+		if _, ok := props[name]; !ok && !strings.Contains(name, "[") {
+			res[name] = form.Get(name)
+		}
+	}
+
+	for name, ps := range props {
+		style, explode := styleForm, true
+
+		// Nested objects in form data are usually encoded as `name[key]=value`.
+		if v.types(ps)["object"] {
+			style = styleDeepObject
+		}
+
+		if enc, ok := mt.Encoding[name]; ok {
+			if enc.Style != nil {
+				style = string(*enc.Style)
+			}
+
+			if enc.Explode != nil {
+				explode = *enc.Explode
+			}
+		}
+
+		if value, present := v.decodeQuery(form, name, style, explode, ps); present {
+			res[name] = value
+		}
+	}
+
+	return res
+}
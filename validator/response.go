@@ -0,0 +1,198 @@
+package validator
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// findResponse finds response by status code, status range (e.g. 2XX) or default.
+func (v *Validator) findResponse(rt Route, status int) (*openapi31.Response, string) {
+	code := strconv.Itoa(status)
+
+	for _, key := range []string{code, code[:1] + "XX", "default"} {
+		if resp, ptr := v.responseByKey(rt, key); resp != nil {
+			return resp, ptr
+		}
+	}
+
+	return nil, ""
+}
+
+// responseByKey returns response by its key in operation responses, e.g. "200", "2XX" or "default".
+func (v *Validator) responseByKey(rt Route, key string) (*openapi31.Response, string) {
+	rr := rt.Operation.Responses
+	if rr == nil {
+		return nil, ""
+	}
+
+	ror, found := rr.MapOfResponseOrReferenceValues[key]
+	if key == "default" && rr.Default != nil {
+		ror, found = *rr.Default, true
+	}
+
+	if !found {
+		return nil, ""
+	}
+
+	ptr := rt.Pointer + "/responses/" + key
+
+	if ror.Reference != nil {
+		var ok bool
+		if ptr, ok = v.resolve(ptr, ror.Reference, "responses"); !ok || v.spec.Components == nil {
+			return nil, ""
+		}
+
+		return v.spec.Components.Responses[componentName(ptr)].Response, ptr
+	}
+
+	return ror.Response, ptr
+}
+
+// ValidateResponse checks response status, headers and body against the operation that matches request,
+// it returns *Error with all found violations.
+func (v *Validator) ValidateResponse(r *http.Request, status int, header http.Header, body []byte) error {
+	rt, found := v.FindRoute(r.Method, r.URL.EscapedPath())
+	if !found {
+		return ErrNotFound
+	}
+
+	e := &Error{Status: http.StatusInternalServerError}
+
+	resp, ptr := v.findResponse(rt, status)
+	if resp == nil {
+		e.Violations = append(e.Violations, Violation{Message: "undocumented response status: " + strconv.Itoa(status)})
+
+		return e
+	}
+
+	if err := v.validateResponseHeaders(resp, ptr, header, e); err != nil {
+		return err
+	}
+
+	if err := v.validateResponseBody(r, resp, ptr, header, body, e); err != nil {
+		return err
+	}
+
+	if len(e.Violations) > 0 {
+		return e
+	}
+
+	return nil
+}
+
+func (v *Validator) validateResponseHeaders(resp *openapi31.Response, ptr string, header http.Header, e *Error) error {
+	names := make([]string, 0, len(resp.Headers))
+
+	for name := range resp.Headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		// Content-Type header is described by response content.
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+
+		hptr := ptr + "/headers/" + internal.EscapeJSONPointer(name)
+		hr := resp.Headers[name]
+		h := hr.Header
+
+		if hr.Reference != nil {
+			var ok bool
+			if hptr, ok = v.resolve(hptr, hr.Reference, "headers"); !ok || v.spec.Components == nil {
+				continue
+			}
+
+			h = v.spec.Components.Headers[componentName(hptr)].Header
+		}
+
+		if h == nil {
+			continue
+		}
+
+		values := header.Values(name)
+		if len(values) == 0 {
+			if h.Required != nil && *h.Required {
+				e.Violations = append(e.Violations, Violation{
+					In: openapi.InHeader, Name: http.CanonicalHeaderKey(name), Message: "missing required header",
+				})
+			}
+
+			continue
+		}
+
+		if h.Schema == nil {
+			continue
+		}
+
+		explode := h.Explode != nil && *h.Explode
+		value := v.decodeDelimited(strings.Join(values, ","), ",", explode, h.Schema, strings.TrimSpace)
+
+		if err := v.validate(e, openapi.InHeader, http.CanonicalHeaderKey(name), hptr+"/schema", value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) validateResponseBody(
+	r *http.Request,
+	resp *openapi31.Response,
+	ptr string,
+	header http.Header,
+	body []byte,
+	e *Error,
+) error {
+	if len(resp.Content) == 0 {
+		if len(body) > 0 {
+			e.Violations = append(e.Violations, Violation{In: openapi.InBody, Message: "unexpected response body"})
+		}
+
+		return nil
+	}
+
+	if len(body) == 0 {
+		if r.Method != http.MethodHead {
+			e.Violations = append(e.Violations, Violation{In: openapi.InBody, Message: "missing response body"})
+		}
+
+		return nil
+	}
+
+	ct := header.Get("Content-Type")
+
+	mt, mtName, found := findMediaType(resp.Content, ct)
+	if !found {
+		e.Violations = append(e.Violations, Violation{
+			In: openapi.InHeader, Name: "Content-Type", Message: "unexpected content type: " + strconv.Quote(ct),
+		})
+
+		return nil
+	}
+
+	if mt.Schema == nil {
+		return nil
+	}
+
+	value, err := v.decodeBody(body, ct, mt)
+	if err != nil {
+		e.Violations = append(e.Violations, Violation{In: openapi.InBody, Message: err.Error()})
+
+		return nil //nolint:nilerr // Decoding error is reported as violation.
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	return v.validate(e, openapi.InBody, "", ptr+"/content/"+internal.EscapeJSONPointer(mtName)+"/schema", *value)
+}
@@ -0,0 +1,167 @@
+package validator
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// resolveSchema follows local references to component schemas.
+func (v *Validator) resolveSchema(s interface{}) map[string]interface{} {
+	for i := 0; i < 32; i++ {
+		m, ok := s.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/components/schemas/") || v.spec.Components == nil {
+			return m
+		}
+
+		s = v.spec.Components.Schemas[componentName(ref)]
+	}
+
+	return nil
+}
+
+// members returns schema with its allOf/anyOf/oneOf members, resolved recursively.
+func (v *Validator) members(s interface{}) []map[string]interface{} {
+	m := v.resolveSchema(s)
+	if m == nil {
+		return nil
+	}
+
+	res := []map[string]interface{}{m}
+
+	for _, k := range []string{"allOf", "anyOf", "oneOf"} {
+		items, _ := m[k].([]interface{}) //nolint:errcheck // Nil is a valid result.
+		for _, item := range items {
+			res = append(res, v.members(item)...)
+		}
+	}
+
+	return res
+}
+
+// types returns JSON types allowed by schema.
+func (v *Validator) types(s interface{}) map[string]bool {
+	res := map[string]bool{}
+
+	for _, m := range v.members(s) {
+		switch t := m["type"].(type) {
+		case string:
+			res[t] = true
+		case []interface{}:
+			for _, tt := range t {
+				if ts, ok := tt.(string); ok {
+					res[ts] = true
+				}
+			}
+		}
+
+		if _, ok := m["properties"]; ok {
+			res["object"] = true
+		}
+
+		if _, ok := m["items"]; ok {
+			res["array"] = true
+		}
+	}
+
+	return res
+}
+
+// properties returns property schemas.
+func (v *Validator) properties(s interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+
+	for _, m := range v.members(s) {
+		props, _ := m["properties"].(map[string]interface{}) //nolint:errcheck // Nil is a valid result.
+		for name, ps := range props {
+			if _, ok := res[name]; !ok {
+				res[name] = ps
+			}
+		}
+	}
+
+	return res
+}
+
+// property returns schema of an object property, or nil if property is not defined.
+func (v *Validator) property(s interface{}, name string) interface{} {
+	for _, m := range v.members(s) {
+		if props, ok := m["properties"].(map[string]interface{}); ok {
+			if ps, ok := props[name]; ok {
+				return ps
+			}
+		}
+	}
+
+	return nil
+}
+
+// keyword returns a value of a keyword from schema or its members.
+func (v *Validator) keyword(s interface{}, key string) interface{} {
+	for _, m := range v.members(s) {
+		if val, ok := m[key]; ok {
+			return val
+		}
+	}
+
+	return nil
+}
+
+// coerce converts a string to a type allowed by schema, invalid values are kept as strings to fail validation.
+func (v *Validator) coerce(s interface{}, raw string) interface{} {
+	t := v.types(s)
+
+	if t["integer"] {
+		if _, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return json.Number(raw)
+		}
+	}
+
+	if t["number"] {
+		if _, err := strconv.ParseFloat(raw, 64); err == nil && json.Valid([]byte(raw)) {
+			return json.Number(raw)
+		}
+	}
+
+	if t["boolean"] && (raw == "true" || raw == "false") {
+		return raw == "true"
+	}
+
+	if t["null"] && !t["string"] && (raw == "" || raw == "null") {
+		return nil
+	}
+
+	return raw
+}
+
+func (v *Validator) coerceArray(s interface{}, items []string) []interface{} {
+	is := v.keyword(s, "items")
+	res := make([]interface{}, 0, len(items))
+
+	for _, item := range items {
+		res = append(res, v.coerce(is, item))
+	}
+
+	return res
+}
+
+func (v *Validator) coerceObject(s interface{}, props map[string]string) map[string]interface{} {
+	ap := v.keyword(s, "additionalProperties")
+	res := make(map[string]interface{}, len(props))
+
+	for name, val := range props {
+		ps := v.property(s, name)
+		if ps == nil {
+			ps = ap
+		}
+
+		res[name] = v.coerce(ps, val)
+	}
+
+	return res
+}
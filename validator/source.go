@@ -0,0 +1,143 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	jsonschemago "github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+var errInvalidSchema = errors.New("invalid schema")
+
+// schemaSource provides JSON Schemas of the original spec revision by JSON Pointers.
+//
+// Schemas are converted with ToJSONSchema of the revision package, the same way as reflector
+// provides them to openapi.JSONSchemaWalker callbacks.
+type schemaSource struct {
+	doc   map[string]interface{}
+	draft *jsonschema.Draft
+
+	convert func(raw interface{}) (jsonschemago.SchemaOrBool, error)
+}
+
+func newSchemaSource(s openapi.SpecSchema, converted *openapi31.Spec) (*schemaSource, error) {
+	src := &schemaSource{}
+
+	switch spec := s.(type) {
+	case *openapi3.Spec:
+		src.draft = jsonschema.Draft7
+		src.convert = func(raw interface{}) (jsonschemago.SchemaOrBool, error) {
+			data, err := json.Marshal(raw)
+			if err != nil {
+				return jsonschemago.SchemaOrBool{}, err
+			}
+
+			var sor openapi3.SchemaOrRef
+			if err := sor.UnmarshalJSON(data); err != nil {
+				return jsonschemago.SchemaOrBool{}, err
+			}
+
+			return sor.ToJSONSchema(spec), nil
+		}
+	default:
+		s = converted
+		src.draft = jsonschema.Draft2020
+		src.convert = func(raw interface{}) (jsonschemago.SchemaOrBool, error) {
+			switch r := raw.(type) {
+			case bool:
+				return jsonschemago.SchemaOrBool{TypeBoolean: &r}, nil
+			case map[string]interface{}:
+				return openapi31.ToJSONSchema(r, converted), nil
+			default:
+				return jsonschemago.SchemaOrBool{}, fmt.Errorf("%w: %T", errInvalidSchema, raw)
+			}
+		}
+	}
+
+	doc, err := internal.ToDocument(s)
+	if err != nil {
+		return nil, err
+	}
+
+	src.doc = doc
+
+	return src, nil
+}
+
+// schema returns JSON Schema at pointer of spec document.
+func (src *schemaSource) schema(ptr string) (js jsonschemago.SchemaOrBool, err error) {
+	raw, err := internal.ResolvePointer(src.doc, "#"+ptr)
+	if err != nil {
+		return js, err
+	}
+
+	// ToJSONSchema panics on broken references.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("converting schema %s: %v", ptr, r)
+		}
+	}()
+
+	return src.convert(raw)
+}
+
+// base returns pointer of schema in spec document, schemas that refer to components are located in components.
+func (src *schemaSource) base(ptr string) string {
+	raw, err := internal.ResolvePointer(src.doc, "#"+ptr)
+	if err != nil {
+		return ptr
+	}
+
+	m, _ := raw.(map[string]interface{}) //nolint:errcheck // Nil is valid.
+
+	if ref, ok := m["$ref"].(string); ok && strings.HasPrefix(ref, "#/components/schemas/") {
+		return strings.TrimPrefix(ref, "#")
+	}
+
+	return ptr
+}
+
+// compiledSchema is a compiled JSON Schema with its pointer in spec document.
+type compiledSchema struct {
+	*jsonschema.Schema
+	base string
+}
+
+func (src *schemaSource) compile(ptr string, js jsonschemago.SchemaOrBool) (compiledSchema, error) {
+	data, err := json.Marshal(js)
+	if err != nil {
+		return compiledSchema{}, err
+	}
+
+	c := jsonschema.NewCompiler()
+	c.Draft = src.draft
+	c.AssertFormat = true
+
+	if err := c.AddResource(schemaURL, strings.NewReader(string(data))); err != nil {
+		return compiledSchema{}, fmt.Errorf("loading schema %s: %w", ptr, err)
+	}
+
+	s, err := c.Compile(schemaURL)
+	if err != nil {
+		return compiledSchema{}, fmt.Errorf("compiling schema %s: %w", ptr, err)
+	}
+
+	return compiledSchema{Schema: s, base: src.base(ptr)}, nil
+}
+
+// location maps keyword location of compiled schema to a pointer in spec document,
+// ToJSONSchema embeds referenced component schemas under the same "/components/schemas" path.
+func (cs compiledSchema) location(keyword string) string {
+	if strings.HasPrefix(keyword, "/components/schemas/") {
+		return keyword
+	}
+
+	return cs.base + keyword
+}
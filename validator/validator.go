@@ -0,0 +1,443 @@
+// Package validator provides runtime validation of HTTP requests against OpenAPI spec.
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	jsonschemago "github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/convert"
+	"github.com/swaggest/openapi-go/internal"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// ErrNotFound is returned when request does not match any operation of the spec.
+var ErrNotFound = errors.New("operation not found")
+
+// schemaURL is a resource name of a schema for schema compiler.
+const schemaURL = "schema.json"
+
+// Validator checks HTTP requests against operations of OpenAPI spec.
+//
+// Spec is captured at construction, so validator should be created after all operations are added to reflector.
+type Validator struct {
+	// ErrorHandler is called to write a response for an invalid request,
+	// by default problem details (RFC 9457) are written with WriteProblem.
+	ErrorHandler func(rw http.ResponseWriter, r *http.Request, err *Error)
+
+	spec   *openapi31.Spec
+	source *schemaSource
+	routes []route
+
+	// walked contains schemas provided by reflector for operation contexts by JSON Pointers in spec.
+	walked map[string]jsonschemago.SchemaOrBool
+
+	mu      sync.Mutex
+	schemas map[string]compiledSchema
+}
+
+// Route describes an operation matched by a request.
+type Route struct {
+	Method     string
+	Pattern    string
+	Pointer    string
+	PathItem   *openapi31.PathItem
+	Operation  *openapi31.Operation
+	PathParams map[string]string
+}
+
+type route struct {
+	pattern  string
+	re       *regexp.Regexp
+	names    []string
+	literals int
+	item     openapi31.PathItem
+}
+
+var pathParam = regexp.MustCompile(`{([^}]+)}`)
+
+// New creates request validator for OpenAPI 3.0 or 3.1 spec.
+//
+// Schemas are checked in the form provided by ToJSONSchema of the spec revision package,
+// and violation locations are JSON Pointers into the spec document.
+func New(s openapi.SpecSchema) (*Validator, error) {
+	spec, err := convert.AsOpenAPI31(s)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := newSchemaSource(s, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Validator{
+		spec:    spec,
+		source:  source,
+		walked:  map[string]jsonschemago.SchemaOrBool{},
+		schemas: map[string]compiledSchema{},
+	}
+
+	if spec.Paths != nil {
+		for pattern, item := range spec.Paths.MapOfPathItemValues {
+			v.routes = append(v.routes, newRoute(pattern, item))
+		}
+	}
+
+	// Routes with less parameters and more literal characters take precedence.
+	sort.Slice(v.routes, func(i, j int) bool {
+		ri, rj := v.routes[i], v.routes[j]
+
+		if len(ri.names) != len(rj.names) {
+			return len(ri.names) < len(rj.names)
+		}
+
+		if ri.literals != rj.literals {
+			return ri.literals > rj.literals
+		}
+
+		return ri.pattern < rj.pattern
+	})
+
+	return v, nil
+}
+
+// NewFromReflector creates request validator for operations added to reflector.
+//
+// Schemas of parameters, request and response bodies and response headers of given operations are taken from
+// reflector with JSONSchemaWalker (WalkRequestJSONSchemas and WalkResponseJSONSchemas), other schemas are
+// taken from reflected spec.
+func NewFromReflector(r openapi.Reflector, operations ...openapi.OperationContext) (*Validator, error) {
+	v, err := New(r.SpecSchema())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, oc := range operations {
+		if err := v.walk(r, oc); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+func newRoute(pattern string, item openapi31.PathItem) route {
+	r := route{pattern: pattern, item: item}
+	expr := "^"
+	pos := 0
+
+	for _, loc := range pathParam.FindAllStringSubmatchIndex(pattern, -1) {
+		expr += regexp.QuoteMeta(pattern[pos:loc[0]]) + "([^/]+)"
+		r.literals += loc[0] - pos
+		r.names = append(r.names, pattern[loc[2]:loc[3]])
+		pos = loc[1]
+	}
+
+	expr += regexp.QuoteMeta(pattern[pos:]) + "$"
+	r.literals += len(pattern) - pos
+	r.re = regexp.MustCompile(expr)
+
+	return r
+}
+
+// Spec returns OpenAPI 3.1 representation of validated spec.
+func (v *Validator) Spec() *openapi31.Spec {
+	return v.spec
+}
+
+// FindRoute finds an operation by method and URL path.
+func (v *Validator) FindRoute(method, path string) (Route, bool) {
+	for i := range v.routes {
+		r := &v.routes[i]
+
+		m := r.re.FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+
+		op, err := r.item.Operation(method)
+		if err != nil || op == nil {
+			continue
+		}
+
+		rt := r.route(method, op)
+		rt.PathParams = make(map[string]string, len(r.names))
+
+		for j, name := range r.names {
+			rt.PathParams[name] = m[j+1]
+		}
+
+		return rt, true
+	}
+
+	return Route{}, false
+}
+
+func (r *route) route(method string, op *openapi31.Operation) Route {
+	return Route{
+		Method:    strings.ToUpper(method),
+		Pattern:   r.pattern,
+		Pointer:   "/paths/" + internal.EscapeJSONPointer(r.pattern) + "/" + strings.ToLower(method),
+		PathItem:  &r.item,
+		Operation: op,
+	}
+}
+
+// Middleware validates requests before passing them to the next handler.
+//
+// Requests that do not match any operation are passed through.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		err := v.ValidateRequest(r)
+		if err == nil || errors.Is(err, ErrNotFound) {
+			next.ServeHTTP(rw, r)
+
+			return
+		}
+
+		var verr *Error
+		if !errors.As(err, &verr) {
+			verr = &Error{Status: http.StatusBadRequest, Violations: []Violation{{Message: err.Error()}}}
+		}
+
+		if v.ErrorHandler != nil {
+			v.ErrorHandler(rw, r, verr)
+		} else {
+			WriteProblem(rw, r, verr)
+		}
+	})
+}
+
+// ValidateRequest checks request parameters and body, it returns *Error for invalid request.
+//
+// Request body is restored after reading, so it can be consumed by the handler.
+func (v *Validator) ValidateRequest(r *http.Request) error {
+	rt, found := v.FindRoute(r.Method, r.URL.EscapedPath())
+	if !found {
+		return ErrNotFound
+	}
+
+	e := &Error{Status: http.StatusBadRequest}
+
+	if err := v.validateParameters(r, rt, e); err != nil {
+		return err
+	}
+
+	if err := v.validateBody(r, rt, e); err != nil {
+		return err
+	}
+
+	if len(e.Violations) > 0 {
+		return e
+	}
+
+	return nil
+}
+
+// schema compiles a schema found by JSON Pointer in spec.
+func (v *Validator) schema(ptr string) (compiledSchema, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if s, ok := v.schemas[ptr]; ok {
+		return s, nil
+	}
+
+	js, ok := v.walked[ptr]
+	if !ok {
+		var err error
+
+		if js, err = v.source.schema(ptr); err != nil {
+			return compiledSchema{}, err
+		}
+	}
+
+	s, err := v.source.compile(ptr, js)
+	if err != nil {
+		return compiledSchema{}, err
+	}
+
+	v.schemas[ptr] = s
+
+	return s, nil
+}
+
+// validate checks value with a schema found by JSON Pointer and adds violations to e.
+func (v *Validator) validate(e *Error, in openapi.In, name string, ptr string, value interface{}) error {
+	s, err := v.schema(ptr)
+	if err != nil {
+		return err
+	}
+
+	err = s.Validate(value)
+	if err == nil {
+		return nil
+	}
+
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+
+	leaves := internal.LeafErrors(ve)
+
+	sort.SliceStable(leaves, func(i, j int) bool {
+		return leaves[i].InstanceLocation < leaves[j].InstanceLocation
+	})
+
+	for _, l := range leaves {
+		vl := Violation{
+			In:       in,
+			Name:     name,
+			Pointer:  l.InstanceLocation,
+			Location: l.AbsoluteKeywordLocation,
+			Message:  l.Message,
+		}
+
+		if i := strings.IndexByte(vl.Location, '#'); i >= 0 {
+			vl.Location = vl.Location[i+1:]
+
+			if u, err := url.PathUnescape(vl.Location); err == nil {
+				vl.Location = u
+			}
+
+			vl.Location = s.location(vl.Location)
+		}
+
+		e.Violations = append(e.Violations, vl)
+	}
+
+	return nil
+}
+
+// resolve follows local reference to a component.
+func (v *Validator) resolve(ptr string, ref *openapi31.Reference, section string) (string, bool) {
+	if ref == nil {
+		return ptr, true
+	}
+
+	prefix := "#/components/" + section + "/"
+	if !strings.HasPrefix(ref.Ref, prefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(ref.Ref, "#"), true
+}
+
+func (v *Validator) validateBody(r *http.Request, rt Route, e *Error) error {
+	rbr := rt.Operation.RequestBody
+	if rbr == nil {
+		return nil
+	}
+
+	ptr := rt.Pointer + "/requestBody"
+	rb := rbr.RequestBody
+
+	if rbr.Reference != nil {
+		var ok bool
+		if ptr, ok = v.resolve(ptr, rbr.Reference, "requestBodies"); !ok || v.spec.Components == nil {
+			return nil
+		}
+
+		rb = v.spec.Components.RequestBodies[componentName(ptr)].RequestBody
+	}
+
+	if rb == nil {
+		return nil
+	}
+
+	var body []byte
+
+	if r.Body != nil && r.Body != http.NoBody {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return fmt.Errorf("reading request body: %w", err)
+		}
+
+		if err := r.Body.Close(); err != nil {
+			return err
+		}
+
+		body = b
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if len(body) == 0 {
+		if rb.Required != nil && *rb.Required {
+			e.Violations = append(e.Violations, Violation{In: openapi.InBody, Message: "missing required request body"})
+		}
+
+		return nil
+	}
+
+	ct := r.Header.Get("Content-Type")
+
+	mt, mtName, found := findMediaType(rb.Content, ct)
+	if !found {
+		e.Status = http.StatusUnsupportedMediaType
+		e.Violations = append(e.Violations, Violation{
+			In: openapi.InBody, Message: "unsupported content type: " + strconv.Quote(ct),
+		})
+
+		return nil
+	}
+
+	if mt.Schema == nil {
+		return nil
+	}
+
+	sptr := ptr + "/content/" + internal.EscapeJSONPointer(mtName) + "/schema"
+
+	value, err := v.decodeBody(body, ct, mt)
+	if err != nil {
+		e.Violations = append(e.Violations, Violation{In: openapi.InBody, Message: err.Error()})
+
+		return nil //nolint:nilerr // Decoding error is reported as violation.
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	return v.validate(e, openapi.InBody, "", sptr, *value)
+}
+
+func componentName(ptr string) string {
+	return internal.UnescapeJSONPointer(ptr[strings.LastIndex(ptr, "/")+1:])
+}
+
+// WriteProblem writes validation error as RFC 9457 problem details.
+func WriteProblem(rw http.ResponseWriter, r *http.Request, err *Error) {
+	p := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(err.Status),
+		Status:   err.Status,
+		Detail:   "request validation failed",
+		Instance: r.URL.RequestURI(),
+		Errors:   err.Violations,
+	}
+
+	data, e := json.Marshal(p)
+	if e != nil {
+		http.Error(rw, e.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/problem+json")
+	rw.WriteHeader(err.Status)
+	_, _ = rw.Write(data) //nolint:errcheck // Nothing to do with write error.
+}
@@ -0,0 +1,225 @@
+package validator_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+	"github.com/swaggest/openapi-go/validator"
+)
+
+type updateReq struct {
+	ID     int      `path:"id" minimum:"1"`
+	Tags   []string `query:"tags" collectionFormat:"csv"`
+	Limit  int      `query:"limit" maximum:"100"`
+	Filter struct {
+		Status string `query:"status" enum:"active,inactive"`
+	} `query:"filter"`
+	Trace string `header:"X-Trace" required:"true"`
+	Name  string `json:"name" required:"true" minLength:"3"`
+	Size  *int   `json:"size"`
+}
+
+func newHandler(t *testing.T, r openapi.Reflector) http.Handler {
+	t.Helper()
+
+	oc, err := r.NewOperationContext(http.MethodPut, "/items/{id}")
+	require.NoError(t, err)
+
+	oc.AddReqStructure(new(updateReq))
+	require.NoError(t, r.AddOperation(oc))
+
+	v, err := validator.NewFromReflector(r, oc)
+	require.NoError(t, err)
+
+	return v.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		_, err = rw.Write(body)
+		require.NoError(t, err)
+	}))
+}
+
+func TestValidator_Middleware(t *testing.T) {
+	for name, r := range map[string]openapi.Reflector{
+		"openapi3":  openapi3.NewReflector(),
+		"openapi31": openapi31.NewReflector(),
+	} {
+		r := r
+
+		t.Run(name, func(t *testing.T) {
+			h := newHandler(t, r)
+
+			req := httptest.NewRequest(http.MethodPut, "/items/12?tags=a,b&limit=10&filter[status]=active",
+				strings.NewReader(`{"name":"foo","size":null}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Trace", "abc")
+
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+			assert.Equal(t, http.StatusOK, rw.Code)
+			assert.Equal(t, `{"name":"foo","size":null}`, rw.Body.String())
+
+			req = httptest.NewRequest(http.MethodPut, "/items/0?limit=1000&filter[status]=unknown",
+				strings.NewReader(`{"name":"f"}`))
+			req.Header.Set("Content-Type", "application/json")
+
+			rw = httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+			assert.Equal(t, http.StatusBadRequest, rw.Code)
+			assert.Equal(t, "application/problem+json", rw.Header().Get("Content-Type"))
+			assertjson.Equal(t, []byte(`{
+			  "type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed",
+			  "instance":"/items/0?limit=1000&filter[status]=unknown",
+			  "errors":[
+				{"in":"query","name":"limit","location":"<ignore-diff>","message":"must be <= 100 but found 1000"},
+				{"in":"query","name":"filter","pointer":"/status","location":"<ignore-diff>","message":"value must be one of \"active\", \"inactive\""},
+				{"in":"path","name":"id","location":"<ignore-diff>","message":"must be >= 1 but found 0"},
+				{"in":"header","name":"X-Trace","message":"missing required parameter"},
+				{"in":"body","pointer":"/name","location":"<ignore-diff>","message":"length must be >= 3, but got 1"}
+			  ]
+			}`), rw.Body.Bytes(), rw.Body.String())
+
+			req = httptest.NewRequest(http.MethodPut, "/items/1", strings.NewReader(`name=foo`))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("X-Trace", "abc")
+
+			rw = httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+			assert.Equal(t, http.StatusUnsupportedMediaType, rw.Code)
+
+			req = httptest.NewRequest(http.MethodGet, "/unknown", nil)
+			rw = httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+			assert.Equal(t, http.StatusOK, rw.Code)
+		})
+	}
+}
+
+func TestValidator_ValidateRequest_styles(t *testing.T) {
+	s := openapi31.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.1.0
+info: {title: Styles, version: 1.0.0}
+paths:
+  /points/{coords}/{ids}:
+    post:
+      parameters:
+        - {name: coords, in: path, required: true, style: matrix, explode: true,
+           schema: {type: object, properties: {lat: {type: integer}, lon: {type: integer}}, required: [lat, lon]}}
+        - {name: ids, in: path, required: true, style: label,
+           schema: {type: array, items: {type: integer}}}
+        - {name: colors, in: query, style: pipeDelimited, explode: false,
+           schema: {type: array, items: {enum: [red, green]}}}
+        - {name: flag, in: cookie, schema: {type: boolean}}
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              additionalProperties: false
+              properties:
+                qty: {type: integer, minimum: 1}
+      responses:
+        "204": {description: OK}
+      x-forbid-unknown-query: true
+`)))
+
+	v, err := validator.New(&s)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/points/;lat=1;lon=2/.1,2?colors=red|green", strings.NewReader(`qty=2`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: "flag", Value: "true"})
+	require.NoError(t, v.ValidateRequest(req))
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "qty=2", string(body))
+
+	req = httptest.NewRequest(http.MethodPost, "/points/;lat=1/.1,a?colors=red|blue&other=1", strings.NewReader(`qty=0&foo=bar`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: "flag", Value: "yes"})
+
+	err = v.ValidateRequest(req)
+	require.Error(t, err)
+
+	var verr *validator.Error
+	require.ErrorAs(t, err, &verr)
+
+	var messages []string
+	for _, vl := range verr.Violations {
+		messages = append(messages, vl.String())
+	}
+
+	assert.Equal(t, []string{
+		"path.coords: missing properties: 'lon'",
+		"path.ids/1: expected integer, but got string",
+		"query.colors/1: value must be one of \"red\", \"green\"",
+		"cookie.flag: expected boolean, but got string",
+		"query.other: unknown parameter",
+		"body: additionalProperties 'foo' not allowed",
+		"body/qty: must be >= 1 but found 0",
+	}, messages)
+
+	assert.ErrorIs(t, v.ValidateRequest(httptest.NewRequest(http.MethodGet, "/points/a/b", nil)), validator.ErrNotFound)
+}
+
+func TestValidator_ValidateRequest_openapi3(t *testing.T) {
+	s := openapi3.Spec{}
+	require.NoError(t, s.UnmarshalYAML([]byte(`
+openapi: 3.0.3
+info: {title: Things, version: 1.0.0}
+paths:
+  /things/{id}:
+    post:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer, minimum: 1, exclusiveMinimum: true}}
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Thing'}
+      responses:
+        "204": {description: OK}
+components:
+  schemas:
+    Thing:
+      type: object
+      properties:
+        count: {type: integer, maximum: 10, exclusiveMaximum: true}
+        label: {type: string, nullable: true}
+`)))
+
+	v, err := validator.New(&s)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/things/2", strings.NewReader(`{"count":9,"label":null}`))
+	req.Header.Set("Content-Type", "application/json")
+	require.NoError(t, v.ValidateRequest(req))
+
+	req = httptest.NewRequest(http.MethodPost, "/things/1", strings.NewReader(`{"count":10,"label":1}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	var verr *validator.Error
+	require.ErrorAs(t, v.ValidateRequest(req), &verr)
+
+	var locations []string
+	for _, vl := range verr.Violations {
+		locations = append(locations, vl.String()+" @ "+vl.Location)
+	}
+
+	assert.Equal(t, []string{
+		"path.id: must be > 1 but found 1 @ /paths/~1things~1{id}/post/parameters/0/schema/exclusiveMinimum",
+		"body/count: must be < 10 but found 10 @ /components/schemas/Thing/properties/count/exclusiveMaximum",
+		"body/label: expected string or null, but got number @ /components/schemas/Thing/properties/label/type",
+	}, locations)
+}
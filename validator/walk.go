@@ -0,0 +1,144 @@
+package validator
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	jsonschemago "github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/openapi-go"
+	"github.com/swaggest/openapi-go/internal"
+)
+
+// walk collects schemas of operation context from reflector by JSON Pointers of their positions in spec.
+func (v *Validator) walk(r openapi.Reflector, oc openapi.OperationContext) error {
+	method, pattern, _, err := openapi.SanitizeMethodPath(oc.Method(), oc.PathPattern())
+	if err != nil {
+		return err
+	}
+
+	rt, found := v.routeByPattern(method, pattern)
+	if !found {
+		return fmt.Errorf("%w: %s %s", ErrNotFound, strings.ToUpper(method), pattern)
+	}
+
+	params := v.parameters(rt)
+
+	for _, cu := range oc.Request() {
+		err := r.WalkRequestJSONSchemas(method, cu, func(in openapi.In, name string, schema *jsonschemago.SchemaOrBool, _ bool) error {
+			if schema == nil {
+				return nil
+			}
+
+			switch in { //nolint:exhaustive // Form data is validated as a whole body.
+			case openapi.InBody:
+				if ptr := v.requestBodyPointer(rt); ptr != "" {
+					v.walked[ptr+"/content/"+internal.EscapeJSONPointer(mimeJSON)+"/schema"] = *schema
+				}
+			case openapi.InFormData:
+			default:
+				for _, p := range params {
+					if openapi.In(p.In) == in && p.Name == name {
+						v.walked[paramSchemaPointer(p)] = *schema
+					}
+				}
+			}
+
+			return nil
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", strings.ToUpper(method), pattern, err)
+		}
+	}
+
+	for _, cu := range oc.Response() {
+		resp, ptr := v.responseByKey(rt, responseKey(cu))
+		if resp == nil {
+			continue
+		}
+
+		err := r.WalkResponseJSONSchemas(cu, func(in openapi.In, name string, schema *jsonschemago.SchemaOrBool, _ bool) error {
+			if schema == nil {
+				return nil
+			}
+
+			if in == openapi.InHeader {
+				for h := range resp.Headers {
+					if strings.EqualFold(h, name) {
+						v.walked[ptr+"/headers/"+internal.EscapeJSONPointer(h)+"/schema"] = *schema
+					}
+				}
+
+				return nil
+			}
+
+			for ct := range resp.Content {
+				v.walked[ptr+"/content/"+internal.EscapeJSONPointer(ct)+"/schema"] = *schema
+			}
+
+			return nil
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", strings.ToUpper(method), pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// route finds an operation by method and path pattern.
+func (v *Validator) routeByPattern(method, pattern string) (Route, bool) {
+	for i := range v.routes {
+		r := &v.routes[i]
+		if r.pattern != pattern {
+			continue
+		}
+
+		op, err := r.item.Operation(method)
+		if err != nil || op == nil {
+			return Route{}, false
+		}
+
+		return r.route(method, op), true
+	}
+
+	return Route{}, false
+}
+
+// requestBodyPointer returns JSON Pointer to request body of an operation, or empty string if there is none.
+func (v *Validator) requestBodyPointer(rt Route) string {
+	rbr := rt.Operation.RequestBody
+	if rbr == nil {
+		return ""
+	}
+
+	ptr, ok := v.resolve(rt.Pointer+"/requestBody", rbr.Reference, "requestBodies")
+	if !ok {
+		return ""
+	}
+
+	return ptr
+}
+
+func paramSchemaPointer(p param) string {
+	if p.Schema == nil {
+		return p.ptr + "/content/" + internal.EscapeJSONPointer(mimeJSON) + "/schema"
+	}
+
+	return p.ptr + "/schema"
+}
+
+// responseKey returns a key of response in operation responses for a content unit.
+func responseKey(cu openapi.ContentUnit) string {
+	switch {
+	case cu.IsDefault:
+		return "default"
+	case cu.HTTPStatus == 0:
+		return strconv.Itoa(http.StatusOK)
+	case cu.HTTPStatus < 6:
+		return strconv.Itoa(cu.HTTPStatus) + "XX"
+	default:
+		return strconv.Itoa(cu.HTTPStatus)
+	}
+}